@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/Predixus/orca-ztbus-prep/loader"
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/schollz/progressbar/v3"
+)
+
+// runAudit applies the plausibility rules to every trip already in the
+// database and records the violations. Stored telemetry is not modified.
+func runAudit(args []string) error {
+	fs := flag.NewFlagSet("audit", flag.ExitOnError)
+	platform := fs.String(
+		"platform",
+		"",
+		"Data platform to use as the data layer (e.g., postgresql)",
+	)
+	connStr := fs.String("connStr", "", "Connection string to the datalayer")
+	rulesFile := fs.String(
+		"rules",
+		"",
+		"JSON plausibility rules file (defaults to the built in rules)",
+	)
+	fs.Parse(args)
+
+	if err := ValidateDatalayer(*platform); err != nil {
+		return fmt.Errorf("invalid platform: %w", err)
+	}
+	if err := ValidateConnStr(*connStr); err != nil {
+		return fmt.Errorf("invalid connection string: %w", err)
+	}
+	rules, err := loader.LoadPlausibilityRules(*rulesFile)
+	if err != nil {
+		return fmt.Errorf("invalid plausibility rules: %w", err)
+	}
+	check := loader.PlausibilityCheck{Rules: rules}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, *connStr)
+	if err != nil {
+		return fmt.Errorf("error creating connection pool: %v", err)
+	}
+	defer pool.Close()
+	q := postgres.New(pool)
+
+	trips, err := q.ListAllTrips(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list trips: %v", err)
+	}
+
+	stops, err := q.ListStops(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list stops: %v", err)
+	}
+	stopNames := make(map[int32]string, len(stops))
+	for _, s := range stops {
+		stopNames[s.ID] = s.Name
+	}
+
+	var summary loader.LoadSummary
+	bar := progressbar.Default(int64(len(trips)), "auditing trips")
+	for _, trip := range trips {
+		bar.Add(1)
+
+		rows, err := q.GetTelemetryByTrip(ctx, trip.ID)
+		if err != nil {
+			return fmt.Errorf("could not read telemetry for trip %s: %v", trip.Name, err)
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		report := check.Apply(trip.ID, postgres.TelemetryToTrip(rows, stopNames))
+		if err := loader.WritePlausibilityReport(ctx, pool, trip.ID, report); err != nil {
+			return fmt.Errorf("trip %s: %v", trip.Name, err)
+		}
+		if report.Total > 0 {
+			slog.Info("implausible telemetry", "trip", trip.Name, "violations", report.Total)
+		}
+		summary.AddPlausibility(report)
+	}
+
+	totals, err := q.SummarisePlausibilityViolations(ctx)
+	if err != nil {
+		return fmt.Errorf("could not summarise plausibility violations: %v", err)
+	}
+	fmt.Fprintf(
+		os.Stdout,
+		"audited %d trips, %d with violations, %d implausible values\n",
+		summary.PlausibilityTrips,
+		summary.ImplausibleTrips,
+		summary.ImplausibleValues,
+	)
+	for _, t := range totals {
+		fmt.Fprintf(
+			os.Stdout,
+			"  %-28s %-10s %8d values in %d trips\n",
+			t.Field,
+			t.Kind,
+			t.Violations,
+			t.Trips,
+		)
+	}
+	return nil
+}
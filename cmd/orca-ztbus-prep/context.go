@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/loader"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// runImportContext imports a time indexed CSV file, e.g. weather
+// observations, as a context source
+func runImportContext(args []string) error {
+	fs := flag.NewFlagSet("import-context", flag.ExitOnError)
+	platform := fs.String(
+		"platform",
+		"",
+		"Data platform to use as the data layer (e.g., postgresql)",
+	)
+	connStr := fs.String("connStr", "", "Connection string to the datalayer")
+	file := fs.String("file", "", "CSV file to import")
+	source := fs.String("source", "", "Name of the context source (default: the file name)")
+	timeCol := fs.String("time-col", "", "Column holding the time of each row")
+	valueCols := fs.String(
+		"value-cols",
+		"",
+		"Columns to import (comma separated, default: all but the time column)",
+	)
+	timeFormat := fs.String(
+		"time-format",
+		"",
+		"Go layout of the time column (default: detect RFC3339, ISO dates and times, YYYYMMDDHHmm or unix seconds)",
+	)
+	timezone := fs.String("timezone", "UTC", "Time zone of times without an offset")
+	delimiter := fs.String("delimiter", ",", "Field delimiter, a single character or \\t")
+	fs.Parse(args)
+
+	if err := ValidateDatalayer(*platform); err != nil {
+		return fmt.Errorf("invalid platform: %w", err)
+	}
+	if err := ValidateConnStr(*connStr); err != nil {
+		return fmt.Errorf("invalid connection string: %w", err)
+	}
+	if *file == "" {
+		return fmt.Errorf("-file is required")
+	}
+	if *timeCol == "" {
+		return fmt.Errorf("-time-col is required")
+	}
+	loc, err := time.LoadLocation(*timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone: %v", err)
+	}
+	if *delimiter == `\t` {
+		*delimiter = "\t"
+	}
+	if len([]rune(*delimiter)) != 1 {
+		return fmt.Errorf("-delimiter must be a single character, got '%s'", *delimiter)
+	}
+	if *source == "" {
+		*source = strings.TrimSuffix(filepath.Base(*file), filepath.Ext(*file))
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, *connStr)
+	if err != nil {
+		return fmt.Errorf("error creating connection pool: %v", err)
+	}
+	defer pool.Close()
+
+	summary, err := loader.ImportContext(ctx, pool, *file, *source, loader.ContextCSV{
+		TimeCol:    *timeCol,
+		ValueCols:  splitList(*valueCols),
+		TimeFormat: *timeFormat,
+		Location:   loc,
+		Delimiter:  []rune(*delimiter)[0],
+	})
+	if err != nil {
+		return err
+	}
+	summary.Print(os.Stdout)
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Predixus/orca-ztbus-prep/server"
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// runExport writes selected telemetry to an Arrow IPC file
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	platform := fs.String(
+		"platform",
+		"",
+		"Data platform to use as the data layer (e.g., postgresql)",
+	)
+	connStr := fs.String("connStr", "", "Connection string to the datalayer")
+	out := fs.String("out", "", "Arrow IPC file to write")
+	var sel server.TelemetrySelection
+	fs.StringVar(&sel.Trip, "trip", "", "Export the telemetry of this trip")
+	fs.StringVar(&sel.Bus, "bus", "", "Only export telemetry of this bus number")
+	fs.StringVar(&sel.Route, "route", "", "Only export telemetry of this route code")
+	fs.StringVar(&sel.From, "from", "", "Start of the time range (RFC3339 or YYYY-MM-DD)")
+	fs.StringVar(&sel.To, "to", "", "End of the time range (RFC3339 or YYYY-MM-DD)")
+	opts := server.DefaultExportOptions()
+	fs.IntVar(&opts.BatchRows, "batch-rows", opts.BatchRows, "Rows per record batch")
+	fs.StringVar(&opts.Compression, "compression", opts.Compression, "Buffer compression: none, lz4 or zstd")
+	fs.Parse(args)
+
+	if err := ValidateDatalayer(*platform); err != nil {
+		return fmt.Errorf("invalid platform: %w", err)
+	}
+	if err := ValidateConnStr(*connStr); err != nil {
+		return fmt.Errorf("invalid connection string: %w", err)
+	}
+	if *out == "" {
+		return fmt.Errorf("-out is required")
+	}
+	if err := opts.Validate(); err != nil {
+		return fmt.Errorf("invalid export: %w", err)
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, *connStr)
+	if err != nil {
+		return fmt.Errorf("error creating connection pool: %v", err)
+	}
+	defer pool.Close()
+
+	summary, err := server.ExportArrow(ctx, postgres.New(pool), sel, *out, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, "%s: %d rows in %d batches\n", *out, summary.Rows, summary.Batches)
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Predixus/orca-ztbus-prep/loader"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// runImportGtfs imports a GTFS feed as the timetable to compare stop events with
+func runImportGtfs(args []string) error {
+	fs := flag.NewFlagSet("import-gtfs", flag.ExitOnError)
+	platform := fs.String(
+		"platform",
+		"",
+		"Data platform to use as the data layer (e.g., postgresql)",
+	)
+	connStr := fs.String("connStr", "", "Connection string to the datalayer")
+	file := fs.String("file", "", "GTFS feed (zip) to import")
+	allRoutes := fs.Bool(
+		"all-routes",
+		false,
+		"Import the trips of all routes, not only those matching a ZTBus route",
+	)
+	fs.Parse(args)
+
+	if err := ValidateDatalayer(*platform); err != nil {
+		return fmt.Errorf("invalid platform: %w", err)
+	}
+	if err := ValidateConnStr(*connStr); err != nil {
+		return fmt.Errorf("invalid connection string: %w", err)
+	}
+	if *file == "" {
+		return fmt.Errorf("-file is required")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, *connStr)
+	if err != nil {
+		return fmt.Errorf("error creating connection pool: %v", err)
+	}
+	defer pool.Close()
+
+	summary, err := loader.ImportGtfs(ctx, pool, *file, *allRoutes)
+	if err != nil {
+		return err
+	}
+	summary.Print(os.Stdout)
+	return nil
+}
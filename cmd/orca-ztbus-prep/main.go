@@ -0,0 +1,409 @@
+// Command orca-ztbus-prep loads the ZTBus dataset into PostgreSQL and serves
+// it over HTTP, gRPC and Arrow Flight. It only parses flags; see the loader
+// and server packages to embed the load or the servers in another program.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/loader"
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// cli flags
+type cliFlags struct {
+	connStr  string
+	migrate  bool
+	postgis  bool
+	showHelp bool
+	platform string
+
+	// what to load and how, see loader.Options
+	opts loader.Options
+}
+
+// valid datalayers - as they are displayed
+var datalayerSuggestions = []string{
+	"postgresql",
+}
+var currentDatalayer = "postgresql"
+
+// templates for filling out connection string
+type (
+	ConnectionStrParser func(connectionStr string, example string) (map[string]string, error)
+	connStringTemplate  struct {
+		validationFunc ConnectionStrParser
+		exampleConnStr string
+	}
+)
+
+var connectionTemplates = map[string]connStringTemplate{
+	"postgresql": {
+		validationFunc: ParsePostgresURL,
+		exampleConnStr: "postgresql://<user>:<pass>@<localhost>:<port>/<db>?<setting=value>",
+	},
+}
+
+// validation functions
+func ValidateDatalayer(s string) error {
+	if s == "" {
+		return fmt.Errorf("select a datalayer")
+	}
+	for _, v := range datalayerSuggestions {
+		if s == v {
+			currentDatalayer = v
+			return nil
+		}
+	}
+	return fmt.Errorf("unsuported datalayer: %s", s)
+}
+
+func ValidateConnStr(s string) error {
+	if s == "" {
+		return errors.New("connection string cannot be empty")
+	}
+	template, ok := connectionTemplates[currentDatalayer]
+	if !ok { // should never occur
+		return fmt.Errorf("no template found for datalayer: %s", currentDatalayer)
+	}
+	_, err := template.validationFunc(s, template.exampleConnStr)
+	return err
+}
+
+func ValidatePort(s string) error {
+	if s == "" {
+		return errors.New("you have to select a port number")
+	}
+
+	// try to lookup the port to validate it
+	if _, err := net.LookupPort("tcp", s); err != nil {
+		return fmt.Errorf("invalid port number '%s' (must be between 1-65535)", s)
+	}
+
+	// check if port is already in use
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", s))
+	if err != nil {
+		return fmt.Errorf("port %s is already in use", s)
+	}
+	listener.Close()
+
+	return nil
+}
+
+func ValidateDataDir(d string) error {
+	if d == "" {
+		return errors.New("dataDir cannot be empty")
+	}
+	_, err := os.Stat(d)
+	if err != nil {
+		return fmt.Errorf("issue finding the data folder: %v", err)
+	}
+	_, err = os.Stat(filepath.Join(d, "/metaData.csv"))
+	if err != nil {
+		return fmt.Errorf("`metaData.csv` file not found in the provided folder: %v", err)
+	}
+	return nil
+}
+
+func parseFlags() cliFlags {
+	flags := cliFlags{opts: loader.DefaultOptions()}
+
+	// connection string
+	flag.StringVar(
+		&flags.platform,
+		"platform",
+		"",
+		"Data platform to use as the data layer (e.g., postgresql)",
+	)
+	flag.StringVar(&flags.connStr, "connStr", "", "Connection string to the datalayer")
+	flag.BoolVar(&flags.showHelp, "help", false, "Show help")
+	flag.BoolVar(
+		&flags.migrate,
+		"migrate",
+		false,
+		"Migrate the orca db prior to launching orca. Will need to be run at least once to provision the store before use",
+	)
+	flag.BoolVar(
+		&flags.postgis,
+		"postgis",
+		false,
+		"With --migrate, also apply the optional PostGIS migrations (GNSS position column and spatial index)",
+	)
+	flag.StringVar(&flags.opts.DataDir, "dataDir", "", "Location to the ZTBus Data")
+
+	// trip selection
+	flag.Func("bus", "Only load trips of these bus numbers (comma separated)", func(s string) error {
+		flags.opts.Bus = splitList(s)
+		return nil
+	})
+	flag.Func("route", "Only load trips on these routes (comma separated)", func(s string) error {
+		flags.opts.Route = splitList(s)
+		return nil
+	})
+	flag.StringVar(
+		&flags.opts.From,
+		"from",
+		"",
+		"Only load trips starting at or after this time (YYYY-MM-DD or RFC3339, UTC)",
+	)
+	flag.StringVar(
+		&flags.opts.To,
+		"to",
+		"",
+		"Only load trips ending at or before this time (YYYY-MM-DD or RFC3339, UTC)",
+	)
+	flag.StringVar(
+		&flags.opts.TripGlob,
+		"trip-glob",
+		"",
+		"Only load trips whose name matches this glob pattern (e.g. 'B183_2019-06-*')",
+	)
+	flag.IntVar(&flags.opts.Limit, "limit", 0, "Load at most this many trips after filtering (0 = all)")
+
+	// random sampling
+	flag.Float64Var(
+		&flags.opts.Sample,
+		"sample",
+		0,
+		"Load a reproducible random fraction of the selected trips, e.g. 0.05 (0 = all)",
+	)
+	flag.Int64Var(&flags.opts.Seed, "seed", flags.opts.Seed, "Seed for --sample")
+	flag.BoolVar(&flags.opts.Stratify, "stratify", false, "Stratify --sample by bus and route")
+	flag.StringVar(
+		&flags.opts.SampleName,
+		"sample-name",
+		"",
+		"Name under which the sampled trips are recorded (defaults to one derived from the settings)",
+	)
+
+	// ingest time resampling
+	flag.StringVar(
+		&flags.opts.Resample,
+		"resample",
+		"",
+		"Aggregate telemetry into windows of this size (e.g. 10s, 1m) into telemetry_resampled",
+	)
+	flag.StringVar(
+		&flags.opts.ResampleMode,
+		"resample-mode",
+		flags.opts.ResampleMode,
+		"'rollup' loads raw telemetry and the windows, 'replace' loads only the windows",
+	)
+
+	// post load steps
+	flag.BoolVar(
+		&flags.opts.Rollups,
+		"rollups",
+		false,
+		"Refresh the per-minute and per-trip rollup tables for loaded trips (and any trip missing one)",
+	)
+	flag.BoolVar(
+		&flags.opts.SnapRoutes,
+		"snap-routes",
+		false,
+		"Snap loaded trips to their route shape (see the route-shapes command) and store distance along route",
+	)
+
+	// bulk loading
+	flag.BoolVar(
+		&flags.opts.BulkLoad,
+		"bulk-load",
+		false,
+		"Drop secondary telemetry indexes during the load and rebuild them in parallel afterwards",
+	)
+	flag.BoolVar(
+		&flags.opts.Unlogged,
+		"unlogged",
+		false,
+		"Set telemetry partitions UNLOGGED during a --bulk-load (data is lost if the server crashes)",
+	)
+
+	// quality checks
+	flag.BoolVar(
+		&flags.opts.Verify,
+		"verify",
+		false,
+		"Cross-check the metaData.csv summaries against telemetry and store the result in trip_quality",
+	)
+	flag.Float64Var(
+		&flags.opts.VerifyTolerance,
+		"verify-tolerance",
+		flags.opts.VerifyTolerance,
+		"Relative tolerance for --verify",
+	)
+	flag.BoolVar(
+		&flags.opts.CheckTimeline,
+		"check-timeline",
+		false,
+		"Check each trip for duplicate, backwards and missing seconds and record gaps in telemetry_gaps",
+	)
+	flag.DurationVar(
+		&flags.opts.GapThreshold,
+		"gap-threshold",
+		flags.opts.GapThreshold,
+		"Record a gap when consecutive samples are further apart than this",
+	)
+	flag.BoolVar(
+		&flags.opts.SortTelemetry,
+		"sort-telemetry",
+		false,
+		"Sort telemetry by time before loading (with --check-timeline)",
+	)
+	flag.BoolVar(
+		&flags.opts.DedupeTelemetry,
+		"dedupe-telemetry",
+		false,
+		"Keep only the first sample of each second (with --check-timeline)",
+	)
+	flag.BoolVar(
+		&flags.opts.Plausibility,
+		"plausibility",
+		false,
+		"Check telemetry against physical plausibility rules and record violations",
+	)
+	flag.StringVar(
+		&flags.opts.RulesFile,
+		"rules",
+		"",
+		"JSON plausibility rules file (defaults to the built in rules, implies --plausibility)",
+	)
+	flag.BoolVar(
+		&flags.opts.NullImplausible,
+		"null-implausible",
+		false,
+		"Load implausible values as NULL (with --plausibility)",
+	)
+	flag.Parse()
+
+	return flags
+}
+
+func validateFlags(flags cliFlags) error {
+	if flags.showHelp {
+		return nil
+	}
+
+	if flags.platform == "" {
+		return fmt.Errorf("a platform selection is required")
+	}
+	if err := ValidateDatalayer(flags.platform); err != nil {
+		return fmt.Errorf("invalid platform: %w", err)
+	}
+
+	if err := ValidateConnStr(flags.connStr); err != nil {
+		return fmt.Errorf("invalid connection string: %w", err)
+	}
+
+	if err := ValidateDataDir(flags.opts.DataDir); err != nil {
+		return fmt.Errorf("invalid dataDir: %w", err)
+	}
+
+	if flags.postgis && !flags.migrate {
+		return fmt.Errorf("--postgis requires --migrate")
+	}
+
+	return flags.opts.Validate()
+}
+
+func runCLI(flags cliFlags) error {
+	if flags.showHelp {
+		flag.Usage()
+		return nil
+	}
+
+	// stdout logger
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	})
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+
+	// perform migrations if requested
+	slog.Debug("premigration")
+	if flags.migrate {
+		slog.Debug("migrating datalayer")
+		err := postgres.Migrate(flags.connStr)
+		if err != nil {
+			slog.Error("could not migrate the datalayer, exiting", "error", err)
+			return err
+		}
+		if flags.postgis {
+			if err := postgres.MigratePostgis(flags.connStr); err != nil {
+				slog.Error("could not apply the PostGIS migrations, exiting", "error", err)
+				return err
+			}
+		}
+	}
+	slog.Debug("postmigration")
+	slog.Debug("starting data load")
+
+	// create connection pool for parallel processing
+	ctx := context.Background()
+	poolConfig, err := pgxpool.ParseConfig(flags.connStr)
+	if err != nil {
+		return fmt.Errorf("error parsing connection string: %v", err)
+	}
+
+	// configure pool settings for optimal performance
+	poolConfig.MaxConns = int32(15) // workers + some buffer for main operations
+	poolConfig.MinConns = 5
+	poolConfig.MaxConnLifetime = time.Hour
+	poolConfig.MaxConnIdleTime = time.Minute * 30
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return fmt.Errorf("error creating connection pool: %v", err)
+	}
+	defer pool.Close()
+
+	flags.opts.Progress = true
+	summary, err := loader.Load(ctx, pool, flags.opts)
+	if err != nil {
+		return err
+	}
+	summary.Print(os.Stdout)
+
+	return nil
+}
+
+// subcommands, selected by the first argument
+var subcommands = map[string]func(args []string) error{
+	"audit":          runAudit,
+	"export":         runExport,
+	"import-context": runImportContext,
+	"import-gtfs":    runImportGtfs,
+	"partition":      runPartition,
+	"route-shapes":   runRouteShapes,
+	"serve":          runServe,
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	flags := parseFlags()
+
+	if err := validateFlags(flags); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	err := runCLI(flags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// runPartition shows or changes the partitioning of the telemetry table
+func runPartition(args []string) error {
+	fs := flag.NewFlagSet("partition", flag.ExitOnError)
+	platform := fs.String(
+		"platform",
+		"",
+		"Data platform to use as the data layer (e.g., postgresql)",
+	)
+	connStr := fs.String("connStr", "", "Connection string to the datalayer")
+	interval := fs.String("interval", "", "Partition interval: day, week or month")
+	premake := fs.Int("premake", -1, "Number of partitions to create ahead of the newest data")
+	subPartition := fs.Int(
+		"sub-partition-trips",
+		-1,
+		"Sub-partition every time partition by trip_id, this many trips each (0 = off)",
+	)
+	retention := fs.String(
+		"retention",
+		"",
+		"Drop partitions older than this, e.g. '2 years' ('none' keeps everything)",
+	)
+	keepTable := fs.Bool(
+		"retention-keep-table",
+		false,
+		"Detach expired partitions instead of dropping them",
+	)
+	fs.Parse(args)
+
+	if err := ValidateDatalayer(*platform); err != nil {
+		return fmt.Errorf("invalid platform: %w", err)
+	}
+	if err := ValidateConnStr(*connStr); err != nil {
+		return fmt.Errorf("invalid connection string: %w", err)
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, *connStr)
+	if err != nil {
+		return fmt.Errorf("error creating connection pool: %v", err)
+	}
+	defer pool.Close()
+
+	current, err := postgres.TelemetryPartitionConfig(ctx, pool)
+	if err != nil {
+		return err
+	}
+
+	// unset flags keep the current setting
+	target := current
+	if *interval != "" {
+		if _, ok := postgres.PartitionIntervals[*interval]; !ok {
+			return fmt.Errorf("--interval must be day, week or month, got '%s'", *interval)
+		}
+		target.Interval = *interval
+	}
+	if *premake >= 0 {
+		target.Premake = *premake
+	}
+	if *subPartition >= 0 {
+		target.SubPartitionTrips = *subPartition
+	}
+	switch *retention {
+	case "":
+	case "none":
+		target.Retention = ""
+	default:
+		target.Retention = *retention
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "retention-keep-table" {
+			target.RetentionKeepTable = *keepTable
+		}
+	})
+
+	if target.Interval != current.Interval || target.SubPartitionTrips != current.SubPartitionTrips {
+		slog.Info(
+			"repartitioning telemetry",
+			"interval", fmt.Sprintf("%s -> %s", current.Interval, target.Interval),
+			"sub_partition_trips", fmt.Sprintf(
+				"%d -> %d",
+				current.SubPartitionTrips,
+				target.SubPartitionTrips,
+			),
+		)
+		start := time.Now()
+		if err := postgres.RepartitionTelemetry(ctx, pool, target); err != nil {
+			return fmt.Errorf("could not repartition telemetry: %w", err)
+		}
+		slog.Info("repartitioned telemetry", "duration", time.Since(start).Round(time.Second))
+	}
+	if err := postgres.ApplyRetention(ctx, pool, target); err != nil {
+		return err
+	}
+
+	layout, err := postgres.FinishPartitions(ctx, pool)
+	if err != nil {
+		return err
+	}
+
+	retentionText := "keep all"
+	if target.Retention != "" {
+		retentionText = target.Retention
+		if target.RetentionKeepTable {
+			retentionText += " (detach)"
+		}
+	}
+	fmt.Fprintf(
+		os.Stdout,
+		"interval %s, premake %d, retention %s, trips per sub-partition %d\n",
+		target.Interval,
+		target.Premake,
+		retentionText,
+		target.SubPartitionTrips,
+	)
+	for _, p := range layout {
+		fmt.Fprintf(os.Stdout, "  %-32s %-60s ~%d rows\n", p.Partition, p.Bounds, p.EstimatedRows)
+	}
+	return nil
+}
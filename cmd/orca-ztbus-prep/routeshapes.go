@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/Predixus/orca-ztbus-prep/loader"
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// runRouteShapes builds route shapes from the loaded telemetry and snaps all
+// trips of those routes onto them
+func runRouteShapes(args []string) error {
+	fs := flag.NewFlagSet("route-shapes", flag.ExitOnError)
+	platform := fs.String(
+		"platform",
+		"",
+		"Data platform to use as the data layer (e.g., postgresql)",
+	)
+	connStr := fs.String("connStr", "", "Connection string to the datalayer")
+	routes := fs.String("route", "", "Only build shapes for these routes (comma separated)")
+	maxTrips := fs.Int("trips", 20, "Number of trips to build each shape from")
+	spacing := fs.Float64("spacing", 10, "Distance between shape vertices in metres")
+	fs.Parse(args)
+
+	if err := ValidateDatalayer(*platform); err != nil {
+		return fmt.Errorf("invalid platform: %w", err)
+	}
+	if err := ValidateConnStr(*connStr); err != nil {
+		return fmt.Errorf("invalid connection string: %w", err)
+	}
+	if *maxTrips < 1 {
+		return fmt.Errorf("-trips must be at least 1, got %d", *maxTrips)
+	}
+	if *spacing <= 0 {
+		return fmt.Errorf("-spacing must be positive, got %g", *spacing)
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, *connStr)
+	if err != nil {
+		return fmt.Errorf("error creating connection pool: %v", err)
+	}
+	defer pool.Close()
+	q := postgres.New(pool)
+
+	all, err := q.ListRoutes(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list routes: %v", err)
+	}
+	selected := splitList(*routes)
+
+	for _, route := range all {
+		if selected != nil && !slices.Contains(selected, route.RouteCode.String) {
+			continue
+		}
+		result, ok, err := loader.BuildRouteShape(ctx, pool, route, *maxTrips, *spacing)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(
+			os.Stdout,
+			"route %-8s %7.0f m from %d trips, %d trips snapped\n",
+			route.RouteCode.String,
+			result.LengthM,
+			result.Tracks,
+			result.Snapped,
+		)
+	}
+	return nil
+}
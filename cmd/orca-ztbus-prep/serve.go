@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Predixus/orca-ztbus-prep/server"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// runServe serves the loaded database as a read only HTTP API
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	platform := fs.String(
+		"platform",
+		"",
+		"Data platform to use as the data layer (e.g., postgresql)",
+	)
+	connStr := fs.String("connStr", "", "Connection string to the datalayer")
+	var c server.Config
+	fs.StringVar(&c.Addr, "addr", ":8080", "Address to listen on")
+	fs.IntVar(&c.MaxLimit, "max-limit", 100000, "Largest page a client may request")
+	fs.StringVar(
+		&c.FlightAddr,
+		"flight-addr",
+		"",
+		"Address to serve telemetry over Arrow Flight on, e.g. :8815 (default: disabled)",
+	)
+	fs.StringVar(
+		&c.GRPCAddr,
+		"grpc-addr",
+		"",
+		"Address to serve the gRPC API on, e.g. :9090 (default: disabled)",
+	)
+	fs.Parse(args)
+
+	if err := ValidateDatalayer(*platform); err != nil {
+		return fmt.Errorf("invalid platform: %w", err)
+	}
+	if err := ValidateConnStr(*connStr); err != nil {
+		return fmt.Errorf("invalid connection string: %w", err)
+	}
+	if c.MaxLimit < 1 {
+		return fmt.Errorf("-max-limit must be at least 1, got %d", c.MaxLimit)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	pool, err := pgxpool.New(ctx, *connStr)
+	if err != nil {
+		return fmt.Errorf("error creating connection pool: %v", err)
+	}
+	defer pool.Close()
+
+	return server.Serve(ctx, pool, c)
+}
@@ -95,3 +95,14 @@ func ParsePostgresURL(s string, example string) (map[string]string, error) {
 
 	return result, nil
 }
+
+// splitList splits a comma separated value, dropping empty entries
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,272 @@
+package loader
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// contextTimeLayouts are tried in order when no time format is given.
+// Layouts without a zone are read in the configured location.
+var contextTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"200601021504", // MeteoSwiss
+	"20060102",
+}
+
+// parseContextTime parses a time cell with the given layout, or any of the
+// known layouts and finally unix seconds if the layout is empty
+func parseContextTime(s, layout string, loc *time.Location) (time.Time, error) {
+	if layout != "" {
+		return time.ParseInLocation(layout, s, loc)
+	}
+	for _, l := range contextTimeLayouts {
+		if t, err := time.ParseInLocation(l, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("could not parse time '%s'", s)
+}
+
+// contextValue types a cell as a number, a boolean or a string. Empty cells,
+// "-" (as MeteoSwiss marks missing values) and NaN are left out.
+func contextValue(s string) (any, bool) {
+	if s == "" || s == "-" {
+		return nil, false
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, false
+		}
+		return f, true
+	}
+	switch strings.ToLower(s) {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	}
+	return s, true
+}
+
+// ContextCSV describes how to read a time indexed CSV file
+type ContextCSV struct {
+	TimeCol    string
+	ValueCols  []string // all but the time column if empty
+	TimeFormat string   // Go layout, detected if empty
+	Location   *time.Location
+	Delimiter  rune
+}
+
+// readContextCSV reads the observations of a time indexed CSV file, ordered by
+// time. Later rows replace earlier ones with the same time.
+func readContextCSV(
+	path string,
+	source string,
+	c ContextCSV,
+) ([]postgres.InsertContextObservationsParams, []string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comma = c.Delimiter
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read header: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, col := range header {
+		col = strings.TrimSpace(strings.TrimPrefix(col, "\ufeff"))
+		header[i] = col
+		columns[col] = i
+	}
+	timeIdx, ok := columns[c.TimeCol]
+	if !ok {
+		return nil, nil, fmt.Errorf("no time column '%s' in %v", c.TimeCol, header)
+	}
+	valueCols := c.ValueCols
+	if len(valueCols) == 0 {
+		for _, col := range header {
+			if col != c.TimeCol {
+				valueCols = append(valueCols, col)
+			}
+		}
+	}
+	valueIdx := make([]int, len(valueCols))
+	for i, col := range valueCols {
+		idx, ok := columns[col]
+		if !ok {
+			return nil, nil, fmt.Errorf("no value column '%s' in %v", col, header)
+		}
+		valueIdx[i] = idx
+	}
+
+	byTime := map[time.Time]int{}
+	var observations []postgres.InsertContextObservationsParams
+	duplicates := 0
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not read line %d: %v", line, err)
+		}
+		if timeIdx >= len(record) || strings.TrimSpace(record[timeIdx]) == "" {
+			continue
+		}
+		ts, err := parseContextTime(strings.TrimSpace(record[timeIdx]), c.TimeFormat, c.Location)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		values := make(map[string]any, len(valueCols))
+		for i, col := range valueCols {
+			if valueIdx[i] >= len(record) {
+				continue
+			}
+			if v, ok := contextValue(strings.TrimSpace(record[valueIdx[i]])); ok {
+				values[col] = v
+			}
+		}
+		data, err := json.Marshal(values)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		o := postgres.InsertContextObservationsParams{
+			Source: source,
+			Time:   pgtype.Timestamptz{Time: ts.UTC(), Valid: true},
+			Data:   data,
+		}
+		if i, ok := byTime[o.Time.Time]; ok {
+			observations[i] = o
+			duplicates++
+			continue
+		}
+		byTime[o.Time.Time] = len(observations)
+		observations = append(observations, o)
+	}
+	if duplicates > 0 {
+		slog.Warn("replaced rows with a repeated time", "file", path, "rows", duplicates)
+	}
+
+	slices.SortFunc(observations, func(a, b postgres.InsertContextObservationsParams) int {
+		return a.Time.Time.Compare(b.Time.Time)
+	})
+	return observations, valueCols, nil
+}
+
+// storeContext replaces the observations of a source
+func storeContext(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	source postgres.UpsertContextSourceParams,
+	observations []postgres.InsertContextObservationsParams,
+) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := postgres.New(tx)
+
+	if err := qtx.UpsertContextSource(ctx, source); err != nil {
+		return fmt.Errorf("could not store context source: %v", err)
+	}
+	if err := qtx.DeleteContextObservations(ctx, source.Name); err != nil {
+		return fmt.Errorf("could not clear context observations: %v", err)
+	}
+	if len(observations) > 0 {
+		if _, err := qtx.InsertContextObservations(ctx, observations); err != nil {
+			return fmt.Errorf("error during COPY FROM: %v", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit transaction: %v", err)
+	}
+	return nil
+}
+
+// ContextSummary is the outcome of a context import
+type ContextSummary struct {
+	Source       string
+	Columns      []string
+	Observations int
+	FirstTime    pgtype.Timestamptz
+	LastTime     pgtype.Timestamptz
+}
+
+// Print writes the summary to w
+func (s ContextSummary) Print(w io.Writer) {
+	fmt.Fprintf(w, "source %s: %d observations (%s)\n",
+		s.Source, s.Observations, strings.Join(s.Columns, ", "))
+	if s.FirstTime.Valid {
+		fmt.Fprintf(w, "from %s to %s\n",
+			s.FirstTime.Time.Format(time.RFC3339), s.LastTime.Time.Format(time.RFC3339))
+	}
+}
+
+// ImportContext replaces the observations of a context source with those read
+// from a time indexed CSV file
+func ImportContext(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	file string,
+	source string,
+	c ContextCSV,
+) (ContextSummary, error) {
+	observations, columns, err := readContextCSV(file, source, c)
+	if err != nil {
+		return ContextSummary{}, fmt.Errorf("could not read %s: %v", file, err)
+	}
+
+	p := postgres.UpsertContextSourceParams{
+		Name:         source,
+		File:         file,
+		ValueColumns: columns,
+		Observations: int32(len(observations)),
+	}
+	if n := len(observations); n > 0 {
+		p.FirstTime = observations[0].Time
+		p.LastTime = observations[n-1].Time
+	}
+	if err := storeContext(ctx, pool, p, observations); err != nil {
+		return ContextSummary{}, err
+	}
+	return ContextSummary{
+		Source:       source,
+		Columns:      columns,
+		Observations: len(observations),
+		FirstTime:    p.FirstTime,
+		LastTime:     p.LastTime,
+	}, nil
+}
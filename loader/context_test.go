@@ -0,0 +1,101 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseContextTime(t *testing.T) {
+	zurich, err := time.LoadLocation("Europe/Zurich")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+	tests := []struct {
+		in, layout string
+		want       time.Time
+	}{
+		{"2019-06-08T13:20:00Z", "", time.Date(2019, 6, 8, 13, 20, 0, 0, time.UTC)},
+		{"2019-06-08 13:20", "", time.Date(2019, 6, 8, 11, 20, 0, 0, time.UTC)},
+		{"201906081320", "", time.Date(2019, 6, 8, 11, 20, 0, 0, time.UTC)},
+		{"2019-06-08", "", time.Date(2019, 6, 7, 22, 0, 0, 0, time.UTC)},
+		{"1560000000", "", time.Unix(1_560_000_000, 0)},
+		{"08.06.2019", "02.01.2006", time.Date(2019, 6, 7, 22, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseContextTime(tt.in, tt.layout, zurich)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseContextTime(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := parseContextTime("yesterday", "", zurich); err == nil {
+		t.Error("parseContextTime() of an unknown format returned no error")
+	}
+}
+
+func TestContextValue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want any
+		ok   bool
+	}{
+		{"12.5", 12.5, true},
+		{"TRUE", true, true},
+		{"false", false, true},
+		{"Sechseläuten", "Sechseläuten", true},
+		{"", nil, false},
+		{"-", nil, false},
+		{"NaN", nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := contextValue(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("contextValue(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestReadContextCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "weather.csv")
+	data := "time;tre200s0;rre150z0;station\n" +
+		"201906081330;21.5;-;SMA\n" +
+		"201906081320;21.0;0.0;SMA\n" +
+		"201906081330;21.7;0.1;SMA\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := ContextCSV{
+		TimeCol:   "time",
+		ValueCols: []string{"tre200s0", "rre150z0"},
+		Location:  time.UTC,
+		Delimiter: ';',
+	}
+	observations, columns, err := readContextCSV(path, "weather", c)
+	if err != nil {
+		t.Fatalf("readContextCSV() error = %v", err)
+	}
+	if !reflect.DeepEqual(columns, c.ValueCols) {
+		t.Errorf("columns = %v, want %v", columns, c.ValueCols)
+	}
+	// sorted by time, with the repeated time replaced by the later row
+	want := []string{`{"rre150z0":0,"tre200s0":21}`, `{"rre150z0":0.1,"tre200s0":21.7}`}
+	if len(observations) != len(want) {
+		t.Fatalf("%d observations, want %d", len(observations), len(want))
+	}
+	for i, o := range observations {
+		if o.Source != "weather" || string(o.Data) != want[i] {
+			t.Errorf("observation %d = %s %s, want %s", i, o.Source, o.Data, want[i])
+		}
+	}
+	if !observations[0].Time.Time.Before(observations[1].Time.Time) {
+		t.Error("observations are not ordered by time")
+	}
+
+	c.ValueCols = []string{"tre200s0", "wind"}
+	if _, _, err := readContextCSV(path, "weather", c); err == nil {
+		t.Error("readContextCSV() with an unknown column returned no error")
+	}
+}
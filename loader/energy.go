@@ -0,0 +1,87 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// minEfficiencyDistance is the distance in km a trip needs for a kWh/km figure
+const minEfficiencyDistance = 0.1
+
+// ComputeTripEnergy integrates the power demand of a trip's time ordered
+// records. Negative power is regeneration. Consumed energy is split into
+// traction while the traction force is positive and auxiliary (heating, air
+// conditioning, compressors) otherwise, attributing each step by its first
+// sample.
+func ComputeTripEnergy(
+	tripID int32,
+	records []ztbus.TripTelemetry,
+) postgres.UpsertTripEnergyParams {
+	var consumed, regenerated, traction, distance float64
+	for i := 1; i < len(records); i++ {
+		prev, t := records[i-1], records[i]
+		dt := float64(t.TimeUnix - prev.TimeUnix)
+		if dt <= 0 {
+			continue
+		}
+
+		used, regen := splitTrapezoid(prev.ElectricPowerDemand, t.ElectricPowerDemand, dt)
+		consumed += used
+		regenerated += regen
+		if prev.TractionTractionForce > 0 {
+			traction += used
+		}
+		distance += trapezoid(prev.OdometryVehicleSpeed, t.OdometryVehicleSpeed, dt)
+	}
+
+	p := postgres.UpsertTripEnergyParams{
+		TripID:         tripID,
+		EnergyKwh:      (consumed - regenerated) / 3.6e6,
+		ConsumedKwh:    consumed / 3.6e6,
+		RegeneratedKwh: regenerated / 3.6e6,
+		TractionKwh:    traction / 3.6e6,
+		AuxiliaryKwh:   (consumed - traction) / 3.6e6,
+		DistanceKm:     distance / 1000,
+	}
+	if p.DistanceKm >= minEfficiencyDistance {
+		p.KwhPerKm = pgtype.Float8{Float64: p.EnergyKwh / p.DistanceKm, Valid: true}
+	}
+	return p
+}
+
+// splitTrapezoid integrates over one step like trapezoid, returning the
+// positive and negative areas separately. A step crossing zero is split at the
+// interpolated crossing, so +P to -P yields P*dt/4 on each side.
+func splitTrapezoid(a, b, dt float64) (float64, float64) {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(b):
+		return 0, 0
+	case a >= 0 && b >= 0:
+		return trapezoid(a, b, dt), 0
+	case a <= 0 && b <= 0:
+		return 0, -trapezoid(a, b, dt)
+	}
+	cross := dt * a / (a - b)
+	if a > 0 {
+		return 0.5 * a * cross, -0.5 * b * (dt - cross)
+	}
+	return 0.5 * b * (dt - cross), -0.5 * a * cross
+}
+
+// writeTripEnergy stores the energy features of a trip
+func writeTripEnergy(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	p postgres.UpsertTripEnergyParams,
+) error {
+	if err := postgres.New(pool).UpsertTripEnergy(ctx, p); err != nil {
+		return fmt.Errorf("could not store trip energy: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package loader
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// kwhPerSecond is the power in W that delivers 1 kWh in one second
+const kwhPerSecond = 3.6e6
+
+func TestSplitTrapezoid(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b, dt float64
+		pos, neg float64
+	}{
+		{"positive", 2, 4, 2, 6, 0},
+		{"negative", -2, -4, 2, 0, 6},
+		{"positive to negative", 4, -4, 1, 1, 1},
+		{"negative to positive", -1, 3, 1, 1.125, 0.125},
+		{"ends at zero", 4, 0, 1, 2, 0},
+		{"unavailable", math.NaN(), 4, 1, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos, neg := splitTrapezoid(tt.a, tt.b, tt.dt)
+			if math.Abs(pos-tt.pos) > 1e-12 || math.Abs(neg-tt.neg) > 1e-12 {
+				t.Errorf(
+					"splitTrapezoid(%g, %g, %g) = %g, %g, want %g, %g",
+					tt.a, tt.b, tt.dt, pos, neg, tt.pos, tt.neg,
+				)
+			}
+			net := trapezoid(tt.a, tt.b, tt.dt)
+			if math.Abs(pos-neg-net) > 1e-12 {
+				t.Errorf("parts %g and %g do not add up to %g", pos, neg, net)
+			}
+		})
+	}
+}
+
+// energySample is one second of power demand, traction force and speed
+type energySample struct {
+	power, force, speed float64
+}
+
+func TestComputeTripEnergy(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []energySample
+		want    [6]float64 // energy, consumed, regenerated, traction, auxiliary kWh, distance km
+		perKm   pgtype.Float8
+	}{
+		{
+			name: "traction and auxiliary",
+			samples: []energySample{
+				{kwhPerSecond, 1, 10},
+				{kwhPerSecond, 0, 10},
+				{kwhPerSecond, 0, 10},
+			},
+			want: [6]float64{2, 2, 0, 1, 1, 0.02},
+		},
+		{
+			name: "regeneration crossing zero",
+			samples: []energySample{
+				{kwhPerSecond, 0, 10},
+				{-kwhPerSecond, 0, 10},
+			},
+			want: [6]float64{0, 0.25, 0.25, 0, 0.25, 0.01},
+		},
+		{
+			name: "unavailable power",
+			samples: []energySample{
+				{kwhPerSecond, 0, 10},
+				{math.NaN(), 0, 10},
+				{kwhPerSecond, 0, 10},
+			},
+			want: [6]float64{0, 0, 0, 0, 0, 0.02},
+		},
+		{
+			name: "efficiency",
+			samples: []energySample{
+				{kwhPerSecond, 1, 50},
+				{kwhPerSecond, 1, 50},
+				{kwhPerSecond, 1, 50},
+			},
+			want:  [6]float64{2, 2, 0, 2, 0, 0.1},
+			perKm: pgtype.Float8{Float64: 20, Valid: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := make([]ztbus.TripTelemetry, len(tt.samples))
+			for i, s := range tt.samples {
+				records[i] = ztbus.TripTelemetry{
+					TimeUnix:              t0 + i,
+					ElectricPowerDemand:   s.power,
+					TractionTractionForce: s.force,
+					OdometryVehicleSpeed:  s.speed,
+				}
+			}
+			p := ComputeTripEnergy(7, records)
+			got := [6]float64{
+				p.EnergyKwh,
+				p.ConsumedKwh,
+				p.RegeneratedKwh,
+				p.TractionKwh,
+				p.AuxiliaryKwh,
+				p.DistanceKm,
+			}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Errorf("ComputeTripEnergy() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+			if p.KwhPerKm.Valid != tt.perKm.Valid ||
+				math.Abs(p.KwhPerKm.Float64-tt.perKm.Float64) > 1e-9 {
+				t.Errorf("kWh/km = %+v, want %+v", p.KwhPerKm, tt.perKm)
+			}
+		})
+	}
+}
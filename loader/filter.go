@@ -0,0 +1,177 @@
+package loader
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+)
+
+// accepted layouts for the From and To options
+var filterTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// TripFilter restricts the set of trips that get loaded from metaData.csv.
+// Zero values disable the corresponding filter.
+type TripFilter struct {
+	Buses    []string
+	Routes   []string
+	From     time.Time
+	To       time.Time
+	TripGlob string
+	Limit    int
+}
+
+// FilterStep records how many trips remained after a single filter was applied
+type FilterStep struct {
+	Name      string
+	Value     string
+	Remaining int
+}
+
+// ParseFilterTime parses a From or To value as either a date or a timestamp (UTC)
+func ParseFilterTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range filterTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf(
+		"could not parse time '%s' (expected YYYY-MM-DD or RFC3339)",
+		s,
+	)
+}
+
+// NewTripFilter builds a TripFilter from the load options
+func NewTripFilter(opts Options) (TripFilter, error) {
+	from, err := ParseFilterTime(opts.From)
+	if err != nil {
+		return TripFilter{}, fmt.Errorf("invalid option From: %w", err)
+	}
+	to, err := ParseFilterTime(opts.To)
+	if err != nil {
+		return TripFilter{}, fmt.Errorf("invalid option To: %w", err)
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return TripFilter{}, fmt.Errorf("option To (%s) is before From (%s)", opts.To, opts.From)
+	}
+	if opts.TripGlob != "" {
+		// surface malformed patterns before any work is done
+		if _, err := path.Match(opts.TripGlob, ""); err != nil {
+			return TripFilter{}, fmt.Errorf("invalid option TripGlob: %w", err)
+		}
+	}
+	if opts.Limit < 0 {
+		return TripFilter{}, fmt.Errorf("option Limit must not be negative")
+	}
+
+	return TripFilter{
+		Buses:    opts.Bus,
+		Routes:   opts.Route,
+		From:     from,
+		To:       to,
+		TripGlob: opts.TripGlob,
+		Limit:    opts.Limit,
+	}, nil
+}
+
+// IsEmpty reports whether no filters are set
+func (f TripFilter) IsEmpty() bool {
+	return len(f.Buses) == 0 &&
+		len(f.Routes) == 0 &&
+		f.From.IsZero() &&
+		f.To.IsZero() &&
+		f.TripGlob == "" &&
+		f.Limit == 0
+}
+
+// String renders the active filters, e.g. for the progress bar description
+func (f TripFilter) String() string {
+	var parts []string
+	if len(f.Buses) > 0 {
+		parts = append(parts, "bus="+strings.Join(f.Buses, ","))
+	}
+	if len(f.Routes) > 0 {
+		parts = append(parts, "route="+strings.Join(f.Routes, ","))
+	}
+	if !f.From.IsZero() {
+		parts = append(parts, "from="+f.From.Format(time.RFC3339))
+	}
+	if !f.To.IsZero() {
+		parts = append(parts, "to="+f.To.Format(time.RFC3339))
+	}
+	if f.TripGlob != "" {
+		parts = append(parts, "trip-glob="+f.TripGlob)
+	}
+	if f.Limit > 0 {
+		parts = append(parts, fmt.Sprintf("limit=%d", f.Limit))
+	}
+	return strings.Join(parts, " ")
+}
+
+func keep(metadata []ztbus.Metadata, pred func(ztbus.Metadata) bool) []ztbus.Metadata {
+	out := make([]ztbus.Metadata, 0, len(metadata))
+	for _, m := range metadata {
+		if pred(m) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply filters the parsed metadata, returning the selected trips along with
+// the number of trips left after each active filter. Filters are applied in a
+// fixed order with the limit last so that it caps the already filtered set.
+func (f TripFilter) Apply(metadata []ztbus.Metadata) ([]ztbus.Metadata, []FilterStep) {
+	var steps []FilterStep
+	out := metadata
+
+	if len(f.Buses) > 0 {
+		out = keep(out, func(m ztbus.Metadata) bool { return contains(f.Buses, m.BusNumber) })
+		steps = append(steps, FilterStep{"bus", strings.Join(f.Buses, ","), len(out)})
+	}
+	if len(f.Routes) > 0 {
+		out = keep(out, func(m ztbus.Metadata) bool { return contains(f.Routes, m.BusRoute) })
+		steps = append(steps, FilterStep{"route", strings.Join(f.Routes, ","), len(out)})
+	}
+	if !f.From.IsZero() {
+		from := f.From.Unix()
+		out = keep(out, func(m ztbus.Metadata) bool { return int64(m.StartTimeUnix) >= from })
+		steps = append(steps, FilterStep{"from", f.From.Format(time.RFC3339), len(out)})
+	}
+	if !f.To.IsZero() {
+		to := f.To.Unix()
+		out = keep(out, func(m ztbus.Metadata) bool { return int64(m.EndTimeUnix) <= to })
+		steps = append(steps, FilterStep{"to", f.To.Format(time.RFC3339), len(out)})
+	}
+	if f.TripGlob != "" {
+		out = keep(out, func(m ztbus.Metadata) bool {
+			ok, _ := path.Match(f.TripGlob, m.Name)
+			return ok
+		})
+		steps = append(steps, FilterStep{"trip-glob", f.TripGlob, len(out)})
+	}
+	if f.Limit > 0 {
+		out = out[:min(f.Limit, len(out))]
+		steps = append(steps, FilterStep{"limit", fmt.Sprintf("%d", f.Limit), len(out)})
+	}
+
+	return out, steps
+}
@@ -0,0 +1,158 @@
+package loader
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+)
+
+// testTrips spans three days, each trip runs for an hour from 08:00 UTC
+var testTrips = []ztbus.Metadata{
+	tripMeta("B183_2019-06-01_08-00-00", "183", "31", "2019-06-01"),
+	tripMeta("B183_2019-06-02_08-00-00", "183", "33", "2019-06-02"),
+	tripMeta("B208_2019-06-02_08-00-00", "208", "31", "2019-06-02"),
+	tripMeta("B208_2019-06-03_08-00-00", "208", "33", "2019-06-03"),
+}
+
+func tripMeta(name, bus, route, day string) ztbus.Metadata {
+	start, _ := time.Parse("2006-01-02", day)
+	start = start.Add(8 * time.Hour)
+	return ztbus.Metadata{
+		Name:          name,
+		BusNumber:     bus,
+		BusRoute:      route,
+		StartTimeUnix: int(start.Unix()),
+		EndTimeUnix:   int(start.Add(time.Hour).Unix()),
+	}
+}
+
+func tripNames(metadata []ztbus.Metadata) []string {
+	names := make([]string, len(metadata))
+	for i, m := range metadata {
+		names[i] = m.Name
+	}
+	return names
+}
+
+func TestTripFilterApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  Options
+		want  []string
+		steps []int // trips remaining after each active filter
+	}{
+		{
+			name: "no filters",
+			want: tripNames(testTrips),
+		},
+		{
+			name:  "bus",
+			opts:  Options{Bus: []string{"208"}},
+			want:  []string{"B208_2019-06-02_08-00-00", "B208_2019-06-03_08-00-00"},
+			steps: []int{2},
+		},
+		{
+			name:  "bus and route",
+			opts:  Options{Bus: []string{"183", "208"}, Route: []string{"31"}},
+			want:  []string{"B183_2019-06-01_08-00-00", "B208_2019-06-02_08-00-00"},
+			steps: []int{4, 2},
+		},
+		{
+			name:  "glob",
+			opts:  Options{TripGlob: "B*_2019-06-02_*"},
+			want:  []string{"B183_2019-06-02_08-00-00", "B208_2019-06-02_08-00-00"},
+			steps: []int{2},
+		},
+		{
+			// a trip must start at or after From
+			name:  "from is inclusive",
+			opts:  Options{From: "2019-06-02T08:00:00"},
+			want:  tripNames(testTrips[1:]),
+			steps: []int{3},
+		},
+		{
+			// a trip must end at or before To, so a date alone excludes that day
+			name:  "to a date",
+			opts:  Options{To: "2019-06-02"},
+			want:  []string{"B183_2019-06-01_08-00-00"},
+			steps: []int{1},
+		},
+		{
+			name:  "to is inclusive",
+			opts:  Options{To: "2019-06-02T09:00:00Z"},
+			want:  tripNames(testTrips[:3]),
+			steps: []int{3},
+		},
+		{
+			name:  "date range",
+			opts:  Options{From: "2019-06-02", To: "2019-06-03"},
+			want:  []string{"B183_2019-06-02_08-00-00", "B208_2019-06-02_08-00-00"},
+			steps: []int{3, 2},
+		},
+		{
+			name:  "limit",
+			opts:  Options{Limit: 3},
+			want:  tripNames(testTrips[:3]),
+			steps: []int{3},
+		},
+		{
+			// the limit caps the filtered set rather than the input
+			name:  "limit after filters",
+			opts:  Options{Route: []string{"33"}, Limit: 1},
+			want:  []string{"B183_2019-06-02_08-00-00"},
+			steps: []int{2, 1},
+		},
+		{
+			name:  "limit above the trip count",
+			opts:  Options{Limit: 10},
+			want:  tripNames(testTrips),
+			steps: []int{4},
+		},
+		{
+			name:  "nothing left",
+			opts:  Options{Bus: []string{"999"}, Limit: 1},
+			want:  []string{},
+			steps: []int{0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewTripFilter(tt.opts)
+			if err != nil {
+				t.Fatalf("NewTripFilter() error = %v", err)
+			}
+			got, steps := f.Apply(testTrips)
+			if names := tripNames(got); !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Apply() = %v, want %v", names, tt.want)
+			}
+			var remaining []int
+			for _, s := range steps {
+				remaining = append(remaining, s.Remaining)
+			}
+			if !reflect.DeepEqual(remaining, tt.steps) {
+				t.Errorf("Apply() steps = %v, want %v", remaining, tt.steps)
+			}
+		})
+	}
+}
+
+func TestNewTripFilterErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"malformed from", Options{From: "01.06.2019"}},
+		{"to before from", Options{From: "2019-06-02", To: "2019-06-01"}},
+		{"malformed glob", Options{TripGlob: "B[183"}},
+		{"negative limit", Options{Limit: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTripFilter(tt.opts); err == nil {
+				t.Error("NewTripFilter() returned no error")
+			}
+		})
+	}
+}
@@ -0,0 +1,472 @@
+package loader
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// gtfsRow is one record of a GTFS file, read by column name
+type gtfsRow struct {
+	header map[string]int
+	values []string
+}
+
+// get returns a column's value, or "" if the file has no such column
+func (r gtfsRow) get(col string) string {
+	if i, ok := r.header[col]; ok && i < len(r.values) {
+		return strings.TrimSpace(r.values[i])
+	}
+	return ""
+}
+
+func (r gtfsRow) text(col string) pgtype.Text {
+	v := r.get(col)
+	return pgtype.Text{String: v, Valid: v != ""}
+}
+
+func (r gtfsRow) integer(col string, fallback int32) (int32, error) {
+	v := r.get(col)
+	if v == "" {
+		return fallback, nil
+	}
+	i, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", col, v)
+	}
+	return int32(i), nil
+}
+
+// radians converts a coordinate in degrees, as GTFS stores them
+func (r gtfsRow) radians(col string) pgtype.Float8 {
+	f, err := strconv.ParseFloat(r.get(col), 64)
+	if err != nil {
+		return pgtype.Float8{}
+	}
+	return pgtype.Float8{Float64: f * math.Pi / 180, Valid: true}
+}
+
+// date parses a GTFS date (YYYYMMDD)
+func (r gtfsRow) date(col string) (pgtype.Date, error) {
+	d, err := time.Parse("20060102", r.get(col))
+	if err != nil {
+		return pgtype.Date{}, fmt.Errorf("invalid %s %q", col, r.get(col))
+	}
+	return pgtype.Date{Time: d, Valid: true}, nil
+}
+
+// gtfsTime parses a GTFS time (H:MM:SS, past 24:00:00 for trips running after
+// midnight) into seconds after midnight of the service day
+func gtfsTime(v string) (int32, error) {
+	parts := strings.Split(v, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid time %q", v)
+	}
+	var s int32
+	for _, p := range parts {
+		n, err := strconv.ParseInt(p, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time %q", v)
+		}
+		s = s*60 + int32(n)
+	}
+	return s, nil
+}
+
+// errGtfsFileMissing is returned for optional files absent from a feed
+var errGtfsFileMissing = errors.New("file not in feed")
+
+// readGtfsFile calls fn for every record of a file in the feed
+func readGtfsFile(zr *zip.Reader, name string, fn func(r gtfsRow) error) error {
+	var file *zip.File
+	for _, f := range zr.File {
+		// some feeds nest their files in a directory
+		if path.Base(f.Name) == name {
+			file = f
+			break
+		}
+	}
+	if file == nil {
+		return fmt.Errorf("%s: %w", name, errGtfsFileMissing)
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("could not open %s: %v", name, err)
+	}
+	defer rc.Close()
+
+	r := csv.NewReader(rc)
+	r.FieldsPerRecord = -1
+	r.ReuseRecord = true
+	header, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("could not read header of %s: %v", name, err)
+	}
+	row := gtfsRow{header: make(map[string]int, len(header))}
+	for i, col := range header {
+		// strip the byte order mark some feeds start with
+		col = strings.TrimSpace(strings.TrimPrefix(col, "\ufeff"))
+		row.header[col] = i
+	}
+	for line := 2; ; line++ {
+		values, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not read %s: %v", name, err)
+		}
+		row.values = values
+		if err := fn(row); err != nil {
+			return fmt.Errorf("%s line %d: %v", name, line, err)
+		}
+	}
+}
+
+// isBusRouteType reports whether a GTFS route type covers buses or
+// trolleybuses, including the extended types used by European feeds
+func isBusRouteType(t int32) bool {
+	return t == 3 || t == 11 || t == 800 || (t >= 700 && t < 800)
+}
+
+// stopNameKey normalises a stop name for matching. GTFS names often carry the
+// town ("Zürich, Bahnhofquai/HB") where ITCS names do not.
+func stopNameKey(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if _, rest, ok := strings.Cut(name, ", "); ok {
+		name = rest
+	}
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// gtfsFeed is a GTFS feed prepared for storage
+type gtfsFeed struct {
+	routes        []postgres.InsertGtfsRoutesParams
+	stops         []postgres.InsertGtfsStopsParams
+	trips         []postgres.InsertGtfsTripsParams
+	stopTimes     []postgres.InsertGtfsStopTimesParams
+	calendar      []postgres.InsertGtfsCalendarParams
+	calendarDates []postgres.InsertGtfsCalendarDatesParams
+}
+
+// readGtfsFeed reads a GTFS zip and links its routes and stops to the ZTBus
+// ones. Unless allRoutes is set only the trips of linked routes are kept.
+func readGtfsFeed(
+	file string,
+	routeIDs map[string]int32,
+	stops []postgres.Stop,
+	allRoutes bool,
+) (*gtfsFeed, error) {
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not open GTFS feed: %v", err)
+	}
+	defer zr.Close()
+	feed := &gtfsFeed{}
+
+	keptRoutes := map[string]bool{}
+	err = readGtfsFile(&zr.Reader, "routes.txt", func(r gtfsRow) error {
+		routeType, err := r.integer("route_type", 0)
+		if err != nil {
+			return err
+		}
+		route := postgres.InsertGtfsRoutesParams{
+			ID:        r.get("route_id"),
+			AgencyID:  r.text("agency_id"),
+			ShortName: r.text("route_short_name"),
+			LongName:  r.text("route_long_name"),
+			RouteType: routeType,
+		}
+		if id, ok := routeIDs[route.ShortName.String]; ok && isBusRouteType(routeType) {
+			route.BusRouteID = pgtype.Int4{Int32: id, Valid: true}
+		}
+		keptRoutes[route.ID] = allRoutes || route.BusRouteID.Valid
+		feed.routes = append(feed.routes, route)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exact := make(map[string]int32, len(stops))
+	normalised := make(map[string]int32, len(stops))
+	for _, s := range stops {
+		exact[s.Name] = s.ID
+		normalised[stopNameKey(s.Name)] = s.ID
+	}
+	err = readGtfsFile(&zr.Reader, "stops.txt", func(r gtfsRow) error {
+		locationType, err := r.integer("location_type", 0)
+		if err != nil {
+			return err
+		}
+		stop := postgres.InsertGtfsStopsParams{
+			ID:            r.get("stop_id"),
+			Name:          r.get("stop_name"),
+			Latitude:      r.radians("stop_lat"),
+			Longitude:     r.radians("stop_lon"),
+			LocationType:  locationType,
+			ParentStation: r.text("parent_station"),
+		}
+		id, ok := exact[stop.Name]
+		if !ok {
+			id, ok = normalised[stopNameKey(stop.Name)]
+		}
+		stop.StopID = pgtype.Int4{Int32: id, Valid: ok}
+		feed.stops = append(feed.stops, stop)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// platforms named differently from their station follow the station
+	stationIDs := map[string]pgtype.Int4{}
+	for _, s := range feed.stops {
+		stationIDs[s.ID] = s.StopID
+	}
+	for i, s := range feed.stops {
+		if !s.StopID.Valid && s.ParentStation.Valid {
+			feed.stops[i].StopID = stationIDs[s.ParentStation.String]
+		}
+	}
+
+	keptTrips := map[string]bool{}
+	err = readGtfsFile(&zr.Reader, "trips.txt", func(r gtfsRow) error {
+		routeID := r.get("route_id")
+		if !keptRoutes[routeID] {
+			return nil
+		}
+		direction, err := r.integer("direction_id", -1)
+		if err != nil {
+			return err
+		}
+		trip := postgres.InsertGtfsTripsParams{
+			ID:          r.get("trip_id"),
+			GtfsRouteID: routeID,
+			ServiceID:   r.get("service_id"),
+			Headsign:    r.text("trip_headsign"),
+			DirectionID: pgtype.Int4{Int32: direction, Valid: direction >= 0},
+		}
+		keptTrips[trip.ID] = true
+		feed.trips = append(feed.trips, trip)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readGtfsFile(&zr.Reader, "stop_times.txt", func(r gtfsRow) error {
+		tripID := r.get("trip_id")
+		if !keptTrips[tripID] {
+			return nil
+		}
+		// stops without times are not timepoints and cannot be compared
+		arrival, departure := r.get("arrival_time"), r.get("departure_time")
+		if arrival == "" && departure == "" {
+			return nil
+		}
+		if arrival == "" {
+			arrival = departure
+		}
+		if departure == "" {
+			departure = arrival
+		}
+		st := postgres.InsertGtfsStopTimesParams{GtfsTripID: tripID, GtfsStopID: r.get("stop_id")}
+		var err error
+		if st.StopSequence, err = r.integer("stop_sequence", 0); err != nil {
+			return err
+		}
+		if st.ArrivalS, err = gtfsTime(arrival); err != nil {
+			return err
+		}
+		if st.DepartureS, err = gtfsTime(departure); err != nil {
+			return err
+		}
+		feed.stopTimes = append(feed.stopTimes, st)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// a feed needs at least one of the calendar files
+	err = readGtfsFile(&zr.Reader, "calendar.txt", func(r gtfsRow) error {
+		c := postgres.InsertGtfsCalendarParams{
+			ServiceID: r.get("service_id"),
+			Monday:    r.get("monday") == "1",
+			Tuesday:   r.get("tuesday") == "1",
+			Wednesday: r.get("wednesday") == "1",
+			Thursday:  r.get("thursday") == "1",
+			Friday:    r.get("friday") == "1",
+			Saturday:  r.get("saturday") == "1",
+			Sunday:    r.get("sunday") == "1",
+		}
+		var err error
+		if c.StartDate, err = r.date("start_date"); err != nil {
+			return err
+		}
+		if c.EndDate, err = r.date("end_date"); err != nil {
+			return err
+		}
+		feed.calendar = append(feed.calendar, c)
+		return nil
+	})
+	calendarMissing := errors.Is(err, errGtfsFileMissing)
+	if err != nil && !calendarMissing {
+		return nil, err
+	}
+	err = readGtfsFile(&zr.Reader, "calendar_dates.txt", func(r gtfsRow) error {
+		exception, err := r.integer("exception_type", 0)
+		if err != nil {
+			return err
+		}
+		date, err := r.date("date")
+		if err != nil {
+			return err
+		}
+		feed.calendarDates = append(feed.calendarDates, postgres.InsertGtfsCalendarDatesParams{
+			ServiceID:     r.get("service_id"),
+			Date:          date,
+			ExceptionType: exception,
+		})
+		return nil
+	})
+	if errors.Is(err, errGtfsFileMissing) && calendarMissing {
+		return nil, fmt.Errorf("feed has neither calendar.txt nor calendar_dates.txt")
+	} else if err != nil && !errors.Is(err, errGtfsFileMissing) {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+// storeGtfsFeed replaces the stored timetable with a feed
+func storeGtfsFeed(ctx context.Context, pool *pgxpool.Pool, feed *gtfsFeed) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := postgres.New(tx)
+
+	if err := qtx.ClearGtfs(ctx); err != nil {
+		return fmt.Errorf("could not clear timetable: %v", err)
+	}
+	copies := []struct {
+		table string
+		copy  func() (int64, error)
+	}{
+		{"gtfs_routes", func() (int64, error) { return qtx.InsertGtfsRoutes(ctx, feed.routes) }},
+		{"gtfs_stops", func() (int64, error) { return qtx.InsertGtfsStops(ctx, feed.stops) }},
+		{"gtfs_trips", func() (int64, error) { return qtx.InsertGtfsTrips(ctx, feed.trips) }},
+		{"gtfs_stop_times", func() (int64, error) { return qtx.InsertGtfsStopTimes(ctx, feed.stopTimes) }},
+		{"gtfs_calendar", func() (int64, error) { return qtx.InsertGtfsCalendar(ctx, feed.calendar) }},
+		{"gtfs_calendar_dates", func() (int64, error) {
+			return qtx.InsertGtfsCalendarDates(ctx, feed.calendarDates)
+		}},
+	}
+	for _, c := range copies {
+		if _, err := c.copy(); err != nil {
+			return fmt.Errorf("error during COPY FROM into %s: %v", c.table, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit transaction: %v", err)
+	}
+	return nil
+}
+
+// GtfsSummary is the outcome of a GTFS import
+type GtfsSummary struct {
+	Routes, LinkedRoutes, BusRoutes int
+	Stops, LinkedStops, BusStops    int
+	Trips                           int
+	StopTimes                       int
+	Services, Exceptions            int
+}
+
+// Print writes the summary to w
+func (s GtfsSummary) Print(w io.Writer) {
+	fmt.Fprintf(w, "routes      %d, matching %d of %d ZTBus routes\n",
+		s.Routes, s.LinkedRoutes, s.BusRoutes)
+	fmt.Fprintf(w, "stops       %d, matching %d of %d ZTBus stops\n",
+		s.Stops, s.LinkedStops, s.BusStops)
+	fmt.Fprintf(w, "trips       %d\n", s.Trips)
+	fmt.Fprintf(w, "stop times  %d\n", s.StopTimes)
+	fmt.Fprintf(w, "services    %d (%d exceptions)\n", s.Services, s.Exceptions)
+}
+
+// ImportGtfs replaces the stored timetable with the GTFS feed in file, linking
+// its routes and stops to the loaded ZTBus ones. Unless allRoutes is set only
+// the trips of linked routes are imported.
+func ImportGtfs(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	file string,
+	allRoutes bool,
+) (GtfsSummary, error) {
+	q := postgres.New(pool)
+	routes, err := q.ListRoutes(ctx)
+	if err != nil {
+		return GtfsSummary{}, fmt.Errorf("could not list routes: %v", err)
+	}
+	routeIDs := make(map[string]int32, len(routes))
+	for _, r := range routes {
+		routeIDs[r.RouteCode.String] = r.ID
+	}
+	stops, err := q.ListStops(ctx)
+	if err != nil {
+		return GtfsSummary{}, fmt.Errorf("could not list stops: %v", err)
+	}
+
+	feed, err := readGtfsFeed(file, routeIDs, stops, allRoutes)
+	if err != nil {
+		return GtfsSummary{}, err
+	}
+	if err := storeGtfsFeed(ctx, pool, feed); err != nil {
+		return GtfsSummary{}, err
+	}
+
+	linkedRoutes, linkedStops := map[int32]bool{}, map[int32]bool{}
+	for _, r := range feed.routes {
+		if r.BusRouteID.Valid {
+			linkedRoutes[r.BusRouteID.Int32] = true
+		}
+	}
+	for _, s := range feed.stops {
+		if s.StopID.Valid {
+			linkedStops[s.StopID.Int32] = true
+		}
+	}
+	for _, s := range stops {
+		if !linkedStops[s.ID] {
+			slog.Debug("no GTFS stop matches stop", "stop", s.Name)
+		}
+	}
+
+	return GtfsSummary{
+		Routes:       len(feed.routes),
+		LinkedRoutes: len(linkedRoutes),
+		BusRoutes:    len(routes),
+		Stops:        len(feed.stops),
+		LinkedStops:  len(linkedStops),
+		BusStops:     len(stops),
+		Trips:        len(feed.trips),
+		StopTimes:    len(feed.stopTimes),
+		Services:     len(feed.calendar),
+		Exceptions:   len(feed.calendarDates),
+	}, nil
+}
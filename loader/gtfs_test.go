@@ -0,0 +1,129 @@
+package loader
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+)
+
+func TestGtfsTime(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int32
+		wantErr bool
+	}{
+		{"06:05:30", 6*3600 + 5*60 + 30, false},
+		{"6:05:30", 6*3600 + 5*60 + 30, false},
+		{"25:10:00", 25*3600 + 10*60, false},
+		{"06:05", 0, true},
+		{"06:xx:00", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := gtfsTime(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("gtfsTime(%q) = %d, %v, want %d, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestStopNameKey(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Bahnhofquai/HB", "bahnhofquai/hb"},
+		{"Zürich, Bahnhofquai/HB", "bahnhofquai/hb"},
+		{"  Zürich,  Central ", "central"},
+		{"Zürich, Rote  Fabrik", "rote fabrik"},
+	}
+	for _, tt := range tests {
+		if got := stopNameKey(tt.in); got != tt.want {
+			t.Errorf("stopNameKey(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// writeGtfsFeed zips files, by name, into a feed
+func writeGtfsFeed(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "gtfs.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadGtfsFeed(t *testing.T) {
+	files := map[string]string{
+		"feed/routes.txt": "\ufeffroute_id,route_short_name,route_type\n" +
+			"r31,31,700\n" +
+			"r4,4,900\n" +
+			"r33,33,3\n",
+		"feed/stops.txt": "stop_id,stop_name,stop_lat,stop_lon,location_type,parent_station\n" +
+			"s1,\"Zürich, Bahnhofquai/HB\",47.37,8.54,1,\n" +
+			"s1a,Bahnhofquai/HB Kante A,47.37,8.54,0,s1\n" +
+			"s2,Central,47.38,8.54,0,\n",
+		"feed/trips.txt": "route_id,service_id,trip_id,direction_id\n" +
+			"r31,daily,t1,0\n" +
+			"r4,daily,t2,1\n",
+		"feed/stop_times.txt": "trip_id,arrival_time,departure_time,stop_id,stop_sequence\n" +
+			"t1,06:00:00,06:00:30,s1a,1\n" +
+			"t1,,,s2,2\n" +
+			"t1,,24:05:00,s2,3\n" +
+			"t2,06:00:00,06:00:00,s1a,1\n",
+		"feed/calendar_dates.txt": "service_id,date,exception_type\n" +
+			"daily,20190608,1\n",
+	}
+	routeIDs := map[string]int32{"31": 1, "4": 2}
+	stops := []postgres.Stop{{ID: 10, Name: "Bahnhofquai/HB"}, {ID: 11, Name: "Central"}}
+
+	feed, err := readGtfsFeed(writeGtfsFeed(t, files), routeIDs, stops, false)
+	if err != nil {
+		t.Fatalf("readGtfsFeed() error = %v", err)
+	}
+
+	// route 4 is a tram, so it is not linked and its trip is left out
+	if !feed.routes[0].BusRouteID.Valid || feed.routes[1].BusRouteID.Valid || feed.routes[2].BusRouteID.Valid {
+		t.Errorf("linked routes = %+v", feed.routes)
+	}
+	if len(feed.trips) != 1 || feed.trips[0].ID != "t1" {
+		t.Errorf("trips = %+v, want t1 only", feed.trips)
+	}
+	// the platform follows its station, which matches without the town
+	wantStops := map[string]int32{"s1": 10, "s1a": 10, "s2": 11}
+	for _, s := range feed.stops {
+		if !s.StopID.Valid || s.StopID.Int32 != wantStops[s.ID] {
+			t.Errorf("stop %s linked to %+v, want %d", s.ID, s.StopID, wantStops[s.ID])
+		}
+	}
+	// the stop without times is skipped and a missing arrival takes the departure
+	if len(feed.stopTimes) != 2 {
+		t.Fatalf("stop times = %+v, want 2", feed.stopTimes)
+	}
+	if st := feed.stopTimes[1]; st.ArrivalS != 24*3600+5*60 || st.DepartureS != st.ArrivalS {
+		t.Errorf("stop time = %+v, want arrival and departure at 24:05:00", st)
+	}
+	if len(feed.calendar) != 0 || len(feed.calendarDates) != 1 {
+		t.Errorf("calendar = %d entries and %d dates, want 0 and 1", len(feed.calendar), len(feed.calendarDates))
+	}
+
+	delete(files, "feed/calendar_dates.txt")
+	if _, err := readGtfsFeed(writeGtfsFeed(t, files), routeIDs, stops, false); err == nil {
+		t.Error("readGtfsFeed() of a feed without a calendar returned no error")
+	}
+}
@@ -0,0 +1,118 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testPool connects to the database in ORCA_TEST_CONN_STR, e.g. the one from
+// docker-compose.yml, and skips the test if it is not set
+func testPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	connStr := os.Getenv("ORCA_TEST_CONN_STR")
+	if connStr == "" {
+		t.Skip("ORCA_TEST_CONN_STR is not set")
+	}
+	if err := postgres.Migrate(connStr); err != nil {
+		t.Fatalf("could not migrate: %v", err)
+	}
+	pool, err := pgxpool.New(context.Background(), connStr)
+	if err != nil {
+		t.Fatalf("could not connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+const testTripName = "B183_2019-06-08_13-20-00"
+
+// writeTestTrip writes metaData.csv and a 10 s trip to dir
+func writeTestTrip(t *testing.T, dir string, energy int) {
+	t.Helper()
+	meta := strings.Join([]string{
+		"name,busNumber,startTime_unix,endTime_unix,drivenDistance,busRoute,energyConsumption," +
+			"itcs_numberOfPassengers_mean,itcs_numberOfPassengers_min,itcs_numberOfPassengers_max," +
+			"status_gridIsAvailable_mean,temperature_ambient_mean,temperature_ambient_min," +
+			"temperature_ambient_max",
+		fmt.Sprintf("%s,183,%d,%d,90,31,%d,5,5,5,1,290,290,290", testTripName, t0, t0+9, energy),
+	}, "\n")
+	if err := os.WriteFile(filepath.Join(dir, "metaData.csv"), []byte(meta), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := []string{"time_unix,electric_powerDemand,gnss_altitude,gnss_course,gnss_latitude," +
+		"gnss_longitude,itcs_busRoute,itcs_numberOfPassengers,itcs_stopName," +
+		"odometry_articulationAngle,odometry_steeringAngle,odometry_vehicleSpeed," +
+		"odometry_wheelSpeed_fl,odometry_wheelSpeed_fr,odometry_wheelSpeed_ml," +
+		"odometry_wheelSpeed_mr,odometry_wheelSpeed_rl,odometry_wheelSpeed_rr,status_doorIsOpen," +
+		"status_gridIsAvailable,status_haltBrakeIsActive,status_parkBrakeIsActive," +
+		"temperature_ambient,traction_brakePressure,traction_tractionForce"}
+	for i := range 10 {
+		rows = append(rows, fmt.Sprintf(
+			"%d,%d,NaN,NaN,NaN,NaN,31,5,-,0,0,10,10,10,10,10,10,10,false,true,false,false,290,0,0",
+			t0+i,
+			energy/9,
+		))
+	}
+	path := filepath.Join(dir, testTripName+".csv")
+	if err := os.WriteFile(path, []byte(strings.Join(rows, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadTripTwice(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	opts := DefaultOptions()
+	opts.DataDir = dir
+
+	var tripID int32
+	for i, energy := range []int{9_000, 18_000} {
+		// the second load carries changed metadata and telemetry
+		writeTestTrip(t, dir, energy)
+		summary, err := Load(ctx, pool, opts)
+		if err != nil {
+			t.Fatalf("load %d: %v", i+1, err)
+		}
+		if summary.LoadedTrips != 1 {
+			t.Fatalf("load %d: loaded %d trips, want 1", i+1, summary.LoadedTrips)
+		}
+
+		q := postgres.New(pool)
+		trip, err := q.GetTripByName(ctx, testTripName)
+		if err != nil {
+			t.Fatalf("load %d: could not get trip: %v", i+1, err)
+		}
+		if i == 0 {
+			tripID = trip.ID
+		} else if trip.ID != tripID {
+			t.Errorf("reload changed the trip id from %d to %d", tripID, trip.ID)
+		}
+		if int(trip.EnergyConsumptionKwh.Int32) != energy {
+			t.Errorf("load %d: energy = %d, want %d", i+1, trip.EnergyConsumptionKwh.Int32, energy)
+		}
+
+		telemetry, err := q.GetTelemetryByTrip(ctx, trip.ID)
+		if err != nil {
+			t.Fatalf("load %d: could not get telemetry: %v", i+1, err)
+		}
+		if len(telemetry) != 10 {
+			t.Errorf("load %d: %d telemetry rows, want 10", i+1, len(telemetry))
+		}
+		for _, r := range telemetry {
+			if int(r.ElectricPowerDemand.Float32) != energy/9 {
+				t.Errorf("load %d: power = %g, want %d", i+1, r.ElectricPowerDemand.Float32, energy/9)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,490 @@
+// Package loader loads the ZTBus dataset into PostgreSQL. Load reads
+// metaData.csv and the telemetry of each selected trip, runs the requested
+// checks and derived tables and writes everything through store/postgres.
+package loader
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/schollz/progressbar/v3"
+)
+
+// Options selects the trips to load and the steps to run on them. String
+// values take the same formats as the command line flags of the same name.
+// Start from DefaultOptions, as some tuning values have no usable zero value.
+type Options struct {
+	DataDir string // folder holding metaData.csv and a CSV per trip
+
+	// trip selection
+	Bus      []string // bus numbers
+	Route    []string // route codes
+	From     string   // trips starting at or after, YYYY-MM-DD or RFC3339
+	To       string   // trips ending at or before, YYYY-MM-DD or RFC3339
+	TripGlob string   // trip name pattern, e.g. 'B183_2019-06-*'
+	Limit    int      // at most this many trips after filtering, 0 for all
+
+	// random sampling
+	Sample     float64 // fraction of the selected trips, 0 for all
+	Seed       int64
+	Stratify   bool // sample by bus and route
+	SampleName string
+
+	// ingest time resampling
+	Resample     string // window size, e.g. 10s, empty for none
+	ResampleMode string // ResampleModeRollup or ResampleModeReplace
+
+	// post load steps
+	Rollups    bool
+	SnapRoutes bool
+
+	// bulk loading
+	BulkLoad bool
+	Unlogged bool
+
+	// quality checks
+	Verify          bool
+	VerifyTolerance float64
+	CheckTimeline   bool
+	GapThreshold    time.Duration
+	SortTelemetry   bool
+	DedupeTelemetry bool
+	Plausibility    bool
+	RulesFile       string // JSON plausibility rules, empty for the built in rules
+	NullImplausible bool
+
+	Progress bool // draw a progress bar on stderr
+}
+
+// DefaultOptions returns the options the command line starts from
+func DefaultOptions() Options {
+	return Options{
+		Seed:            42,
+		ResampleMode:    ResampleModeRollup,
+		VerifyTolerance: 0.02,
+		GapThreshold:    time.Second,
+	}
+}
+
+// newBulkLoad builds a BulkLoad from the load options
+func newBulkLoad(opts Options) (postgres.BulkLoad, error) {
+	if opts.Unlogged && !opts.BulkLoad {
+		return postgres.BulkLoad{}, fmt.Errorf("option Unlogged requires BulkLoad")
+	}
+	return postgres.BulkLoad{DeferIndexes: opts.BulkLoad, Unlogged: opts.Unlogged}, nil
+}
+
+// Validate checks the options without touching the data or the database
+func (opts Options) Validate() error {
+	if _, err := NewTripFilter(opts); err != nil {
+		return fmt.Errorf("invalid trip filter: %w", err)
+	}
+
+	if _, err := NewTripSample(opts); err != nil {
+		return fmt.Errorf("invalid sample: %w", err)
+	}
+
+	if _, err := NewResampler(opts); err != nil {
+		return fmt.Errorf("invalid resampling: %w", err)
+	}
+
+	if _, err := NewMetadataCheck(opts); err != nil {
+		return fmt.Errorf("invalid verification: %w", err)
+	}
+
+	if _, err := NewTimelineCheck(opts); err != nil {
+		return fmt.Errorf("invalid timeline check: %w", err)
+	}
+
+	if _, err := NewPlausibilityCheck(opts); err != nil {
+		return fmt.Errorf("invalid plausibility check: %w", err)
+	}
+
+	if _, err := newBulkLoad(opts); err != nil {
+		return fmt.Errorf("invalid bulk load: %w", err)
+	}
+
+	return nil
+}
+
+// Load loads the trips selected by opts into the database behind pool. The
+// pool should allow at least WorkerCount connections for the telemetry
+// writers plus a few for trip management. The summary is returned also on
+// error, covering the trips loaded until then.
+func Load(ctx context.Context, pool *pgxpool.Pool, opts Options) (LoadSummary, error) {
+	var summary LoadSummary
+	start := time.Now()
+	err := load(ctx, pool, opts, &summary)
+	summary.Duration = time.Since(start)
+	return summary, err
+}
+
+func load(ctx context.Context, pool *pgxpool.Pool, opts Options, summary *LoadSummary) error {
+	metadata, err := ztbus.ParseMetadataCSV(filepath.Join(opts.DataDir, "metaData.csv"))
+	if err != nil {
+		return fmt.Errorf("could not parse metadata CSV: %v", err)
+	}
+
+	// narrow down the trips to load
+	filter, err := NewTripFilter(opts)
+	if err != nil {
+		return fmt.Errorf("invalid trip filter: %v", err)
+	}
+	summary.MetadataTrips = len(metadata)
+	metadata, summary.Filters = filter.Apply(metadata)
+	if !filter.IsEmpty() {
+		slog.Info(
+			"filtered trips",
+			"filters", filter.String(),
+			"selected", len(metadata),
+			"total", summary.MetadataTrips,
+		)
+	}
+
+	sample, err := NewTripSample(opts)
+	if err != nil {
+		return fmt.Errorf("invalid sample: %v", err)
+	}
+	if sample.Enabled() {
+		metadata = sample.Draw(metadata)
+		summary.Filters = append(summary.Filters, FilterStep{
+			Name:      "sample",
+			Value:     sample.String(),
+			Remaining: len(metadata),
+		})
+		slog.Info("sampled trips", "sample", sample.String(), "selected", len(metadata))
+	}
+
+	resampler, err := NewResampler(opts)
+	if err != nil {
+		return fmt.Errorf("invalid resampling: %v", err)
+	}
+	if resampler.Enabled() {
+		summary.Resample = resampler.String()
+	}
+
+	metadataCheck, err := NewMetadataCheck(opts)
+	if err != nil {
+		return fmt.Errorf("invalid verification: %v", err)
+	}
+
+	timelineCheck, err := NewTimelineCheck(opts)
+	if err != nil {
+		return fmt.Errorf("invalid timeline check: %v", err)
+	}
+
+	plausibilityCheck, err := NewPlausibilityCheck(opts)
+	if err != nil {
+		return fmt.Errorf("invalid plausibility check: %v", err)
+	}
+
+	bulkLoad, err := newBulkLoad(opts)
+	if err != nil {
+		return fmt.Errorf("invalid bulk load: %v", err)
+	}
+
+	// create a single connection for trip management (non-telemetry operations)
+	tripConn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring trip connection: %v", err)
+	}
+	defer tripConn.Release()
+
+	// record the sample so others can reproduce the same slice of trips
+	var sampleSetID int32
+	if sample.Enabled() {
+		q := postgres.New(tripConn)
+		sampleSetID, err = q.CreateSampleSet(ctx, postgres.CreateSampleSetParams{
+			Name:       sample.Name,
+			Fraction:   sample.Fraction,
+			Seed:       sample.Seed,
+			Stratified: sample.Stratified,
+			Filters:    pgtype.Text{String: filter.String(), Valid: !filter.IsEmpty()},
+		})
+		if err != nil {
+			return fmt.Errorf("could not create sample set: %v", err)
+		}
+		if err := q.ClearSampleSetTrips(ctx, sampleSetID); err != nil {
+			return fmt.Errorf("could not clear sample set: %v", err)
+		}
+	}
+
+	// partitions must exist before loading, or telemetry lands in the default partition
+	if err := postgres.PreparePartitions(ctx, pool, metadata); err != nil {
+		return err
+	}
+
+	bulkLoadFinished := false
+	if bulkLoad.Enabled() {
+		if err := bulkLoad.Prepare(ctx, pool); err != nil {
+			return fmt.Errorf("could not prepare bulk load: %v", err)
+		}
+		defer func() {
+			// restore the indexes even if the load failed part way
+			if bulkLoadFinished {
+				return
+			}
+			if err := bulkLoad.Finish(ctx, pool); err != nil {
+				slog.Error("could not finish bulk load, rerun with BulkLoad", "error", err)
+			}
+		}()
+	}
+
+	// for each trip
+	var loadedTripIDs []int32
+	description := "loading trips"
+	if !filter.IsEmpty() {
+		description = fmt.Sprintf("loading trips (%s)", filter.String())
+	}
+	if sample.Enabled() {
+		description += fmt.Sprintf(" [sample %s]", sample.Name)
+	}
+	bar := progressbar.DefaultSilent(int64(len(metadata)), description)
+	if opts.Progress {
+		bar = progressbar.Default(int64(len(metadata)), description)
+	}
+	for _, m := range metadata {
+		bar.Add(1)
+
+		// Start transaction for trip creation
+		tx, err := tripConn.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("could not start the transaction: %v", err)
+		}
+
+		qtx := postgres.New(tx)
+
+		// add bus
+		busID, err := qtx.CreateBus(ctx, pgtype.Text{String: m.BusNumber, Valid: true})
+		if err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("could not create bus id: %v", err)
+		}
+
+		// add route
+		routeID, err := qtx.CreateRoute(ctx, pgtype.Text{String: m.BusRoute, Valid: true})
+		if err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("could not create route id: %v", err)
+		}
+
+		// grab the trip info for this metadata
+		tripID, err := qtx.CreateTrip(ctx, postgres.CreateTripParams{
+			Name:    m.Name,
+			BusID:   pgtype.Int4{Int32: busID, Valid: true},
+			RouteID: pgtype.Int4{Int32: routeID, Valid: true},
+			StartTime: pgtype.Timestamptz{
+				Time:  time.Unix(int64(m.StartTimeUnix), 0),
+				Valid: true,
+			},
+			EndTime: pgtype.Timestamptz{
+				Time:  time.Unix(int64(m.EndTimeUnix), 0),
+				Valid: true,
+			},
+			DrivenDistanceKm: pgtype.Float4{
+				Float32: float32(m.DrivenDistance),
+				Valid:   true,
+			},
+			EnergyConsumptionKWh: pgtype.Int4{
+				Int32: int32(m.EnergyConsumption),
+				Valid: true,
+			},
+			ItcsPassengersMean: pgtype.Float4{
+				Float32: float32(m.ItcsNumberOfPassengersMean),
+				Valid:   true,
+			},
+			ItcsPassengersMin: pgtype.Int4{
+				Int32: int32(m.ItcsNumberOfPassengersMin),
+				Valid: true,
+			},
+			ItcsPassengersMax: pgtype.Int4{
+				Int32: int32(m.ItcsNumberOfPassengersMax),
+				Valid: true,
+			},
+			GridAvailableMean: pgtype.Float4{
+				Float32: float32(m.StatusGridIsAvailableMean),
+				Valid:   true,
+			},
+			TemperatureMean: pgtype.Float4{
+				Float32: float32(m.TemperatureAmbientMean),
+				Valid:   true,
+			},
+			TemperatureMin: pgtype.Float4{
+				Float32: float32(m.TemperatureAmbientMin),
+				Valid:   true,
+			},
+			TemperatureMax: pgtype.Float4{
+				Float32: float32(m.TemperatureAmbientMax),
+				Valid:   true,
+			},
+		})
+		if err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("could not create trip: %v", err)
+		}
+
+		if sample.Enabled() {
+			err = qtx.AddTripToSampleSet(ctx, postgres.AddTripToSampleSetParams{
+				SampleSetID: sampleSetID,
+				TripID:      tripID,
+			})
+			if err != nil {
+				tx.Rollback(ctx)
+				return fmt.Errorf("could not add trip to sample set: %v", err)
+			}
+		}
+
+		// Commit trip creation transaction
+		err = tx.Commit(ctx)
+		if err != nil {
+			return fmt.Errorf("could not commit trip transaction: %v", err)
+		}
+
+		// Now handle telemetry data with parallel processing
+		tripTelemetry, err := ztbus.ParseTripTelemetryCSV(filepath.Join(opts.DataDir, m.Name+".csv"))
+		if err != nil {
+			return fmt.Errorf("could not parse telemetry CSV for trip %s: %v", m.Name, err)
+		}
+
+		if len(tripTelemetry) == 0 {
+			slog.Debug("No telemetry data for trip", "trip", m.Name)
+			summary.SkippedTrips++
+			continue
+		}
+
+		if timelineCheck.Enabled() {
+			var report TimelineReport
+			report, tripTelemetry = timelineCheck.Apply(tripID, tripTelemetry)
+			if err := writeTimelineReport(ctx, pool, tripID, report); err != nil {
+				return fmt.Errorf("trip %s: %v", m.Name, err)
+			}
+			summary.AddTimeline(report)
+		}
+
+		if plausibilityCheck.Enabled() {
+			report := plausibilityCheck.Apply(tripID, tripTelemetry)
+			if err := WritePlausibilityReport(ctx, pool, tripID, report); err != nil {
+				return fmt.Errorf("trip %s: %v", m.Name, err)
+			}
+			if report.Total > 0 {
+				slog.Info(
+					"implausible telemetry",
+					"trip", m.Name,
+					"violations", report.Total,
+					"nulled", report.Nulled,
+				)
+			}
+			summary.AddPlausibility(report)
+		}
+
+		if metadataCheck.Enabled() {
+			quality := metadataCheck.Check(tripID, m, tripTelemetry)
+			if err := writeTripQuality(ctx, pool, quality); err != nil {
+				return fmt.Errorf("trip %s: %v", m.Name, err)
+			}
+			summary.AddQuality(m.Name, quality)
+		}
+
+		stopIDs, err := postgres.ResolveStops(ctx, pool, tripTelemetry)
+		if err != nil {
+			return fmt.Errorf("trip %s: %v", m.Name, err)
+		}
+		events := ExtractStopEvents(tripID, stopIDs, tripTelemetry)
+		if err := writeStopEvents(ctx, pool, tripID, events); err != nil {
+			return fmt.Errorf("trip %s: %v", m.Name, err)
+		}
+		summary.StopEvents += len(events)
+
+		segments := SegmentTrip(tripID, events, tripTelemetry)
+		if err := writeTripSegments(ctx, pool, tripID, segments); err != nil {
+			return fmt.Errorf("trip %s: %v", m.Name, err)
+		}
+		summary.TripSegments += len(segments)
+
+		energy := ComputeTripEnergy(tripID, tripTelemetry)
+		if err := writeTripEnergy(ctx, pool, energy); err != nil {
+			return fmt.Errorf("trip %s: %v", m.Name, err)
+		}
+		summary.EnergyKwh += energy.EnergyKwh
+
+		if resampler.KeepRaw() {
+			err := postgres.WriteTelemetry(ctx, pool, tripID, m.Name, stopIDs, tripTelemetry)
+			if err != nil {
+				return err
+			}
+			summary.TelemetryRecords += int64(len(tripTelemetry))
+		}
+
+		if resampler.Enabled() {
+			windows := resampler.Windows(
+				tripID,
+				pgtype.Int4{Int32: routeID, Valid: true},
+				tripTelemetry,
+			)
+			count, err := writeResampledTelemetry(ctx, pool, tripID, windows)
+			if err != nil {
+				return fmt.Errorf("could not write resampled telemetry for trip %s: %v", m.Name, err)
+			}
+			summary.ResampledWindows += count
+		}
+
+		summary.LoadedTrips++
+		loadedTripIDs = append(loadedTripIDs, tripID)
+		slog.Debug(
+			"Successfully processed trip",
+			"trip",
+			m.Name,
+			"telemetry_records",
+			len(tripTelemetry),
+		)
+	}
+
+	slog.Debug("Data load completed successfully")
+
+	if bulkLoad.Enabled() {
+		bulkLoadFinished = true
+		if err := bulkLoad.Finish(ctx, pool); err != nil {
+			return fmt.Errorf("could not finish bulk load: %v", err)
+		}
+	}
+
+	if len(loadedTripIDs) > 0 {
+		// without raw telemetry the stop visits come from the resampled windows
+		var resolutionS int32
+		if !resampler.KeepRaw() {
+			resolutionS = int32(resampler.Resolution / time.Second)
+		}
+		summary.Stops, err = postgres.RefreshStops(ctx, pool, loadedTripIDs, resolutionS)
+		if err != nil {
+			return fmt.Errorf("could not refresh stops: %v", err)
+		}
+	}
+
+	if opts.Rollups {
+		summary.RollupTrips, err = postgres.RefreshRollups(ctx, pool, loadedTripIDs)
+		if err != nil {
+			return fmt.Errorf("could not refresh rollups: %v", err)
+		}
+	}
+
+	if opts.SnapRoutes && len(loadedTripIDs) > 0 {
+		summary.SnappedTrips, err = SnapTrips(ctx, pool, loadedTripIDs)
+		if err != nil {
+			return fmt.Errorf("could not snap trips to route shapes: %v", err)
+		}
+	}
+
+	summary.Partitions, err = postgres.FinishPartitions(ctx, pool)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
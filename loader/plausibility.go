@@ -0,0 +1,337 @@
+package loader
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed rules/default.json
+var defaultPlausibilityRules []byte
+
+// kinds of plausibility violations
+const (
+	ViolationRange     = "range"
+	ViolationRate      = "rate"
+	ViolationDeviation = "deviation"
+)
+
+// telemetryField reads and clears a numeric TripTelemetry field. Required
+// fields are cleared by setting them to NaN, which is loaded as NULL.
+type telemetryField struct {
+	get   func(*ztbus.TripTelemetry) (float64, bool)
+	clear func(*ztbus.TripTelemetry)
+}
+
+func requiredField(p func(*ztbus.TripTelemetry) *float64) telemetryField {
+	return telemetryField{
+		get: func(t *ztbus.TripTelemetry) (float64, bool) {
+			v := *p(t)
+			return v, !math.IsNaN(v)
+		},
+		clear: func(t *ztbus.TripTelemetry) { *p(t) = math.NaN() },
+	}
+}
+
+func optionalField(p func(*ztbus.TripTelemetry) **float64) telemetryField {
+	return telemetryField{
+		get: func(t *ztbus.TripTelemetry) (float64, bool) {
+			if v := *p(t); v != nil {
+				return *v, true
+			}
+			return 0, false
+		},
+		clear: func(t *ztbus.TripTelemetry) { *p(t) = nil },
+	}
+}
+
+// numeric telemetry fields addressable from a rules file, by column name
+var telemetryFields = map[string]telemetryField{
+	"electric_power_demand": requiredField(func(t *ztbus.TripTelemetry) *float64 {
+		return &t.ElectricPowerDemand
+	}),
+	"gnss_altitude":  optionalField(func(t *ztbus.TripTelemetry) **float64 { return &t.GnssAltitude }),
+	"gnss_course":    optionalField(func(t *ztbus.TripTelemetry) **float64 { return &t.GnssCourse }),
+	"gnss_latitude":  optionalField(func(t *ztbus.TripTelemetry) **float64 { return &t.GnssLatitude }),
+	"gnss_longitude": optionalField(func(t *ztbus.TripTelemetry) **float64 { return &t.GnssLongitude }),
+	"itcs_number_of_passengers": {
+		get: func(t *ztbus.TripTelemetry) (float64, bool) {
+			if t.ItcsNumberOfPassengers != nil {
+				return float64(*t.ItcsNumberOfPassengers), true
+			}
+			return 0, false
+		},
+		clear: func(t *ztbus.TripTelemetry) { t.ItcsNumberOfPassengers = nil },
+	},
+	"odometry_articulation_angle": requiredField(func(t *ztbus.TripTelemetry) *float64 {
+		return &t.OdometryArticulationAngle
+	}),
+	"odometry_steering_angle": requiredField(func(t *ztbus.TripTelemetry) *float64 {
+		return &t.OdometrySteeringAngle
+	}),
+	"odometry_vehicle_speed": requiredField(func(t *ztbus.TripTelemetry) *float64 {
+		return &t.OdometryVehicleSpeed
+	}),
+	"odometry_wheel_speed_fl": requiredField(func(t *ztbus.TripTelemetry) *float64 {
+		return &t.OdometryWheelSpeedFl
+	}),
+	"odometry_wheel_speed_fr": requiredField(func(t *ztbus.TripTelemetry) *float64 {
+		return &t.OdometryWheelSpeedFr
+	}),
+	"odometry_wheel_speed_ml": requiredField(func(t *ztbus.TripTelemetry) *float64 {
+		return &t.OdometryWheelSpeedMl
+	}),
+	"odometry_wheel_speed_mr": requiredField(func(t *ztbus.TripTelemetry) *float64 {
+		return &t.OdometryWheelSpeedMr
+	}),
+	"odometry_wheel_speed_rl": requiredField(func(t *ztbus.TripTelemetry) *float64 {
+		return &t.OdometryWheelSpeedRl
+	}),
+	"odometry_wheel_speed_rr": requiredField(func(t *ztbus.TripTelemetry) *float64 {
+		return &t.OdometryWheelSpeedRr
+	}),
+	"temperature_ambient": requiredField(func(t *ztbus.TripTelemetry) *float64 {
+		return &t.TemperatureAmbient
+	}),
+	"traction_brake_pressure": requiredField(func(t *ztbus.TripTelemetry) *float64 {
+		return &t.TractionBrakePressure
+	}),
+	"traction_traction_force": requiredField(func(t *ztbus.TripTelemetry) *float64 {
+		return &t.TractionTractionForce
+	}),
+}
+
+// PlausibilityRule describes the valid values of one telemetry field. All
+// limits are optional and in ZTBus units (m/s, rad, W, K, Pa, N).
+type PlausibilityRule struct {
+	Field   string `json:"field"`
+	Comment string `json:"comment,omitempty"`
+
+	// valid range
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+
+	// largest absolute change per second between consecutive samples
+	MaxRate *float64 `json:"max_rate,omitempty"`
+
+	// largest absolute difference to another field, only checked while the
+	// reference is at least ReferenceMin
+	Reference    string   `json:"reference,omitempty"`
+	ReferenceMin *float64 `json:"reference_min,omitempty"`
+	MaxDeviation *float64 `json:"max_deviation,omitempty"`
+}
+
+// PlausibilityRules is the content of a rules file
+type PlausibilityRules struct {
+	Rules []PlausibilityRule `json:"rules"`
+}
+
+// LoadPlausibilityRules reads a rules file, or the built in rules if path is empty
+func LoadPlausibilityRules(path string) (PlausibilityRules, error) {
+	data := defaultPlausibilityRules
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return PlausibilityRules{}, fmt.Errorf("could not read rules file: %w", err)
+		}
+	}
+
+	var rules PlausibilityRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return PlausibilityRules{}, fmt.Errorf("could not parse rules file: %w", err)
+	}
+	for i, r := range rules.Rules {
+		if _, ok := telemetryFields[r.Field]; !ok {
+			return PlausibilityRules{}, fmt.Errorf("rule %d: unknown field '%s'", i, r.Field)
+		}
+		if r.MaxDeviation != nil || r.Reference != "" {
+			if _, ok := telemetryFields[r.Reference]; !ok {
+				return PlausibilityRules{}, fmt.Errorf(
+					"rule %d: unknown reference field '%s'",
+					i,
+					r.Reference,
+				)
+			}
+			if r.MaxDeviation == nil {
+				return PlausibilityRules{}, fmt.Errorf("rule %d: reference without max_deviation", i)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// PlausibilityCheck applies plausibility rules to a trip's telemetry
+type PlausibilityCheck struct {
+	Rules PlausibilityRules
+	Null  bool // clear violating values before loading
+}
+
+// NewPlausibilityCheck builds a PlausibilityCheck from the load options
+func NewPlausibilityCheck(opts Options) (PlausibilityCheck, error) {
+	if !opts.Plausibility && opts.RulesFile == "" {
+		if opts.NullImplausible {
+			return PlausibilityCheck{}, fmt.Errorf("option NullImplausible requires Plausibility")
+		}
+		return PlausibilityCheck{}, nil
+	}
+	rules, err := LoadPlausibilityRules(opts.RulesFile)
+	if err != nil {
+		return PlausibilityCheck{}, err
+	}
+	return PlausibilityCheck{Rules: rules, Null: opts.NullImplausible}, nil
+}
+
+// Enabled reports whether plausibility checks were requested
+func (c PlausibilityCheck) Enabled() bool {
+	return len(c.Rules.Rules) > 0
+}
+
+// violationKey groups violations per field and kind
+type violationKey struct {
+	field, kind string
+}
+
+// sampleField addresses one field of one telemetry sample
+type sampleField struct {
+	index int
+	field string
+}
+
+// PlausibilityReport is the outcome of a PlausibilityCheck for one trip
+type PlausibilityReport struct {
+	Violations []postgres.InsertPlausibilityViolationsParams
+	Total      int
+	Nulled     int
+}
+
+// Apply checks the records against the rules. All rules are evaluated on the
+// original values, violating values are only cleared afterwards (if enabled),
+// so a cleared sample cannot hide or cause a rate violation of its neighbour.
+func (c PlausibilityCheck) Apply(tripID int32, records []ztbus.TripTelemetry) PlausibilityReport {
+	type violation struct {
+		count       int
+		first, last int
+	}
+	found := make(map[violationKey]*violation)
+	var toClear []sampleField
+
+	record := func(i int, field, kind string) {
+		key := violationKey{field, kind}
+		v, ok := found[key]
+		if !ok {
+			v = &violation{first: records[i].TimeUnix}
+			found[key] = v
+		}
+		v.count++
+		v.last = records[i].TimeUnix
+		toClear = append(toClear, sampleField{i, field})
+	}
+
+	for _, rule := range c.Rules.Rules {
+		field := telemetryFields[rule.Field]
+		for i := range records {
+			v, ok := field.get(&records[i])
+			if !ok {
+				continue
+			}
+
+			if (rule.Min != nil && v < *rule.Min) || (rule.Max != nil && v > *rule.Max) {
+				record(i, rule.Field, ViolationRange)
+			}
+
+			if rule.MaxRate != nil && i > 0 {
+				prev, ok := field.get(&records[i-1])
+				dt := float64(records[i].TimeUnix - records[i-1].TimeUnix)
+				if ok && dt > 0 && math.Abs(v-prev)/dt > *rule.MaxRate {
+					record(i, rule.Field, ViolationRate)
+				}
+			}
+
+			if rule.MaxDeviation != nil {
+				ref, ok := telemetryFields[rule.Reference].get(&records[i])
+				if ok && (rule.ReferenceMin == nil || ref >= *rule.ReferenceMin) &&
+					math.Abs(v-ref) > *rule.MaxDeviation {
+					record(i, rule.Field, ViolationDeviation)
+				}
+			}
+		}
+	}
+
+	var report PlausibilityReport
+	if c.Null {
+		cleared := make(map[sampleField]bool)
+		for _, sf := range toClear {
+			if cleared[sf] {
+				continue
+			}
+			cleared[sf] = true
+			telemetryFields[sf.field].clear(&records[sf.index])
+			report.Nulled++
+		}
+	}
+
+	keys := make([]violationKey, 0, len(found))
+	for key := range found {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].field != keys[j].field {
+			return keys[i].field < keys[j].field
+		}
+		return keys[i].kind < keys[j].kind
+	})
+	for _, key := range keys {
+		v := found[key]
+		report.Total += v.count
+		report.Violations = append(report.Violations, postgres.InsertPlausibilityViolationsParams{
+			TripID:     tripID,
+			Field:      key.field,
+			Kind:       key.kind,
+			Violations: int32(v.count),
+			FirstTime:  pgtype.Timestamptz{Time: time.Unix(int64(v.first), 0), Valid: true},
+			LastTime:   pgtype.Timestamptz{Time: time.Unix(int64(v.last), 0), Valid: true},
+			Nulled:     c.Null,
+		})
+	}
+	return report
+}
+
+// WritePlausibilityReport replaces the trip's recorded violations
+func WritePlausibilityReport(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	tripID int32,
+	report PlausibilityReport,
+) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := postgres.New(tx)
+
+	if err := qtx.DeletePlausibilityViolationsByTrip(ctx, tripID); err != nil {
+		return fmt.Errorf("could not clear plausibility violations: %v", err)
+	}
+	if len(report.Violations) > 0 {
+		if _, err := qtx.InsertPlausibilityViolations(ctx, report.Violations); err != nil {
+			return fmt.Errorf("error during COPY FROM: %v", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit transaction: %v", err)
+	}
+	return nil
+}
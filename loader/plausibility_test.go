@@ -0,0 +1,252 @@
+package loader
+
+import (
+	"math"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+)
+
+// plausibleRecord is one second of a bus in Zurich at speed, in ZTBus units
+func plausibleRecord(i int, speed float64) ztbus.TripTelemetry {
+	lat, lon := 47.37*math.Pi/180, 8.54*math.Pi/180
+	altitude, course := 410.0, 3.0
+	passengers := 30
+	return ztbus.TripTelemetry{
+		TimeUnix:                  t0 + i,
+		ElectricPowerDemand:       100_000,
+		GnssAltitude:              &altitude,
+		GnssCourse:                &course,
+		GnssLatitude:              &lat,
+		GnssLongitude:             &lon,
+		ItcsNumberOfPassengers:    &passengers,
+		OdometryArticulationAngle: 0.1,
+		OdometrySteeringAngle:     -0.2,
+		OdometryVehicleSpeed:      speed,
+		OdometryWheelSpeedFl:      speed,
+		OdometryWheelSpeedFr:      speed,
+		OdometryWheelSpeedMl:      speed,
+		OdometryWheelSpeedMr:      speed,
+		OdometryWheelSpeedRl:      speed,
+		OdometryWheelSpeedRr:      speed,
+		TemperatureAmbient:        290,
+		TractionBrakePressure:     0,
+	}
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
+
+func TestPlausibilityCheckDefaultRules(t *testing.T) {
+	rules, err := LoadPlausibilityRules("")
+	if err != nil {
+		t.Fatalf("LoadPlausibilityRules() error = %v", err)
+	}
+
+	// each case changes the second of three records at 10 m/s
+	tests := []struct {
+		name   string
+		change func(r *ztbus.TripTelemetry)
+		want   []violationKey
+	}{
+		{
+			name:   "plausible",
+			change: func(r *ztbus.TripTelemetry) {},
+		},
+		{
+			name:   "latitude in degrees",
+			change: func(r *ztbus.TripTelemetry) { r.GnssLatitude = ptr(47.37) },
+			want:   []violationKey{{"gnss_latitude", ViolationRange}},
+		},
+		{
+			name:   "course in degrees",
+			change: func(r *ztbus.TripTelemetry) { r.GnssCourse = ptr(270) },
+			want:   []violationKey{{"gnss_course", ViolationRange}},
+		},
+		{
+			name:   "temperature in celsius",
+			change: func(r *ztbus.TripTelemetry) { r.TemperatureAmbient = 17 },
+			want: []violationKey{
+				{"temperature_ambient", ViolationRange},
+				{"temperature_ambient", ViolationRate},
+			},
+		},
+		{
+			name:   "steering angle in degrees",
+			change: func(r *ztbus.TripTelemetry) { r.OdometrySteeringAngle = 15 },
+			want:   []violationKey{{"odometry_steering_angle", ViolationRange}},
+		},
+		{
+			name:   "power in kW is plausible",
+			change: func(r *ztbus.TripTelemetry) { r.ElectricPowerDemand = 100 },
+		},
+		{
+			// 10 m/s read as 36 km/h, the wheels follow it out of range
+			name: "speed in km/h",
+			change: func(r *ztbus.TripTelemetry) {
+				*r = plausibleRecord(1, 36)
+			},
+			want: []violationKey{
+				{"odometry_vehicle_speed", ViolationRange},
+				{"odometry_vehicle_speed", ViolationRate},
+				{"odometry_wheel_speed_fl", ViolationRange},
+				{"odometry_wheel_speed_fr", ViolationRange},
+				{"odometry_wheel_speed_ml", ViolationRange},
+				{"odometry_wheel_speed_mr", ViolationRange},
+				{"odometry_wheel_speed_rl", ViolationRange},
+				{"odometry_wheel_speed_rr", ViolationRange},
+			},
+		},
+		{
+			name:   "wheel slipping",
+			change: func(r *ztbus.TripTelemetry) { r.OdometryWheelSpeedFl = 14 },
+			want:   []violationKey{{"odometry_wheel_speed_fl", ViolationDeviation}},
+		},
+		{
+			name:   "unavailable values",
+			change: func(r *ztbus.TripTelemetry) { r.GnssLatitude, r.TemperatureAmbient = nil, math.NaN() },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := []ztbus.TripTelemetry{
+				plausibleRecord(0, 10),
+				plausibleRecord(1, 10),
+				plausibleRecord(2, 10),
+			}
+			tt.change(&records[1])
+
+			report := PlausibilityCheck{Rules: rules}.Apply(7, records)
+			var got []violationKey
+			for _, v := range report.Violations {
+				got = append(got, violationKey{v.Field, v.Kind})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() violations = %v, want %v", got, tt.want)
+			}
+			if report.Nulled != 0 {
+				t.Errorf("Apply() nulled %d values without Null", report.Nulled)
+			}
+		})
+	}
+}
+
+func TestPlausibilityCheckNull(t *testing.T) {
+	rules := PlausibilityRules{Rules: []PlausibilityRule{
+		{Field: "temperature_ambient", Min: ptr(243.15), Max: ptr(318.15), MaxRate: ptr(2)},
+		{Field: "gnss_altitude", Max: ptr(900)},
+	}}
+	records := []ztbus.TripTelemetry{
+		plausibleRecord(0, 10),
+		plausibleRecord(1, 10),
+		plausibleRecord(2, 10),
+		plausibleRecord(3, 10),
+	}
+	// a temperature spike is out of range and jumps both ways, and one
+	// altitude is out of range
+	records[1].TemperatureAmbient = 330
+	records[3].GnssAltitude = ptr(1200)
+
+	// a violation with its count and first and last offset from t0
+	type counted struct {
+		key         violationKey
+		count       int
+		first, last int
+	}
+
+	for _, null := range []bool{false, true} {
+		input := make([]ztbus.TripTelemetry, len(records))
+		copy(input, records)
+		report := PlausibilityCheck{Rules: rules, Null: null}.Apply(7, input)
+
+		// rules are evaluated on the original values, so the spike also
+		// flags the rate of the sample after it
+		var got []counted
+		for _, v := range report.Violations {
+			if v.Nulled != null {
+				t.Errorf("violation %s/%s nulled = %v, want %v", v.Field, v.Kind, v.Nulled, null)
+			}
+			got = append(got, counted{
+				violationKey{v.Field, v.Kind},
+				int(v.Violations),
+				int(v.FirstTime.Time.Unix() - t0),
+				int(v.LastTime.Time.Unix() - t0),
+			})
+		}
+		want := []counted{
+			{violationKey{"gnss_altitude", ViolationRange}, 1, 3, 3},
+			{violationKey{"temperature_ambient", ViolationRange}, 1, 1, 1},
+			{violationKey{"temperature_ambient", ViolationRate}, 2, 1, 2},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Null %v: violations = %v, want %v", null, got, want)
+		}
+		if report.Total != 4 {
+			t.Errorf("Null %v: total = %d, want 4", null, report.Total)
+		}
+
+		if !null {
+			if report.Nulled != 0 || input[1].TemperatureAmbient != 330 {
+				t.Errorf("values were cleared without Null")
+			}
+			continue
+		}
+		// the spike is counted once although it broke two rules
+		if report.Nulled != 3 {
+			t.Errorf("nulled %d values, want 3", report.Nulled)
+		}
+		if !math.IsNaN(input[1].TemperatureAmbient) || !math.IsNaN(input[2].TemperatureAmbient) {
+			t.Errorf("temperatures = %v, %v, want NaN", input[1].TemperatureAmbient, input[2].TemperatureAmbient)
+		}
+		if input[0].TemperatureAmbient != 290 || input[3].TemperatureAmbient != 290 {
+			t.Error("plausible temperatures were cleared")
+		}
+		if input[3].GnssAltitude != nil || input[2].GnssAltitude == nil {
+			t.Error("altitude was not cleared on the right sample")
+		}
+	}
+}
+
+func TestLoadPlausibilityRulesErrors(t *testing.T) {
+	tests := []struct {
+		name, rules string
+	}{
+		{"malformed", `{"rules": [`},
+		{"unknown field", `{"rules": [{"field": "speed", "max": 25}]}`},
+		{
+			"unknown reference",
+			`{"rules": [{"field": "odometry_wheel_speed_fl", "reference": "speed", "max_deviation": 3}]}`,
+		},
+		{
+			"reference without deviation",
+			`{"rules": [{"field": "odometry_wheel_speed_fl", "reference": "odometry_vehicle_speed"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := t.TempDir() + "/rules.json"
+			if err := os.WriteFile(path, []byte(tt.rules), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadPlausibilityRules(path); err == nil {
+				t.Error("LoadPlausibilityRules() returned no error")
+			}
+		})
+	}
+}
+
+func TestNewPlausibilityCheck(t *testing.T) {
+	if _, err := NewPlausibilityCheck(Options{NullImplausible: true}); err == nil {
+		t.Error("NullImplausible without Plausibility returned no error")
+	}
+	c, err := NewPlausibilityCheck(Options{Plausibility: true, NullImplausible: true})
+	if err != nil {
+		t.Fatalf("NewPlausibilityCheck() error = %v", err)
+	}
+	if !c.Enabled() || !c.Null {
+		t.Errorf("NewPlausibilityCheck() = enabled %v null %v, want both", c.Enabled(), c.Null)
+	}
+}
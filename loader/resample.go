@@ -0,0 +1,317 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// valid values for Options.ResampleMode
+const (
+	ResampleModeRollup  = "rollup"  // load raw telemetry and the resampled windows
+	ResampleModeReplace = "replace" // load only the resampled windows
+)
+
+// continuous signals that are aggregated to mean/min/max per window
+const (
+	sigElectricPowerDemand = iota
+	sigTemperatureAmbient
+	sigTractionBrakePressure
+	sigTractionTractionForce
+	sigGnssAltitude
+	sigGnssLatitude
+	sigGnssLongitude
+	sigItcsNumberOfPassengers
+	sigOdometryArticulationAngle
+	sigOdometrySteeringAngle
+	sigOdometryVehicleSpeed
+	sigOdometryWheelSpeedFl
+	sigOdometryWheelSpeedFr
+	sigOdometryWheelSpeedMl
+	sigOdometryWheelSpeedMr
+	sigOdometryWheelSpeedRl
+	sigOdometryWheelSpeedRr
+	numSignals
+)
+
+// boolean signals that are aggregated to the fraction of time they are true
+const (
+	flagDoorIsOpen = iota
+	flagGridIsAvailable
+	flagHaltBrakeIsActive
+	flagParkBrakeIsActive
+	numFlags
+)
+
+// Resampler aggregates 1 Hz telemetry into fixed, epoch aligned windows
+type Resampler struct {
+	Resolution time.Duration
+	Mode       string
+}
+
+// NewResampler builds a Resampler from the load options
+func NewResampler(opts Options) (Resampler, error) {
+	if opts.Resample == "" {
+		return Resampler{}, nil
+	}
+	d, err := time.ParseDuration(opts.Resample)
+	if err != nil {
+		return Resampler{}, fmt.Errorf("invalid option Resample: %w", err)
+	}
+	if d < 2*time.Second || d%time.Second != 0 {
+		return Resampler{}, fmt.Errorf(
+			"option Resample must be a whole number of seconds of at least 2s, got %s",
+			opts.Resample,
+		)
+	}
+	switch opts.ResampleMode {
+	case ResampleModeRollup, ResampleModeReplace:
+	default:
+		return Resampler{}, fmt.Errorf(
+			"option ResampleMode must be '%s' or '%s', got '%s'",
+			ResampleModeRollup,
+			ResampleModeReplace,
+			opts.ResampleMode,
+		)
+	}
+	return Resampler{Resolution: d, Mode: opts.ResampleMode}, nil
+}
+
+// Enabled reports whether resampling was requested
+func (r Resampler) Enabled() bool {
+	return r.Resolution > 0
+}
+
+// KeepRaw reports whether the raw 1 Hz telemetry should still be loaded
+func (r Resampler) KeepRaw() bool {
+	return !r.Enabled() || r.Mode == ResampleModeRollup
+}
+
+func (r Resampler) String() string {
+	return fmt.Sprintf("%s (%s)", r.Resolution, r.Mode)
+}
+
+// windowStat accumulates a continuous signal over one window
+type windowStat struct {
+	sum, min, max float64
+	n             int
+}
+
+func (s *windowStat) add(v float64) {
+	if math.IsNaN(v) {
+		// unavailable or nulled as implausible
+		return
+	}
+	if s.n == 0 || v < s.min {
+		s.min = v
+	}
+	if s.n == 0 || v > s.max {
+		s.max = v
+	}
+	s.sum += v
+	s.n++
+}
+
+func (s *windowStat) addOptional(v *float64) {
+	if v != nil {
+		s.add(*v)
+	}
+}
+
+func float4(v float64, valid bool) pgtype.Float4 {
+	return pgtype.Float4{Float32: float32(v), Valid: valid}
+}
+
+// agg returns the mean, min and max of the window. Signals that had no
+// values in the window are returned as NULL.
+func (s windowStat) agg() (pgtype.Float4, pgtype.Float4, pgtype.Float4) {
+	if s.n == 0 {
+		return pgtype.Float4{}, pgtype.Float4{}, pgtype.Float4{}
+	}
+	return float4(s.sum/float64(s.n), true), float4(s.min, true), float4(s.max, true)
+}
+
+// window accumulates all signals of one resampled row
+type window struct {
+	start    int64
+	count    int
+	stats    [numSignals]windowStat
+	flags    [numFlags]int
+	course   *float64
+	stopName *string
+
+	// times of the records course and stopName were taken from
+	courseTime, stopTime int
+}
+
+func (w *window) add(t ztbus.TripTelemetry) {
+	w.count++
+
+	w.stats[sigElectricPowerDemand].add(t.ElectricPowerDemand)
+	w.stats[sigTemperatureAmbient].add(t.TemperatureAmbient)
+	w.stats[sigTractionBrakePressure].add(t.TractionBrakePressure)
+	w.stats[sigTractionTractionForce].add(t.TractionTractionForce)
+	w.stats[sigGnssAltitude].addOptional(t.GnssAltitude)
+	w.stats[sigGnssLatitude].addOptional(t.GnssLatitude)
+	w.stats[sigGnssLongitude].addOptional(t.GnssLongitude)
+	if t.ItcsNumberOfPassengers != nil {
+		w.stats[sigItcsNumberOfPassengers].add(float64(*t.ItcsNumberOfPassengers))
+	}
+	w.stats[sigOdometryArticulationAngle].add(t.OdometryArticulationAngle)
+	w.stats[sigOdometrySteeringAngle].add(t.OdometrySteeringAngle)
+	w.stats[sigOdometryVehicleSpeed].add(t.OdometryVehicleSpeed)
+	w.stats[sigOdometryWheelSpeedFl].add(t.OdometryWheelSpeedFl)
+	w.stats[sigOdometryWheelSpeedFr].add(t.OdometryWheelSpeedFr)
+	w.stats[sigOdometryWheelSpeedMl].add(t.OdometryWheelSpeedMl)
+	w.stats[sigOdometryWheelSpeedMr].add(t.OdometryWheelSpeedMr)
+	w.stats[sigOdometryWheelSpeedRl].add(t.OdometryWheelSpeedRl)
+	w.stats[sigOdometryWheelSpeedRr].add(t.OdometryWheelSpeedRr)
+
+	for i, set := range [numFlags]bool{
+		flagDoorIsOpen:        t.StatusDoorIsOpen,
+		flagGridIsAvailable:   t.StatusGridIsAvailable,
+		flagHaltBrakeIsActive: t.StatusHaltBrakeIsActive,
+		flagParkBrakeIsActive: t.StatusParkBrakeIsActive,
+	} {
+		if set {
+			w.flags[i]++
+		}
+	}
+
+	// headings and stop names are not averaged, keep the latest value. The
+	// records may be unsorted, so compare times rather than input order.
+	if t.GnssCourse != nil && (w.course == nil || t.TimeUnix >= w.courseTime) {
+		w.course, w.courseTime = t.GnssCourse, t.TimeUnix
+	}
+	if t.ItcsStopName != nil && (w.stopName == nil || t.TimeUnix >= w.stopTime) {
+		w.stopName, w.stopTime = t.ItcsStopName, t.TimeUnix
+	}
+}
+
+func (w *window) fraction(flag int) pgtype.Float4 {
+	return float4(float64(w.flags[flag])/float64(w.count), true)
+}
+
+func (w *window) params(
+	tripID int32,
+	routeID pgtype.Int4,
+	resolution int32,
+) postgres.InsertResampledTelemetryParams {
+	p := postgres.InsertResampledTelemetryParams{
+		TripID: tripID,
+		Time: pgtype.Timestamptz{
+			Time:  time.Unix(w.start, 0),
+			Valid: true,
+		},
+		ResolutionS:                 resolution,
+		SampleCount:                 int32(w.count),
+		ItcsBusRouteID:              routeID,
+		StatusDoorIsOpenFrac:        w.fraction(flagDoorIsOpen),
+		StatusGridIsAvailableFrac:   w.fraction(flagGridIsAvailable),
+		StatusHaltBrakeIsActiveFrac: w.fraction(flagHaltBrakeIsActive),
+		StatusParkBrakeIsActiveFrac: w.fraction(flagParkBrakeIsActive),
+	}
+	if w.course != nil {
+		p.GnssCourse = float4(*w.course, true)
+	}
+	if w.stopName != nil {
+		p.ItcsStopName = pgtype.Text{String: *w.stopName, Valid: true}
+	}
+
+	p.ElectricPowerDemandMean, p.ElectricPowerDemandMin, p.ElectricPowerDemandMax = w.stats[sigElectricPowerDemand].agg()
+	p.TemperatureAmbientMean, p.TemperatureAmbientMin, p.TemperatureAmbientMax = w.stats[sigTemperatureAmbient].agg()
+	p.TractionBrakePressureMean, p.TractionBrakePressureMin, p.TractionBrakePressureMax = w.stats[sigTractionBrakePressure].agg()
+	p.TractionTractionForceMean, p.TractionTractionForceMin, p.TractionTractionForceMax = w.stats[sigTractionTractionForce].agg()
+	p.GnssAltitudeMean, p.GnssAltitudeMin, p.GnssAltitudeMax = w.stats[sigGnssAltitude].agg()
+	p.GnssLatitudeMean, p.GnssLatitudeMin, p.GnssLatitudeMax = w.stats[sigGnssLatitude].agg()
+	p.GnssLongitudeMean, p.GnssLongitudeMin, p.GnssLongitudeMax = w.stats[sigGnssLongitude].agg()
+	p.ItcsNumberOfPassengersMean, p.ItcsNumberOfPassengersMin, p.ItcsNumberOfPassengersMax = w.stats[sigItcsNumberOfPassengers].agg()
+	p.OdometryArticulationAngleMean, p.OdometryArticulationAngleMin, p.OdometryArticulationAngleMax = w.stats[sigOdometryArticulationAngle].agg()
+	p.OdometrySteeringAngleMean, p.OdometrySteeringAngleMin, p.OdometrySteeringAngleMax = w.stats[sigOdometrySteeringAngle].agg()
+	p.OdometryVehicleSpeedMean, p.OdometryVehicleSpeedMin, p.OdometryVehicleSpeedMax = w.stats[sigOdometryVehicleSpeed].agg()
+	p.OdometryWheelSpeedFlMean, p.OdometryWheelSpeedFlMin, p.OdometryWheelSpeedFlMax = w.stats[sigOdometryWheelSpeedFl].agg()
+	p.OdometryWheelSpeedFrMean, p.OdometryWheelSpeedFrMin, p.OdometryWheelSpeedFrMax = w.stats[sigOdometryWheelSpeedFr].agg()
+	p.OdometryWheelSpeedMlMean, p.OdometryWheelSpeedMlMin, p.OdometryWheelSpeedMlMax = w.stats[sigOdometryWheelSpeedMl].agg()
+	p.OdometryWheelSpeedMrMean, p.OdometryWheelSpeedMrMin, p.OdometryWheelSpeedMrMax = w.stats[sigOdometryWheelSpeedMr].agg()
+	p.OdometryWheelSpeedRlMean, p.OdometryWheelSpeedRlMin, p.OdometryWheelSpeedRlMax = w.stats[sigOdometryWheelSpeedRl].agg()
+	p.OdometryWheelSpeedRrMean, p.OdometryWheelSpeedRrMin, p.OdometryWheelSpeedRrMax = w.stats[sigOdometryWheelSpeedRr].agg()
+
+	return p
+}
+
+// Windows aggregates a trip's telemetry into rows for telemetry_resampled.
+// Windows are aligned to the unix epoch, so a 1m window always starts on a
+// full minute. Records do not need to be sorted.
+func (r Resampler) Windows(
+	tripID int32,
+	routeID pgtype.Int4,
+	records []ztbus.TripTelemetry,
+) []postgres.InsertResampledTelemetryParams {
+	res := int64(r.Resolution / time.Second)
+	windows := make(map[int64]*window)
+	for _, t := range records {
+		start := int64(math.Floor(float64(t.TimeUnix)/float64(res))) * res
+		w, ok := windows[start]
+		if !ok {
+			w = &window{start: start}
+			windows[start] = w
+		}
+		w.add(t)
+	}
+
+	starts := make([]int64, 0, len(windows))
+	for start := range windows {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+
+	out := make([]postgres.InsertResampledTelemetryParams, len(starts))
+	for i, start := range starts {
+		out[i] = windows[start].params(tripID, routeID, int32(res))
+	}
+	return out
+}
+
+// writeResampledTelemetry replaces the trip's windows at this resolution
+func writeResampledTelemetry(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	tripID int32,
+	rows []postgres.InsertResampledTelemetryParams,
+) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := postgres.New(tx)
+
+	err = qtx.DeleteResampledTelemetryByTrip(ctx, postgres.DeleteResampledTelemetryByTripParams{
+		TripID:      tripID,
+		ResolutionS: rows[0].ResolutionS,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("could not clear resampled telemetry: %v", err)
+	}
+
+	count, err := qtx.InsertResampledTelemetry(ctx, rows)
+	if err != nil {
+		return 0, fmt.Errorf("error during COPY FROM: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("could not commit transaction: %v", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,180 @@
+package loader
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// windowSample is one record of a resampled trip, offset from t0 in seconds
+type windowSample struct {
+	at     int
+	speed  float64
+	door   bool
+	course float64 // noCourse if unavailable
+	stop   string  // empty if the record has no stop name
+}
+
+func windowRecords(samples ...windowSample) []ztbus.TripTelemetry {
+	records := make([]ztbus.TripTelemetry, len(samples))
+	for i, s := range samples {
+		records[i] = ztbus.TripTelemetry{
+			TimeUnix:             t0 + s.at,
+			OdometryVehicleSpeed: s.speed,
+			StatusDoorIsOpen:     s.door,
+		}
+		if s.course != noCourse {
+			records[i].GnssCourse = &s.course
+		}
+		if s.stop != "" {
+			records[i].ItcsStopName = &s.stop
+		}
+	}
+	return records
+}
+
+func TestResamplerWindows(t *testing.T) {
+	// a window of the expected output, times are offsets from t0
+	type window struct {
+		start               int
+		count               int
+		speedMean, speedMax float64
+		doorFrac            float64
+		course              pgtype.Float4
+		stop                pgtype.Text
+	}
+	course := func(v float64) pgtype.Float4 { return pgtype.Float4{Float32: float32(v), Valid: true} }
+	stop := func(s string) pgtype.Text { return pgtype.Text{String: s, Valid: true} }
+
+	tests := []struct {
+		name    string
+		samples []windowSample
+		want    []window
+	}{
+		{
+			// t0 is a multiple of 10, so windows start at offsets 0, 10, ...
+			name: "epoch aligned boundaries",
+			samples: []windowSample{
+				{at: -1, speed: 2, course: noCourse},
+				{at: 0, speed: 4, course: noCourse},
+				{at: 9, speed: 6, door: true, course: noCourse},
+				{at: 10, speed: 8, course: noCourse},
+			},
+			want: []window{
+				{start: -10, count: 1, speedMean: 2, speedMax: 2},
+				{start: 0, count: 2, speedMean: 5, speedMax: 6, doorFrac: 0.5},
+				{start: 10, count: 1, speedMean: 8, speedMax: 8},
+			},
+		},
+		{
+			name: "gap leaves no empty window",
+			samples: []windowSample{
+				{at: 1, speed: 1, course: noCourse},
+				{at: 35, speed: 3, course: noCourse},
+			},
+			want: []window{
+				{start: 0, count: 1, speedMean: 1, speedMax: 1},
+				{start: 30, count: 1, speedMean: 3, speedMax: 3},
+			},
+		},
+		{
+			name: "latest course and stop",
+			samples: []windowSample{
+				{at: 1, speed: 1, course: 0.5, stop: "A"},
+				{at: 2, speed: 1, course: 1.5},
+				{at: 3, speed: 1, course: noCourse},
+			},
+			want: []window{
+				{start: 0, count: 3, speedMean: 1, speedMax: 1, course: course(1.5), stop: stop("A")},
+			},
+		},
+		{
+			name: "unsorted input",
+			samples: []windowSample{
+				{at: 12, speed: 6, course: noCourse, stop: "B"},
+				{at: 3, speed: 2, course: 1.5, stop: "A"},
+				{at: 11, speed: 4, course: 2.5},
+				{at: 1, speed: 4, door: true, course: 0.5, stop: "C"},
+				{at: 10, speed: 2, course: noCourse, stop: "D"},
+			},
+			want: []window{
+				{start: 0, count: 2, speedMean: 3, speedMax: 4, doorFrac: 0.5, course: course(1.5), stop: stop("A")},
+				{start: 10, count: 3, speedMean: 4, speedMax: 6, course: course(2.5), stop: stop("B")},
+			},
+		},
+	}
+	r := Resampler{Resolution: 10 * time.Second, Mode: ResampleModeRollup}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := r.Windows(7, int4(3), windowRecords(tt.samples...))
+			if len(rows) != len(tt.want) {
+				t.Fatalf("Windows() returned %d windows, want %d", len(rows), len(tt.want))
+			}
+			for i, p := range rows {
+				want := tt.want[i]
+				if p.TripID != 7 || p.ItcsBusRouteID != int4(3) || p.ResolutionS != 10 {
+					t.Errorf("window %d is for trip %d, route %+v at %ds", i, p.TripID, p.ItcsBusRouteID, p.ResolutionS)
+				}
+				got := window{
+					start:     int(p.Time.Time.Unix() - t0),
+					count:     int(p.SampleCount),
+					speedMean: float64(p.OdometryVehicleSpeedMean.Float32),
+					speedMax:  float64(p.OdometryVehicleSpeedMax.Float32),
+					doorFrac:  float64(p.StatusDoorIsOpenFrac.Float32),
+					course:    p.GnssCourse,
+					stop:      p.ItcsStopName,
+				}
+				if got != want {
+					t.Errorf("window %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWindowStat(t *testing.T) {
+	var s windowStat
+	for _, v := range []float64{3, math.NaN(), -1, 4} {
+		s.add(v)
+	}
+	mean, lo, hi := s.agg()
+	if mean.Float32 != 2 || lo.Float32 != -1 || hi.Float32 != 4 {
+		t.Errorf("agg() = %v, %v, %v, want 2, -1, 4", mean.Float32, lo.Float32, hi.Float32)
+	}
+
+	mean, lo, hi = (windowStat{}).agg()
+	if mean.Valid || lo.Valid || hi.Valid {
+		t.Error("agg() of an empty window is not NULL")
+	}
+}
+
+func TestNewResampler(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		want    time.Duration
+		wantErr bool
+	}{
+		{"disabled", Options{}, 0, false},
+		{"minute", Options{Resample: "1m", ResampleMode: ResampleModeRollup}, time.Minute, false},
+		{"shortest", Options{Resample: "2s", ResampleMode: ResampleModeReplace}, 2 * time.Second, false},
+		{"too short", Options{Resample: "1s", ResampleMode: ResampleModeRollup}, 0, true},
+		{"fractional", Options{Resample: "2500ms", ResampleMode: ResampleModeRollup}, 0, true},
+		{"unknown mode", Options{Resample: "1m", ResampleMode: "merge"}, 0, true},
+		{"malformed", Options{Resample: "a minute", ResampleMode: ResampleModeRollup}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewResampler(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewResampler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if r.Resolution != tt.want {
+				t.Errorf("NewResampler() resolution = %s, want %s", r.Resolution, tt.want)
+			}
+		})
+	}
+}
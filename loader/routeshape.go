@@ -0,0 +1,433 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	earthRadius = 6371000.0 // m
+
+	// fixes further than this from the shape are treated as unusable
+	maxRouteOffset = 50.0 // m
+	// snapping searches this many segments either side of the previous match
+	// before falling back to the whole shape
+	snapWindow = 50
+	// fixes implying a faster jump than this are dropped while building shapes
+	maxFixSpeed = 40.0 // m/s
+)
+
+// planePoint is a position in metres on a local equirectangular projection
+type planePoint struct {
+	x, y float64 // east, north
+}
+
+// projection maps radians to a plane around an origin, accurate to well below
+// the GNSS noise over the extent of a city
+type projection struct {
+	lat0, lon0, cosLat0 float64
+}
+
+func newProjection(lat0, lon0 float64) projection {
+	return projection{lat0: lat0, lon0: lon0, cosLat0: math.Cos(lat0)}
+}
+
+func (p projection) toPlane(lat, lon float64) planePoint {
+	return planePoint{
+		x: earthRadius * (lon - p.lon0) * p.cosLat0,
+		y: earthRadius * (lat - p.lat0),
+	}
+}
+
+func (p projection) fromPlane(pt planePoint) (float64, float64) {
+	return p.lat0 + pt.y/earthRadius, p.lon0 + pt.x/(earthRadius*p.cosLat0)
+}
+
+func planeDistance(a, b planePoint) float64 {
+	return math.Hypot(b.x-a.x, b.y-a.y)
+}
+
+// routeShape is a route polyline on the plane
+type routeShape struct {
+	proj     projection
+	points   []planePoint
+	distance []float64 // along the shape at each point
+}
+
+func newRouteShape(proj projection, points []planePoint) routeShape {
+	s := routeShape{proj: proj, points: points, distance: make([]float64, len(points))}
+	for i := 1; i < len(points); i++ {
+		s.distance[i] = s.distance[i-1] + planeDistance(points[i-1], points[i])
+	}
+	return s
+}
+
+func (s routeShape) length() float64 {
+	return s.distance[len(s.distance)-1]
+}
+
+// snap projects a point onto the segments [from, to) of the shape and returns
+// the distance along the shape, the offset from it and the segment
+func (s routeShape) snap(p planePoint, from, to int) (float64, float64, int) {
+	from = max(from, 0)
+	to = min(to, len(s.points)-1)
+	along, offset, segment := 0.0, math.Inf(1), -1
+	for i := from; i < to; i++ {
+		a, b := s.points[i], s.points[i+1]
+		dx, dy := b.x-a.x, b.y-a.y
+		t := 0.0
+		if l2 := dx*dx + dy*dy; l2 > 0 {
+			t = math.Max(0, math.Min(1, ((p.x-a.x)*dx+(p.y-a.y)*dy)/l2))
+		}
+		q := planePoint{x: a.x + t*dx, y: a.y + t*dy}
+		if d := planeDistance(p, q); d < offset {
+			along, offset, segment = s.distance[i]+t*(s.distance[i+1]-s.distance[i]), d, i
+		}
+	}
+	return along, offset, segment
+}
+
+// bearing returns the heading of a segment, clockwise from north like the GNSS course
+func (s routeShape) bearing(segment int) float64 {
+	a, b := s.points[segment], s.points[segment+1]
+	return math.Atan2(b.x-a.x, b.y-a.y)
+}
+
+// segmentAt returns the segment containing a distance along the shape
+func (s routeShape) segmentAt(along float64) int {
+	i, _ := slices.BinarySearch(s.distance, along)
+	return max(0, min(i-1, len(s.points)-2))
+}
+
+// routeShapeFromRow converts a stored shape
+func routeShapeFromRow(row postgres.RouteShape) routeShape {
+	proj := newProjection(row.Latitudes[0], row.Longitudes[0])
+	points := make([]planePoint, len(row.Latitudes))
+	for i := range points {
+		points[i] = proj.toPlane(row.Latitudes[i], row.Longitudes[i])
+	}
+	return routeShape{proj: proj, points: points, distance: row.DistancesM}
+}
+
+// trackFixes returns the usable GNSS fixes of a track on the plane, dropping
+// fixes that jump further than the bus could have driven
+func trackFixes(proj projection, track []postgres.GetTripTrackRow) []planePoint {
+	var fixes []planePoint
+	var last time.Time
+	for _, t := range track {
+		if !t.GnssLatitude.Valid || !t.GnssLongitude.Valid {
+			continue
+		}
+		p := proj.toPlane(t.GnssLatitude.Float64, t.GnssLongitude.Float64)
+		if n := len(fixes); n > 0 {
+			dt := t.Time.Time.Sub(last).Seconds()
+			if planeDistance(fixes[n-1], p) > maxFixSpeed*dt+maxRouteOffset {
+				continue
+			}
+		}
+		fixes = append(fixes, p)
+		last = t.Time.Time
+	}
+	return fixes
+}
+
+// buildRouteShape derives a route's shape from the tracks of several trips.
+// The trip with the most fixes gives the initial polyline, resampled every
+// spacing metres. The fixes of all trips near it are then averaged per vertex.
+func buildRouteShape(tracks [][]postgres.GetTripTrackRow, spacing float64) (routeShape, bool) {
+	reference := slices.MaxFunc(tracks, func(a, b []postgres.GetTripTrackRow) int {
+		return len(a) - len(b)
+	})
+	var origin postgres.GetTripTrackRow
+	for _, t := range reference {
+		if t.GnssLatitude.Valid && t.GnssLongitude.Valid {
+			origin = t
+			break
+		}
+	}
+	if !origin.GnssLatitude.Valid {
+		return routeShape{}, false
+	}
+	proj := newProjection(origin.GnssLatitude.Float64, origin.GnssLongitude.Float64)
+
+	var points []planePoint
+	for _, p := range trackFixes(proj, reference) {
+		if len(points) == 0 || planeDistance(points[len(points)-1], p) >= spacing {
+			points = append(points, p)
+		}
+	}
+	if len(points) < 2 {
+		return routeShape{}, false
+	}
+	initial := newRouteShape(proj, points)
+
+	sums := make([]planePoint, len(points))
+	counts := make([]int, len(points))
+	for _, track := range tracks {
+		segment := -1
+		for _, p := range trackFixes(proj, track) {
+			along, offset, s := snapNear(initial, p, segment)
+			if offset > maxRouteOffset {
+				continue
+			}
+			segment = s
+			v := s
+			if along-initial.distance[s] > initial.distance[s+1]-along {
+				v = s + 1
+			}
+			sums[v].x += p.x
+			sums[v].y += p.y
+			counts[v]++
+		}
+	}
+	for i, n := range counts {
+		if n > 0 {
+			points[i] = planePoint{x: sums[i].x / float64(n), y: sums[i].y / float64(n)}
+		}
+	}
+	return newRouteShape(proj, points), true
+}
+
+// snapNear snaps a point close to the previous match, searching the whole
+// shape for the first point and whenever the bus appears to leave the window
+func snapNear(shape routeShape, p planePoint, previous int) (float64, float64, int) {
+	if previous >= 0 {
+		along, offset, segment := shape.snap(p, previous-snapWindow, previous+snapWindow)
+		if offset <= maxRouteOffset {
+			return along, offset, segment
+		}
+	}
+	return shape.snap(p, 0, len(shape.points)-1)
+}
+
+// SnapTrack computes the route position of every sample of a track. Samples
+// without a usable fix are dead reckoned from the speed, moving along the
+// shape in the direction of the GNSS course or, without one, the direction of
+// the last movement. Samples before the first fix are reckoned backwards.
+func SnapTrack(
+	tripID int32,
+	shape routeShape,
+	track []postgres.GetTripTrackRow,
+) []postgres.InsertRoutePositionsParams {
+	positions := make([]postgres.InsertRoutePositionsParams, len(track))
+	known := make([]bool, len(track))
+	along := make([]float64, len(track))
+	for i, t := range track {
+		positions[i] = postgres.InsertRoutePositionsParams{TripID: tripID, Time: t.Time}
+	}
+
+	speed := func(i int) float64 {
+		v := track[i].OdometryVehicleSpeed
+		if !v.Valid || math.IsNaN(float64(v.Float32)) {
+			return 0
+		}
+		return float64(v.Float32)
+	}
+	dt := func(i, j int) float64 {
+		return math.Abs(track[j].Time.Time.Sub(track[i].Time.Time).Seconds())
+	}
+	clamp := func(s float64) float64 {
+		return math.Max(0, math.Min(shape.length(), s))
+	}
+
+	segment, direction, first := -1, 1.0, -1
+	for i, t := range track {
+		if t.GnssLatitude.Valid && t.GnssLongitude.Valid {
+			p := shape.proj.toPlane(t.GnssLatitude.Float64, t.GnssLongitude.Float64)
+			s, offset, seg := snapNear(shape, p, segment)
+			if offset <= maxRouteOffset {
+				if segment >= 0 && i > 0 && known[i-1] && math.Abs(s-along[i-1]) > 0.5 {
+					direction = math.Copysign(1, s-along[i-1])
+				}
+				along[i], known[i], segment = s, true, seg
+				positions[i].RouteOffsetM = pgtype.Float4{Float32: float32(offset), Valid: true}
+				positions[i].RoutePositionEstimated = pgtype.Bool{Bool: false, Valid: true}
+				if first < 0 {
+					first = i
+				}
+				continue
+			}
+		}
+		if i == 0 || !known[i-1] {
+			continue
+		}
+		if c := t.GnssCourse; c.Valid && !math.IsNaN(float64(c.Float32)) {
+			heading := shape.bearing(shape.segmentAt(along[i-1]))
+			direction = math.Copysign(1, math.Cos(float64(c.Float32)-heading))
+		}
+		along[i], known[i] = clamp(along[i-1]+direction*speed(i-1)*dt(i-1, i)), true
+		positions[i].RoutePositionEstimated = pgtype.Bool{Bool: true, Valid: true}
+	}
+	if first > 0 {
+		direction = 1
+		if first+1 < len(track) && known[first+1] && along[first+1] < along[first] {
+			direction = -1
+		}
+		for i := first - 1; i >= 0; i-- {
+			along[i], known[i] = clamp(along[i+1]-direction*speed(i)*dt(i, i+1)), true
+			positions[i].RoutePositionEstimated = pgtype.Bool{Bool: true, Valid: true}
+		}
+	}
+
+	for i := range positions {
+		if known[i] {
+			positions[i].RouteDistanceM = pgtype.Float4{Float32: float32(along[i]), Valid: true}
+		}
+	}
+	return positions
+}
+
+// snapTrip stores the route positions of a trip. It returns false if the
+// trip's route has no shape yet.
+func snapTrip(ctx context.Context, pool *pgxpool.Pool, tripID int32) (bool, error) {
+	q := postgres.New(pool)
+	routeID, err := q.GetBusRouteIdFromTripId(ctx, tripID)
+	if err != nil {
+		return false, fmt.Errorf("could not get route of trip %d: %v", tripID, err)
+	}
+	if !routeID.Valid {
+		return false, nil
+	}
+	row, err := q.GetRouteShape(ctx, routeID.Int32)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("could not get shape of route %d: %v", routeID.Int32, err)
+	}
+	return true, snapTripToShape(ctx, pool, tripID, routeShapeFromRow(row))
+}
+
+func snapTripToShape(ctx context.Context, pool *pgxpool.Pool, tripID int32, shape routeShape) error {
+	track, err := postgres.New(pool).GetTripTrack(ctx, tripID)
+	if err != nil {
+		return fmt.Errorf("could not read track of trip %d: %v", tripID, err)
+	}
+	if len(track) == 0 {
+		return nil
+	}
+	positions := SnapTrack(tripID, shape, track)
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := postgres.New(tx)
+
+	if err := qtx.DeleteRoutePositionsByTrip(ctx, tripID); err != nil {
+		return fmt.Errorf("could not clear route positions: %v", err)
+	}
+	if _, err := qtx.InsertRoutePositions(ctx, positions); err != nil {
+		return fmt.Errorf("error during COPY FROM: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit transaction: %v", err)
+	}
+	return nil
+}
+
+// SnapTrips stores the route positions of the given trips on routes with a
+// shape and returns how many were snapped
+func SnapTrips(ctx context.Context, pool *pgxpool.Pool, tripIDs []int32) (int, error) {
+	snapped := 0
+	for _, tripID := range tripIDs {
+		ok, err := snapTrip(ctx, pool, tripID)
+		if err != nil {
+			return snapped, err
+		}
+		if ok {
+			snapped++
+		}
+	}
+	slog.Info("snapped trips to route shapes", "trips", len(tripIDs), "snapped", snapped)
+	return snapped, nil
+}
+
+// evenlySpaced picks at most n trips spread over the whole list
+func evenlySpaced(trips []postgres.Trip, n int) []postgres.Trip {
+	if len(trips) <= n {
+		return trips
+	}
+	picked := make([]postgres.Trip, n)
+	for i := range picked {
+		picked[i] = trips[i*len(trips)/n]
+	}
+	return picked
+}
+
+// RouteShapeResult describes a shape stored by BuildRouteShape
+type RouteShapeResult struct {
+	LengthM float64
+	Tracks  int // trips the shape was built from
+	Snapped int // trips snapped onto the shape
+}
+
+// BuildRouteShape builds the shape of a route from the tracks of up to
+// maxTrips of its trips, with vertices spacing metres apart, stores it and
+// snaps all trips of the route onto it. It returns false if the route has no
+// usable GNSS fixes.
+func BuildRouteShape(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	route postgres.BusRoute,
+	maxTrips int,
+	spacing float64,
+) (RouteShapeResult, bool, error) {
+	q := postgres.New(pool)
+	trips, err := q.GetTripsByRoute(ctx, pgtype.Int4{Int32: route.ID, Valid: true})
+	if err != nil {
+		return RouteShapeResult{}, false, fmt.Errorf("could not list trips of route %s: %v", route.RouteCode.String, err)
+	}
+	var tracks [][]postgres.GetTripTrackRow
+	for _, trip := range evenlySpaced(trips, maxTrips) {
+		track, err := q.GetTripTrack(ctx, trip.ID)
+		if err != nil {
+			return RouteShapeResult{}, false, fmt.Errorf("could not read track of trip %s: %v", trip.Name, err)
+		}
+		if len(track) > 0 {
+			tracks = append(tracks, track)
+		}
+	}
+	if len(tracks) == 0 {
+		slog.Info("no telemetry for route, skipping", "route", route.RouteCode.String)
+		return RouteShapeResult{}, false, nil
+	}
+
+	shape, ok := buildRouteShape(tracks, spacing)
+	if !ok {
+		slog.Info("no usable GNSS fixes for route, skipping", "route", route.RouteCode.String)
+		return RouteShapeResult{}, false, nil
+	}
+	p := postgres.UpsertRouteShapeParams{
+		RouteID:    route.ID,
+		DistancesM: shape.distance,
+		LengthM:    shape.length(),
+		Trips:      int32(len(tracks)),
+	}
+	for _, pt := range shape.points {
+		lat, lon := shape.proj.fromPlane(pt)
+		p.Latitudes = append(p.Latitudes, lat)
+		p.Longitudes = append(p.Longitudes, lon)
+	}
+	if err := q.UpsertRouteShape(ctx, p); err != nil {
+		return RouteShapeResult{}, false, fmt.Errorf("could not store shape of route %s: %v", route.RouteCode.String, err)
+	}
+
+	for _, trip := range trips {
+		if err := snapTripToShape(ctx, pool, trip.ID, shape); err != nil {
+			return RouteShapeResult{}, false, err
+		}
+	}
+	return RouteShapeResult{LengthM: shape.length(), Tracks: len(tracks), Snapped: len(trips)}, true, nil
+}
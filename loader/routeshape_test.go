@@ -0,0 +1,249 @@
+package loader
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// testProjection is centred on Zurich
+var testProjection = newProjection(47.38*math.Pi/180, 8.54*math.Pi/180)
+
+// GNSS courses of the test shapes, which run east
+const (
+	east     = math.Pi / 2
+	west     = -math.Pi / 2
+	noCourse = -1000.0
+)
+
+// trackSample is one second of a track, with a fix at (x, y) or without one
+type trackSample struct {
+	fix    bool
+	x, y   float64
+	course float64
+	speed  float64
+}
+
+func fixAt(x, y, speed float64) trackSample {
+	return trackSample{fix: true, x: x, y: y, course: noCourse, speed: speed}
+}
+
+func gap(course, speed float64) trackSample {
+	return trackSample{course: course, speed: speed}
+}
+
+// trackRows expands samples into a track one second apart
+func trackRows(samples ...trackSample) []postgres.GetTripTrackRow {
+	rows := make([]postgres.GetTripTrackRow, len(samples))
+	for i, s := range samples {
+		rows[i] = postgres.GetTripTrackRow{
+			Time:                 pgtype.Timestamptz{Time: time.Unix(int64(t0+i), 0), Valid: true},
+			OdometryVehicleSpeed: pgtype.Float4{Float32: float32(s.speed), Valid: true},
+		}
+		if s.fix {
+			lat, lon := testProjection.fromPlane(planePoint{x: s.x, y: s.y})
+			rows[i].GnssLatitude = pgtype.Float8{Float64: lat, Valid: true}
+			rows[i].GnssLongitude = pgtype.Float8{Float64: lon, Valid: true}
+		}
+		if s.course != noCourse {
+			rows[i].GnssCourse = pgtype.Float4{Float32: float32(s.course), Valid: true}
+		}
+	}
+	return rows
+}
+
+// straightShape runs east from the origin in segments of step metres
+func straightShape(length, step float64) routeShape {
+	var points []planePoint
+	for x := 0.0; x <= length; x += step {
+		points = append(points, planePoint{x: x})
+	}
+	return newRouteShape(testProjection, points)
+}
+
+func TestRouteShapeSnap(t *testing.T) {
+	// east for 100 m, then north for 100 m
+	shape := newRouteShape(testProjection, []planePoint{{0, 0}, {100, 0}, {100, 100}})
+
+	tests := []struct {
+		name          string
+		p             planePoint
+		along, offset float64
+		segment       int
+	}{
+		{"on the first segment", planePoint{50, 0}, 50, 0, 0},
+		{"beside the first segment", planePoint{50, 10}, 50, 10, 0},
+		{"beside the second segment", planePoint{110, 50}, 150, 10, 1},
+		{"before the start", planePoint{-20, 0}, 0, 20, 0},
+		{"past the end", planePoint{100, 130}, 200, 30, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			along, offset, segment := shape.snap(tt.p, 0, len(shape.points)-1)
+			if math.Abs(along-tt.along) > 1e-9 || math.Abs(offset-tt.offset) > 1e-9 ||
+				segment != tt.segment {
+				t.Errorf(
+					"snap(%v) = %g, %g, %d, want %g, %g, %d",
+					tt.p, along, offset, segment, tt.along, tt.offset, tt.segment,
+				)
+			}
+		})
+	}
+}
+
+func TestSnapNear(t *testing.T) {
+	shape := straightShape(2000, 10)
+
+	tests := []struct {
+		name     string
+		p        planePoint
+		previous int
+		along    float64
+	}{
+		{"first fix", planePoint{1234, 5}, -1, 1234},
+		{"within the window", planePoint{520, 5}, 50, 520},
+		// the window around segment 0 ends at 500 m
+		{"outside the window", planePoint{1500, 5}, 0, 1500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			along, offset, _ := snapNear(shape, tt.p, tt.previous)
+			if math.Abs(along-tt.along) > 1e-9 || math.Abs(offset-5) > 1e-9 {
+				t.Errorf("snapNear(%v) = %g, %g, want %g, 5", tt.p, along, offset, tt.along)
+			}
+		})
+	}
+}
+
+func TestBuildRouteShape(t *testing.T) {
+	// two passes 3 m either side of a straight road
+	var north, south []trackSample
+	for x := 0.0; x <= 300; x += 10 {
+		north = append(north, fixAt(x, 3, 10))
+		south = append(south, fixAt(x, -3, 10))
+	}
+
+	// vertices every 50 m, the spacing stays clear of the rounding of the
+	// projection around the first fix
+	shape, ok := buildRouteShape([][]postgres.GetTripTrackRow{
+		trackRows(north...),
+		trackRows(south...),
+	}, 45)
+	if !ok {
+		t.Fatal("buildRouteShape() found no shape")
+	}
+
+	// each vertex averages the fixes closest to it, so the ends move inwards
+	want := []float64{10, 50, 100, 150, 200, 250, 290}
+	if len(shape.points) != len(want) {
+		t.Fatalf("shape has %d points, want %d", len(shape.points), len(want))
+	}
+	for i, p := range shape.points {
+		got := testProjection.toPlane(shape.proj.fromPlane(p))
+		if math.Abs(got.x-want[i]) > 0.01 || math.Abs(got.y) > 0.01 {
+			t.Errorf("point %d = %+v, want {x:%g y:0}", i, got, want[i])
+		}
+	}
+	if math.Abs(shape.length()-280) > 0.01 {
+		t.Errorf("shape is %g m long, want 280", shape.length())
+	}
+
+	if _, ok := buildRouteShape([][]postgres.GetTripTrackRow{
+		trackRows(gap(east, 10), gap(east, 10)),
+	}, 50); ok {
+		t.Error("buildRouteShape() built a shape without fixes")
+	}
+}
+
+func TestSnapTrack(t *testing.T) {
+	shape := straightShape(1000, 100)
+
+	type position struct {
+		along     float64
+		estimated bool
+	}
+	tests := []struct {
+		name  string
+		track []trackSample
+		want  []position
+	}{
+		{
+			name: "gap filled from speed",
+			track: []trackSample{
+				fixAt(100, 2, 10),
+				gap(noCourse, 10),
+				gap(east, 12),
+				fixAt(145, -2, 10),
+			},
+			want: []position{{100, false}, {110, true}, {120, true}, {145, false}},
+		},
+		{
+			name: "reckoned backwards before the first fix",
+			track: []trackSample{
+				gap(noCourse, 10),
+				gap(noCourse, 5),
+				fixAt(200, 0, 10),
+				fixAt(210, 0, 10),
+			},
+			want: []position{{185, true}, {195, true}, {200, false}, {210, false}},
+		},
+		{
+			name: "reversing along the shape",
+			track: []trackSample{
+				gap(noCourse, 10),
+				fixAt(300, 0, 10),
+				fixAt(290, 0, 10),
+				gap(noCourse, 10),
+				gap(noCourse, 10),
+			},
+			want: []position{{310, true}, {300, false}, {290, false}, {280, true}, {270, true}},
+		},
+		{
+			name: "course against the shape",
+			track: []trackSample{
+				fixAt(500, 0, 10),
+				gap(west, 10),
+				gap(west, 10),
+			},
+			want: []position{{500, false}, {490, true}, {480, true}},
+		},
+		{
+			name: "clamped to the shape",
+			track: []trackSample{
+				fixAt(5, 0, 10),
+				gap(west, 10),
+			},
+			want: []position{{5, false}, {0, true}},
+		},
+		{
+			name: "fix off the route",
+			track: []trackSample{
+				fixAt(100, 0, 10),
+				fixAt(110, 200, 10),
+			},
+			want: []position{{100, false}, {110, true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			positions := SnapTrack(7, shape, trackRows(tt.track...))
+			if len(positions) != len(tt.want) {
+				t.Fatalf("SnapTrack() returned %d positions, want %d", len(positions), len(tt.want))
+			}
+			for i, p := range positions {
+				if !p.RouteDistanceM.Valid || !p.RoutePositionEstimated.Valid {
+					t.Errorf("position %d is unknown", i)
+					continue
+				}
+				got := position{float64(p.RouteDistanceM.Float32), p.RoutePositionEstimated.Bool}
+				if math.Abs(got.along-tt.want[i].along) > 0.01 ||
+					got.estimated != tt.want[i].estimated {
+					t.Errorf("position %d = %+v, want %+v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package loader
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"sort"
+
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+)
+
+// TripSample draws a reproducible random subset of trips. The same fraction,
+// seed and input trips always yield the same sample, independent of the order
+// of rows in metaData.csv.
+type TripSample struct {
+	Name       string
+	Fraction   float64
+	Seed       int64
+	Stratified bool
+}
+
+// NewTripSample builds a TripSample from the load options
+func NewTripSample(opts Options) (TripSample, error) {
+	if opts.Sample == 0 {
+		return TripSample{}, nil
+	}
+	// NaN compares false against both bounds
+	if math.IsNaN(opts.Sample) || opts.Sample < 0 || opts.Sample > 1 {
+		return TripSample{}, fmt.Errorf("option Sample must be in (0, 1], got %g", opts.Sample)
+	}
+
+	name := opts.SampleName
+	if name == "" {
+		name = fmt.Sprintf("sample-%g-seed-%d", opts.Sample, opts.Seed)
+		if opts.Stratify {
+			name += "-stratified"
+		}
+	}
+
+	return TripSample{
+		Name:       name,
+		Fraction:   opts.Sample,
+		Seed:       opts.Seed,
+		Stratified: opts.Stratify,
+	}, nil
+}
+
+// Enabled reports whether sampling was requested
+func (s TripSample) Enabled() bool {
+	return s.Fraction > 0
+}
+
+// String renders the sample settings for logs and the summary
+func (s TripSample) String() string {
+	desc := fmt.Sprintf("%s (%.2f%%, seed %d", s.Name, s.Fraction*100, s.Seed)
+	if s.Stratified {
+		desc += ", stratified by bus/route"
+	}
+	return desc + ")"
+}
+
+// stratum returns the grouping key of a trip
+func (s TripSample) stratum(m ztbus.Metadata) string {
+	if !s.Stratified {
+		return ""
+	}
+	return m.BusNumber + "/" + m.BusRoute
+}
+
+// rng returns a generator for a single stratum. Seeding per stratum keeps the
+// selection within one bus/route stable when other strata are filtered out.
+func (s TripSample) rng(stratum string) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(stratum))
+	return rand.New(rand.NewPCG(uint64(s.Seed), h.Sum64()))
+}
+
+// Draw returns the sampled trips, preserving the input order. When stratified,
+// every bus/route combination contributes at least one trip.
+func (s TripSample) Draw(metadata []ztbus.Metadata) []ztbus.Metadata {
+	if !s.Enabled() {
+		return metadata
+	}
+
+	strata := make(map[string][]string)
+	for _, m := range metadata {
+		key := s.stratum(m)
+		strata[key] = append(strata[key], m.Name)
+	}
+
+	selected := make(map[string]bool)
+	for key, names := range strata {
+		// sort so the draw does not depend on csv row order
+		sort.Strings(names)
+		n := int(math.Round(s.Fraction * float64(len(names))))
+		n = max(n, 1)
+
+		for _, idx := range s.rng(key).Perm(len(names))[:n] {
+			selected[names[idx]] = true
+		}
+	}
+
+	return keep(metadata, func(m ztbus.Metadata) bool { return selected[m.Name] })
+}
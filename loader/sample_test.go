@@ -0,0 +1,143 @@
+package loader
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+)
+
+// sampleTrips returns n trips for each of buses 183 and 208 on routes 31 and
+// 33, with a single extra trip forming its own bus/route stratum
+func sampleTrips(n int) []ztbus.Metadata {
+	var trips []ztbus.Metadata
+	for _, bus := range []string{"183", "208"} {
+		for _, route := range []string{"31", "33"} {
+			for i := range n {
+				trips = append(trips, ztbus.Metadata{
+					Name:      fmt.Sprintf("B%s_R%s_%02d", bus, route, i),
+					BusNumber: bus,
+					BusRoute:  route,
+				})
+			}
+		}
+	}
+	return append(trips, ztbus.Metadata{Name: "B209_R32_00", BusNumber: "209", BusRoute: "32"})
+}
+
+func TestTripSampleDraw(t *testing.T) {
+	trips := sampleTrips(20)
+
+	t.Run("same seed, same draw", func(t *testing.T) {
+		s := TripSample{Fraction: 0.25, Seed: 42}
+		first := tripNames(s.Draw(trips))
+		if len(first) != 20 {
+			t.Errorf("drew %d trips, want 20", len(first))
+		}
+		if again := tripNames(s.Draw(trips)); !reflect.DeepEqual(again, first) {
+			t.Errorf("second draw = %v, want %v", again, first)
+		}
+		other := tripNames(TripSample{Fraction: 0.25, Seed: 43}.Draw(trips))
+		if reflect.DeepEqual(other, first) {
+			t.Error("a different seed drew the same trips")
+		}
+	})
+
+	t.Run("independent of row order", func(t *testing.T) {
+		s := TripSample{Fraction: 0.25, Seed: 42}
+		reversed := make([]ztbus.Metadata, len(trips))
+		for i, m := range trips {
+			reversed[len(trips)-1-i] = m
+		}
+		want := map[string]bool{}
+		for _, name := range tripNames(s.Draw(trips)) {
+			want[name] = true
+		}
+		got := tripNames(s.Draw(reversed))
+		if len(got) != len(want) {
+			t.Fatalf("drew %d trips from reversed rows, want %d", len(got), len(want))
+		}
+		for _, name := range got {
+			if !want[name] {
+				t.Errorf("reversed rows drew %s", name)
+			}
+		}
+	})
+
+	t.Run("stratified covers every stratum", func(t *testing.T) {
+		s := TripSample{Fraction: 0.1, Seed: 7, Stratified: true}
+		drawn := s.Draw(trips)
+
+		counts := map[string]int{}
+		for _, m := range drawn {
+			counts[m.BusNumber+"/"+m.BusRoute]++
+		}
+		// 10% of 20 in the four large strata, at least one in the small one
+		want := map[string]int{"183/31": 2, "183/33": 2, "208/31": 2, "208/33": 2, "209/32": 1}
+		if !reflect.DeepEqual(counts, want) {
+			t.Errorf("trips per stratum = %v, want %v", counts, want)
+		}
+	})
+
+	t.Run("stratum draw is stable", func(t *testing.T) {
+		// dropping other strata leaves the draw within a stratum unchanged
+		s := TripSample{Fraction: 0.25, Seed: 7, Stratified: true}
+		all := map[string]bool{}
+		for _, m := range s.Draw(trips) {
+			if m.BusNumber == "183" && m.BusRoute == "31" {
+				all[m.Name] = true
+			}
+		}
+		only := tripNames(s.Draw(trips[:20]))
+		if len(only) != len(all) {
+			t.Fatalf("drew %d trips from one stratum, want %d", len(only), len(all))
+		}
+		for _, name := range only {
+			if !all[name] {
+				t.Errorf("single stratum drew %s", name)
+			}
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		if got := (TripSample{}).Draw(trips); len(got) != len(trips) {
+			t.Errorf("disabled sample drew %d trips, want %d", len(got), len(trips))
+		}
+	})
+}
+
+func TestNewTripSample(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		want    string
+		wantErr bool
+	}{
+		{"disabled", Options{}, "", false},
+		{"named", Options{Sample: 0.1, Seed: 3, SampleName: "dev"}, "dev", false},
+		{"default name", Options{Sample: 0.1, Seed: 3}, "sample-0.1-seed-3", false},
+		{
+			"stratified name",
+			Options{Sample: 0.5, Seed: 1, Stratify: true},
+			"sample-0.5-seed-1-stratified",
+			false,
+		},
+		{"whole set", Options{Sample: 1}, "sample-1-seed-0", false},
+		{"negative", Options{Sample: -0.1}, "", true},
+		{"above one", Options{Sample: 1.5}, "", true},
+		{"NaN", Options{Sample: math.NaN()}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewTripSample(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewTripSample() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if s.Name != tt.want {
+				t.Errorf("NewTripSample() name = %q, want %q", s.Name, tt.want)
+			}
+		})
+	}
+}
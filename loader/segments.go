@@ -0,0 +1,152 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// trip phases, as stored in trip_segments
+const (
+	phaseDrivingGrid    = "driving_grid"    // moving with the trolley poles on the grid
+	phaseDrivingBattery = "driving_battery" // moving on the battery alone
+	phaseDwelling       = "dwelling"        // at a stop, from arrival to departure
+	phaseCharging       = "charging"        // standing elsewhere, connected to the grid
+	phaseIdle           = "idle"            // standing elsewhere off the grid, e.g. parked
+)
+
+// minSegmentSamples is the shortest segment kept on its own. Shorter runs join
+// the segment before them, so that a speed hovering around the standstill
+// threshold does not split a trip into one second segments.
+const minSegmentSamples = 5
+
+// tripPhase classifies one sample
+func tripPhase(t ztbus.TripTelemetry, atStop bool) string {
+	switch {
+	case atStop:
+		return phaseDwelling
+	case standing(t) && t.StatusGridIsAvailable:
+		return phaseCharging
+	case standing(t):
+		return phaseIdle
+	case t.StatusGridIsAvailable:
+		return phaseDrivingGrid
+	default:
+		return phaseDrivingBattery
+	}
+}
+
+// SegmentTrip splits the time ordered records of a trip into phases. Samples
+// between the arrival and departure of a stop event are dwelling. The step
+// from one sample to the next counts towards the segment of the first.
+func SegmentTrip(
+	tripID int32,
+	events []postgres.InsertStopEventsParams,
+	records []ztbus.TripTelemetry,
+) []postgres.InsertTripSegmentsParams {
+	if len(records) == 0 {
+		return nil
+	}
+
+	type run struct {
+		phase      string
+		start, end int // record indices, end exclusive
+	}
+	var runs []run
+	e := 0
+	departed := func(ts time.Time) bool {
+		d := events[e].DepartureTime
+		return d.Valid && !ts.Before(d.Time)
+	}
+	for i, t := range records {
+		ts := time.Unix(int64(t.TimeUnix), 0)
+		for e < len(events) && departed(ts) {
+			e++
+		}
+		atStop := e < len(events) && !ts.Before(events[e].ArrivalTime.Time)
+		phase := tripPhase(t, atStop)
+
+		n := len(runs)
+		if n > 0 && runs[n-1].phase == phase {
+			runs[n-1].end = i + 1
+			continue
+		}
+		if n > 1 && runs[n-1].end-runs[n-1].start < minSegmentSamples {
+			// fold the short run into the one before it
+			runs[n-2].end = runs[n-1].end
+			runs = runs[:n-1]
+			if runs[n-2].phase == phase {
+				runs[n-2].end = i + 1
+				continue
+			}
+		}
+		runs = append(runs, run{phase: phase, start: i, end: i + 1})
+	}
+	if n := len(runs); n > 1 && runs[n-1].end-runs[n-1].start < minSegmentSamples {
+		runs[n-2].end = runs[n-1].end
+		runs = runs[:n-1]
+	}
+
+	at := func(i int) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: time.Unix(int64(records[i].TimeUnix), 0), Valid: true}
+	}
+	segments := make([]postgres.InsertTripSegmentsParams, len(runs))
+	for s, r := range runs {
+		last := min(r.end, len(records)-1)
+		var energy, distance float64
+		for i := r.start + 1; i <= last; i++ {
+			prev, t := records[i-1], records[i]
+			dt := float64(t.TimeUnix - prev.TimeUnix)
+			if dt <= 0 {
+				continue
+			}
+			energy += trapezoid(prev.ElectricPowerDemand, t.ElectricPowerDemand, dt)
+			distance += trapezoid(prev.OdometryVehicleSpeed, t.OdometryVehicleSpeed, dt)
+		}
+		segments[s] = postgres.InsertTripSegmentsParams{
+			TripID:     tripID,
+			Seq:        int32(s + 1),
+			Phase:      r.phase,
+			StartTime:  at(r.start),
+			EndTime:    at(last),
+			DurationS:  int32(records[last].TimeUnix - records[r.start].TimeUnix),
+			EnergyKwh:  float32(energy / 3.6e6),
+			DistanceKm: float32(distance / 1000),
+		}
+	}
+	return segments
+}
+
+// writeTripSegments replaces the segments stored for a trip
+func writeTripSegments(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	tripID int32,
+	segments []postgres.InsertTripSegmentsParams,
+) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := postgres.New(tx)
+
+	if err := qtx.DeleteTripSegmentsByTrip(ctx, tripID); err != nil {
+		return fmt.Errorf("could not clear trip segments: %v", err)
+	}
+	if len(segments) > 0 {
+		if _, err := qtx.InsertTripSegments(ctx, segments); err != nil {
+			return fmt.Errorf("error during COPY FROM: %v", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit transaction: %v", err)
+	}
+	return nil
+}
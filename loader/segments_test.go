@@ -0,0 +1,121 @@
+package loader
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+)
+
+// segmentSpan is a segment's phase with its bounds in seconds from t0
+type segmentSpan struct {
+	phase      string
+	start, end int
+}
+
+// repeat returns n copies of a sample
+func repeat(n int, s sample) []sample {
+	return slices.Repeat([]sample{s}, n)
+}
+
+func TestSegmentTrip(t *testing.T) {
+	var (
+		drivingGrid    = sample{speed: 5, grid: true, passengers: -1}
+		drivingBattery = sample{speed: 5, passengers: -1}
+		charging       = sample{speed: 0, grid: true, passengers: -1}
+		idle           = sample{speed: 0, passengers: -1}
+	)
+
+	tests := []struct {
+		name    string
+		samples []sample
+		events  []postgres.InsertStopEventsParams
+		want    []segmentSpan
+	}{
+		{
+			name: "short run folded into its predecessor",
+			samples: slices.Concat(
+				repeat(6, drivingGrid),
+				repeat(minSegmentSamples-1, idle),
+				repeat(6, drivingGrid),
+			),
+			want: []segmentSpan{{phaseDrivingGrid, 0, 15}},
+		},
+		{
+			name: "short trailing run",
+			samples: slices.Concat(
+				repeat(6, drivingGrid),
+				repeat(3, drivingBattery),
+			),
+			want: []segmentSpan{{phaseDrivingGrid, 0, 8}},
+		},
+		{
+			name: "run of minimum length kept",
+			samples: slices.Concat(
+				repeat(minSegmentSamples, charging),
+				repeat(minSegmentSamples, idle),
+				repeat(minSegmentSamples, drivingBattery),
+			),
+			want: []segmentSpan{
+				{phaseCharging, 0, 5},
+				{phaseIdle, 5, 10},
+				{phaseDrivingBattery, 10, 14},
+			},
+		},
+		{
+			name: "dwelling bounded by stop events",
+			samples: slices.Concat(
+				repeat(6, drivingGrid),
+				repeat(6, charging),
+				repeat(6, drivingGrid),
+			),
+			events: []postgres.InsertStopEventsParams{{
+				ArrivalTime:   ts(6),
+				DepartureTime: ts(12),
+			}},
+			want: []segmentSpan{
+				{phaseDrivingGrid, 0, 6},
+				{phaseDwelling, 6, 12},
+				{phaseDrivingGrid, 12, 17},
+			},
+		},
+		{
+			name: "dwelling until the trip ends",
+			samples: slices.Concat(
+				repeat(6, drivingGrid),
+				repeat(6, idle),
+			),
+			events: []postgres.InsertStopEventsParams{{ArrivalTime: ts(6)}},
+			want: []segmentSpan{
+				{phaseDrivingGrid, 0, 6},
+				{phaseDwelling, 6, 11},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments := SegmentTrip(7, tt.events, tripRecords(tt.samples...))
+			got := make([]segmentSpan, len(segments))
+			for i, s := range segments {
+				if s.Seq != int32(i+1) {
+					t.Errorf("segment %d has seq %d", i, s.Seq)
+				}
+				if s.DurationS != int32(s.EndTime.Time.Sub(s.StartTime.Time).Seconds()) {
+					t.Errorf(
+						"segment %d lasts %ds between %v and %v",
+						i, s.DurationS, s.StartTime.Time, s.EndTime.Time,
+					)
+				}
+				got[i] = segmentSpan{
+					phase: s.Phase,
+					start: int(s.StartTime.Time.Unix() - t0),
+					end:   int(s.EndTime.Time.Unix() - t0),
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SegmentTrip() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// standstillSpeed is the speed in m/s below which the bus counts as standing
+const standstillSpeed = 0.5
+
+// standing reports whether the bus stands still at a sample. The halt brake
+// also covers samples with an unavailable speed.
+func standing(t ztbus.TripTelemetry) bool {
+	return t.StatusHaltBrakeIsActive || t.OdometryVehicleSpeed < standstillSpeed
+}
+
+// runLayover is the time between leaving one stop and arriving at the next
+// after which the next stop event starts a new run
+const runLayover = 15 * time.Minute
+
+// stopVisit is a run of samples reporting the same stop, up to the next stop
+type stopVisit struct {
+	name       string
+	start, end int // record indices, end inclusive
+}
+
+// stopVisits splits a trip into visits. Samples without a stop name belong to
+// the visit before them.
+func stopVisits(records []ztbus.TripTelemetry) []stopVisit {
+	var visits []stopVisit
+	for i, t := range records {
+		if t.ItcsStopName == nil {
+			continue
+		}
+		if n := len(visits); n > 0 && visits[n-1].name == *t.ItcsStopName {
+			continue
+		}
+		if n := len(visits); n > 0 {
+			visits[n-1].end = i - 1
+		}
+		visits = append(visits, stopVisit{name: *t.ItcsStopName, start: i})
+	}
+	if n := len(visits); n > 0 {
+		visits[n-1].end = len(records) - 1
+	}
+	return visits
+}
+
+// passengers returns the first passenger count found stepping from index i
+// by step, if any
+func passengers(records []ztbus.TripTelemetry, i, step int) pgtype.Int4 {
+	for ; i >= 0 && i < len(records); i += step {
+		if p := records[i].ItcsNumberOfPassengers; p != nil {
+			return pgtype.Int4{Int32: int32(*p), Valid: true}
+		}
+	}
+	return pgtype.Int4{}
+}
+
+// ExtractStopEvents derives the stop events of a trip from its time ordered
+// records. Every visit during which the doors opened is an event:
+//   - the doors open at the first and close after the last door open sample
+//   - arrival is the start of the standstill around the first opening
+//   - departure is the first moving sample after the doors closed
+//   - the passenger change compares the last count before arrival with the
+//     first count after departure
+//
+// Events are numbered into runs by assignRuns.
+func ExtractStopEvents(
+	tripID int32,
+	stopIDs postgres.StopIDs,
+	records []ztbus.TripTelemetry,
+) []postgres.InsertStopEventsParams {
+	at := func(i int) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: time.Unix(int64(records[i].TimeUnix), 0), Valid: true}
+	}
+
+	var events []postgres.InsertStopEventsParams
+	for _, v := range stopVisits(records) {
+		stopID := stopIDs.ID(&v.name)
+		if !stopID.Valid {
+			continue
+		}
+
+		opened, closed := -1, -1
+		for i := v.start; i <= v.end; i++ {
+			if records[i].StatusDoorIsOpen {
+				if opened < 0 {
+					opened = i
+				}
+				closed = i
+			}
+		}
+		if opened < 0 {
+			continue
+		}
+		if closed+1 < len(records) {
+			closed++
+		}
+
+		arrival := opened
+		if standing(records[opened]) {
+			for arrival > 0 && standing(records[arrival-1]) {
+				arrival--
+			}
+		}
+		departure := closed
+		for departure < len(records) && standing(records[departure]) {
+			departure++
+		}
+
+		event := postgres.InsertStopEventsParams{
+			TripID:           tripID,
+			Seq:              int32(len(events) + 1),
+			StopID:           stopID.Int32,
+			ArrivalTime:      at(arrival),
+			DoorOpenTime:     at(opened),
+			DoorCloseTime:    at(closed),
+			DoorOpenS:        int32(records[closed].TimeUnix - records[opened].TimeUnix),
+			PassengersBefore: passengers(records, arrival, -1),
+		}
+		if departure < len(records) {
+			event.DepartureTime = at(departure)
+			event.DwellS = pgtype.Int4{
+				Int32: int32(records[departure].TimeUnix - records[arrival].TimeUnix),
+				Valid: true,
+			}
+			event.PassengersAfter = passengers(records, departure, 1)
+		}
+		if event.PassengersBefore.Valid && event.PassengersAfter.Valid {
+			event.PassengerChange = pgtype.Int4{
+				Int32: event.PassengersAfter.Int32 - event.PassengersBefore.Int32,
+				Valid: true,
+			}
+		}
+		events = append(events, event)
+	}
+	assignRuns(events)
+	return events
+}
+
+// assignRuns splits the stop events of a trip into runs, the single journeys
+// between two termini that a timetable lists as trips. A run ends when the bus
+// comes back to a stop it already served on it, turning at a terminus or
+// finishing a loop, or when there is a gap of more than runLayover in the stop
+// sequence. Runs are numbered from 1.
+func assignRuns(events []postgres.InsertStopEventsParams) {
+	run := int32(1)
+	served := make(map[int32]bool)
+	for i := range events {
+		e := &events[i]
+		if i > 0 {
+			previous := events[i-1]
+			left := previous.DepartureTime
+			if !left.Valid {
+				left = previous.DoorCloseTime
+			}
+			if served[e.StopID] || e.ArrivalTime.Time.Sub(left.Time) > runLayover {
+				run++
+				clear(served)
+			}
+		}
+		served[e.StopID] = true
+		e.Run = run
+	}
+}
+
+// writeStopEvents replaces the stop events stored for a trip
+func writeStopEvents(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	tripID int32,
+	events []postgres.InsertStopEventsParams,
+) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := postgres.New(tx)
+
+	if err := qtx.DeleteStopEventsByTrip(ctx, tripID); err != nil {
+		return fmt.Errorf("could not clear stop events: %v", err)
+	}
+	if len(events) > 0 {
+		if _, err := qtx.InsertStopEvents(ctx, events); err != nil {
+			return fmt.Errorf("error during COPY FROM: %v", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit transaction: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package loader
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// sample is a compact description of one second of telemetry
+type sample struct {
+	speed      float64
+	door       bool
+	grid       bool
+	stop       string // empty if the sample has no stop name
+	passengers int    // negative if unavailable
+}
+
+// tripRecords expands samples into telemetry one second apart, starting at t0
+func tripRecords(samples ...sample) []ztbus.TripTelemetry {
+	records := make([]ztbus.TripTelemetry, len(samples))
+	for i, s := range samples {
+		records[i] = ztbus.TripTelemetry{
+			TimeUnix:              t0 + i,
+			OdometryVehicleSpeed:  s.speed,
+			StatusDoorIsOpen:      s.door,
+			StatusGridIsAvailable: s.grid,
+		}
+		if s.stop != "" {
+			records[i].ItcsStopName = &s.stop
+		}
+		if s.passengers >= 0 {
+			records[i].ItcsNumberOfPassengers = &s.passengers
+		}
+	}
+	return records
+}
+
+const t0 = 1_560_000_000
+
+func ts(i int) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: time.Unix(int64(t0+i), 0), Valid: true}
+}
+
+func int4(v int32) pgtype.Int4 {
+	return pgtype.Int4{Int32: v, Valid: true}
+}
+
+func TestStopVisits(t *testing.T) {
+	records := tripRecords(
+		sample{stop: "A", passengers: -1},
+		sample{passengers: -1},
+		sample{stop: "A", passengers: -1},
+		sample{passengers: -1},
+		sample{stop: "B", passengers: -1},
+		sample{passengers: -1},
+	)
+	want := []stopVisit{
+		{name: "A", start: 0, end: 3},
+		{name: "B", start: 4, end: 5},
+	}
+	if got := stopVisits(records); !reflect.DeepEqual(got, want) {
+		t.Errorf("stopVisits() = %+v, want %+v", got, want)
+	}
+}
+
+func TestExtractStopEvents(t *testing.T) {
+	stopIDs := postgres.StopIDs{"A": 1, "B": 2}
+
+	tests := []struct {
+		name    string
+		samples []sample
+		want    []postgres.InsertStopEventsParams
+	}{
+		{
+			name: "normal stop",
+			samples: []sample{
+				{speed: 5, stop: "A", passengers: 10},
+				{speed: 0, passengers: -1},
+				{speed: 0, door: true, passengers: -1},
+				{speed: 0, door: true, passengers: -1},
+				{speed: 0, passengers: -1},
+				{speed: 3, passengers: 12},
+			},
+			want: []postgres.InsertStopEventsParams{{
+				TripID:           7,
+				Seq:              1,
+				Run:              1,
+				StopID:           1,
+				ArrivalTime:      ts(1),
+				DoorOpenTime:     ts(2),
+				DoorCloseTime:    ts(4),
+				DepartureTime:    ts(5),
+				DwellS:           int4(4),
+				DoorOpenS:        2,
+				PassengersBefore: int4(10),
+				PassengersAfter:  int4(12),
+				PassengerChange:  int4(2),
+			}},
+		},
+		{
+			name: "doors open while rolling",
+			samples: []sample{
+				{speed: 5, stop: "A", passengers: 10},
+				{speed: 2, door: true, passengers: -1},
+				{speed: 0, door: true, passengers: -1},
+				{speed: 0, passengers: -1},
+				{speed: 4, passengers: 9},
+			},
+			want: []postgres.InsertStopEventsParams{{
+				TripID:           7,
+				Seq:              1,
+				Run:              1,
+				StopID:           1,
+				ArrivalTime:      ts(1),
+				DoorOpenTime:     ts(1),
+				DoorCloseTime:    ts(3),
+				DepartureTime:    ts(4),
+				DwellS:           int4(3),
+				DoorOpenS:        2,
+				PassengersBefore: int4(10),
+				PassengersAfter:  int4(9),
+				PassengerChange:  int4(-1),
+			}},
+		},
+		{
+			name: "trip ends before departure",
+			samples: []sample{
+				{speed: 5, stop: "A", passengers: 8},
+				{speed: 0, passengers: -1},
+				{speed: 0, door: true, passengers: -1},
+				{speed: 0, door: true, passengers: 3},
+			},
+			want: []postgres.InsertStopEventsParams{{
+				TripID:           7,
+				Seq:              1,
+				Run:              1,
+				StopID:           1,
+				ArrivalTime:      ts(1),
+				DoorOpenTime:     ts(2),
+				DoorCloseTime:    ts(3),
+				DoorOpenS:        1,
+				PassengersBefore: int4(8),
+			}},
+		},
+		{
+			name: "stop names missing between stops",
+			samples: []sample{
+				{speed: 5, stop: "A", passengers: 4},
+				{speed: 0, passengers: -1},
+				{speed: 0, door: true, passengers: -1},
+				{speed: 3, passengers: 6},
+				{speed: 5, stop: "B", passengers: -1},
+				{speed: 0, passengers: -1},
+				{speed: 0, door: true, passengers: -1},
+				{speed: 2, passengers: 5},
+			},
+			want: []postgres.InsertStopEventsParams{
+				{
+					TripID:           7,
+					Seq:              1,
+					Run:              1,
+					StopID:           1,
+					ArrivalTime:      ts(1),
+					DoorOpenTime:     ts(2),
+					DoorCloseTime:    ts(3),
+					DepartureTime:    ts(3),
+					DwellS:           int4(2),
+					DoorOpenS:        1,
+					PassengersBefore: int4(4),
+					PassengersAfter:  int4(6),
+					PassengerChange:  int4(2),
+				},
+				{
+					TripID:           7,
+					Seq:              2,
+					Run:              1,
+					StopID:           2,
+					ArrivalTime:      ts(5),
+					DoorOpenTime:     ts(6),
+					DoorCloseTime:    ts(7),
+					DepartureTime:    ts(7),
+					DwellS:           int4(2),
+					DoorOpenS:        1,
+					PassengersBefore: int4(6),
+					PassengersAfter:  int4(5),
+					PassengerChange:  int4(-1),
+				},
+			},
+		},
+		{
+			name: "doors never open",
+			samples: []sample{
+				{speed: 5, stop: "A", passengers: 4},
+				{speed: 0, passengers: -1},
+				{speed: 3, passengers: 4},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractStopEvents(7, stopIDs, tripRecords(tt.samples...))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractStopEvents() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssignRuns(t *testing.T) {
+	// a stop event at a stop, arriving and leaving at offsets from t0
+	type visit struct {
+		stop          int32
+		arrive, leave int
+	}
+
+	tests := []struct {
+		name   string
+		visits []visit
+		want   []int32
+	}{
+		{
+			name:   "single run",
+			visits: []visit{{1, 0, 30}, {2, 90, 120}, {3, 200, 230}},
+			want:   []int32{1, 1, 1},
+		},
+		{
+			// the bus turns at stop 3 and serves stop 2 again on the way back
+			name:   "reversal at a terminus",
+			visits: []visit{{1, 0, 30}, {2, 90, 120}, {3, 200, 500}, {2, 600, 630}, {1, 700, 730}},
+			want:   []int32{1, 1, 1, 2, 2},
+		},
+		{
+			name:   "loop",
+			visits: []visit{{1, 0, 30}, {2, 90, 120}, {3, 200, 230}, {1, 300, 330}, {2, 400, 430}},
+			want:   []int32{1, 1, 1, 2, 2},
+		},
+		{
+			name:   "layover of exactly the limit",
+			visits: []visit{{1, 0, 30}, {2, 930, 960}},
+			want:   []int32{1, 1},
+		},
+		{
+			name:   "gap in the stop sequence",
+			visits: []visit{{1, 0, 30}, {2, 931, 960}, {3, 1000, 1030}},
+			want:   []int32{1, 2, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := make([]postgres.InsertStopEventsParams, len(tt.visits))
+			for i, v := range tt.visits {
+				events[i] = postgres.InsertStopEventsParams{
+					Seq:           int32(i + 1),
+					StopID:        v.stop,
+					ArrivalTime:   ts(v.arrive),
+					DepartureTime: ts(v.leave),
+				}
+			}
+			assignRuns(events)
+
+			got := make([]int32, len(events))
+			for i, e := range events {
+				got[i] = e.Run
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("assignRuns() runs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package loader
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// styles for the end of run summary
+var (
+	summaryHeaderStyle = lipgloss.NewStyle().
+				Bold(true).
+				Underline(true)
+	summaryLabelStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("12")). // Blue text
+				MarginLeft(2)
+)
+
+// LoadSummary collects the outcome of a data load for reporting
+type LoadSummary struct {
+	MetadataTrips    int
+	Filters          []FilterStep
+	LoadedTrips      int
+	SkippedTrips     int
+	TelemetryRecords int64
+	Resample         string
+	ResampledWindows int64
+	RollupTrips      int
+	Stops            int
+	StopEvents       int
+	TripSegments     int
+	EnergyKwh        float64
+	SnappedTrips     int
+	Partitions       []postgres.ListTelemetryPartitionLayoutRow
+	Duration         time.Duration
+
+	// metadata cross-check
+	VerifiedTrips     int
+	InconsistentTrips []string
+
+	// timeline checks
+	TimelineTrips       int
+	DuplicateTimestamps int
+	BackwardJumps       int
+	Gaps                int
+	MissingSeconds      int
+
+	// plausibility checks
+	PlausibilityTrips int
+	ImplausibleTrips  int
+	ImplausibleValues int
+	NulledValues      int
+}
+
+// AddTimeline records the outcome of a timeline check
+func (s *LoadSummary) AddTimeline(r TimelineReport) {
+	s.TimelineTrips++
+	s.DuplicateTimestamps += r.DuplicateTimestamps
+	s.BackwardJumps += r.BackwardJumps
+	s.Gaps += len(r.Gaps)
+	s.MissingSeconds += r.MissingSeconds
+}
+
+// AddPlausibility records the outcome of a plausibility check
+func (s *LoadSummary) AddPlausibility(r PlausibilityReport) {
+	s.PlausibilityTrips++
+	if r.Total > 0 {
+		s.ImplausibleTrips++
+	}
+	s.ImplausibleValues += r.Total
+	s.NulledValues += r.Nulled
+}
+
+// number of inconsistent trips listed by name in the summary
+const maxListedInconsistentTrips = 10
+
+// AddQuality records the outcome of a metadata check
+func (s *LoadSummary) AddQuality(name string, q postgres.UpsertTripQualityParams) {
+	s.VerifiedTrips++
+	if !q.MetadataConsistent.Bool {
+		s.InconsistentTrips = append(
+			s.InconsistentTrips,
+			fmt.Sprintf("%s (%s)", name, strings.Join(q.Mismatches, ", ")),
+		)
+	}
+}
+
+func (s LoadSummary) line(sb *strings.Builder, label string, value any) {
+	sb.WriteString("\n")
+	sb.WriteString(summaryLabelStyle.Render(fmt.Sprintf("%-22s", label)))
+	sb.WriteString(fmt.Sprintf(" %v", value))
+}
+
+// Render formats the summary for display on a terminal
+func (s LoadSummary) Render() string {
+	var sb strings.Builder
+	sb.WriteString(summaryHeaderStyle.Render("Load Summary"))
+
+	s.line(&sb, "trips in metadata", s.MetadataTrips)
+	for _, f := range s.Filters {
+		s.line(&sb, "filter "+f.Name, fmt.Sprintf("%s -> %d trips", f.Value, f.Remaining))
+	}
+	s.line(&sb, "trips loaded", s.LoadedTrips)
+	if s.SkippedTrips > 0 {
+		s.line(&sb, "trips without telemetry", s.SkippedTrips)
+	}
+	s.line(&sb, "telemetry records", s.TelemetryRecords)
+	if s.Resample != "" {
+		s.line(&sb, "resampled windows", fmt.Sprintf("%d @ %s", s.ResampledWindows, s.Resample))
+	}
+	if s.VerifiedTrips > 0 {
+		s.line(&sb, "metadata consistent", fmt.Sprintf(
+			"%d/%d trips",
+			s.VerifiedTrips-len(s.InconsistentTrips),
+			s.VerifiedTrips,
+		))
+		for i, trip := range s.InconsistentTrips {
+			if i == maxListedInconsistentTrips {
+				s.line(&sb, "", fmt.Sprintf(
+					"... and %d more, see trip_quality",
+					len(s.InconsistentTrips)-i,
+				))
+				break
+			}
+			s.line(&sb, "", trip)
+		}
+	}
+	if s.TimelineTrips > 0 {
+		s.line(&sb, "timeline checked", fmt.Sprintf("%d trips", s.TimelineTrips))
+		s.line(&sb, "duplicate timestamps", s.DuplicateTimestamps)
+		s.line(&sb, "backward jumps", s.BackwardJumps)
+		s.line(&sb, "gaps", fmt.Sprintf("%d (%d s missing)", s.Gaps, s.MissingSeconds))
+	}
+	if s.PlausibilityTrips > 0 {
+		s.line(&sb, "plausibility checked", fmt.Sprintf(
+			"%d trips, %d with violations",
+			s.PlausibilityTrips,
+			s.ImplausibleTrips,
+		))
+		s.line(&sb, "implausible values", fmt.Sprintf(
+			"%d (%d nulled)",
+			s.ImplausibleValues,
+			s.NulledValues,
+		))
+	}
+	if s.RollupTrips > 0 {
+		s.line(&sb, "rollups refreshed", fmt.Sprintf("%d trips", s.RollupTrips))
+	}
+	if s.EnergyKwh != 0 {
+		s.line(&sb, "energy", fmt.Sprintf("%.1f kWh", s.EnergyKwh))
+	}
+	if s.TripSegments > 0 {
+		s.line(&sb, "trip segments", fmt.Sprint(s.TripSegments))
+	}
+	if s.Stops > 0 {
+		s.line(&sb, "stops", fmt.Sprintf("%d (%d stop events)", s.Stops, s.StopEvents))
+	}
+	if s.SnappedTrips > 0 {
+		s.line(&sb, "snapped to routes", fmt.Sprintf("%d trips", s.SnappedTrips))
+	}
+	if len(s.Partitions) > 0 {
+		var defaultRows int64
+		var ranged []string
+		for _, p := range s.Partitions {
+			if p.Bounds == "DEFAULT" {
+				defaultRows += p.EstimatedRows
+				continue
+			}
+			ranged = append(ranged, p.Partition)
+		}
+		if len(ranged) > 0 {
+			s.line(&sb, "telemetry partitions", fmt.Sprintf(
+				"%d (%s .. %s)",
+				len(ranged),
+				ranged[0],
+				ranged[len(ranged)-1],
+			))
+		}
+		s.line(&sb, "default partition", fmt.Sprintf("~%d rows", defaultRows))
+	}
+	s.line(&sb, "duration", s.Duration.Round(time.Second))
+
+	return sb.String()
+}
+
+// Print writes the rendered summary to w
+func (s LoadSummary) Print(w io.Writer) {
+	fmt.Fprintln(w, s.Render())
+}
@@ -0,0 +1,173 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TimelineCheck inspects the time_unix sequence of a trip for duplicate
+// seconds, backwards jumps and gaps, and optionally repairs the order
+type TimelineCheck struct {
+	GapThreshold time.Duration
+	Sort         bool
+	Dedupe       bool
+}
+
+// TimelineReport is the outcome of a TimelineCheck for one trip
+type TimelineReport struct {
+	DuplicateTimestamps int // samples sharing a second with an earlier sample
+	BackwardJumps       int // samples earlier than their predecessor
+	Gaps                []postgres.InsertTelemetryGapsParams
+	MissingSeconds      int
+	Sorted              bool
+	Deduplicated        bool
+}
+
+// NewTimelineCheck builds a TimelineCheck from the load options
+func NewTimelineCheck(opts Options) (TimelineCheck, error) {
+	if !opts.CheckTimeline {
+		if opts.SortTelemetry || opts.DedupeTelemetry {
+			return TimelineCheck{}, fmt.Errorf(
+				"options SortTelemetry and DedupeTelemetry require CheckTimeline",
+			)
+		}
+		return TimelineCheck{}, nil
+	}
+	if opts.GapThreshold < time.Second || opts.GapThreshold%time.Second != 0 {
+		return TimelineCheck{}, fmt.Errorf(
+			"option GapThreshold must be a whole number of seconds of at least 1s, got %s",
+			opts.GapThreshold,
+		)
+	}
+	return TimelineCheck{
+		GapThreshold: opts.GapThreshold,
+		Sort:         opts.SortTelemetry,
+		Dedupe:       opts.DedupeTelemetry,
+	}, nil
+}
+
+// Enabled reports whether timeline checks were requested
+func (c TimelineCheck) Enabled() bool {
+	return c.GapThreshold > 0
+}
+
+// sortedByTime returns a time sorted copy of the records. The sort is stable
+// so that the first of several duplicate samples stays first.
+func sortedByTime(records []ztbus.TripTelemetry) []ztbus.TripTelemetry {
+	sorted := make([]ztbus.TripTelemetry, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TimeUnix < sorted[j].TimeUnix
+	})
+	return sorted
+}
+
+// Apply checks the records of a trip. It returns the report along with the
+// records to load, which are sorted and/or deduplicated if configured.
+func (c TimelineCheck) Apply(
+	tripID int32,
+	records []ztbus.TripTelemetry,
+) (TimelineReport, []ztbus.TripTelemetry) {
+	var report TimelineReport
+
+	seen := make(map[int]bool, len(records))
+	for i, t := range records {
+		if seen[t.TimeUnix] {
+			report.DuplicateTimestamps++
+		}
+		seen[t.TimeUnix] = true
+		if i > 0 && t.TimeUnix < records[i-1].TimeUnix {
+			report.BackwardJumps++
+		}
+	}
+
+	// gaps describe coverage, so they are found on the ordered timeline
+	// whether or not the records get reordered
+	sorted := records
+	if report.BackwardJumps > 0 {
+		sorted = sortedByTime(records)
+	}
+	threshold := int(c.GapThreshold / time.Second)
+	for i := 1; i < len(sorted); i++ {
+		prev, next := sorted[i-1].TimeUnix, sorted[i].TimeUnix
+		if next-prev <= threshold {
+			continue
+		}
+		report.Gaps = append(report.Gaps, postgres.InsertTelemetryGapsParams{
+			TripID:    tripID,
+			GapStart:  pgtype.Timestamptz{Time: time.Unix(int64(prev), 0), Valid: true},
+			GapEnd:    pgtype.Timestamptz{Time: time.Unix(int64(next), 0), Valid: true},
+			DurationS: int32(next - prev),
+		})
+		report.MissingSeconds += next - prev - 1
+	}
+
+	out := records
+	if c.Sort && report.BackwardJumps > 0 {
+		out = sorted
+		report.Sorted = true
+	}
+	if c.Dedupe && report.DuplicateTimestamps > 0 {
+		kept := make(map[int]bool, len(out))
+		deduped := make([]ztbus.TripTelemetry, 0, len(out)-report.DuplicateTimestamps)
+		for _, t := range out {
+			if !kept[t.TimeUnix] {
+				kept[t.TimeUnix] = true
+				deduped = append(deduped, t)
+			}
+		}
+		out = deduped
+		report.Deduplicated = true
+	}
+
+	return report, out
+}
+
+// writeTimelineReport replaces the trip's gaps and timeline quality columns
+func writeTimelineReport(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	tripID int32,
+	report TimelineReport,
+) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := postgres.New(tx)
+
+	if err := qtx.DeleteTelemetryGapsByTrip(ctx, tripID); err != nil {
+		return fmt.Errorf("could not clear telemetry gaps: %v", err)
+	}
+	if len(report.Gaps) > 0 {
+		if _, err := qtx.InsertTelemetryGaps(ctx, report.Gaps); err != nil {
+			return fmt.Errorf("error during COPY FROM: %v", err)
+		}
+	}
+
+	err = qtx.UpsertTripTimelineQuality(ctx, postgres.UpsertTripTimelineQualityParams{
+		TripID:               tripID,
+		DuplicateTimestamps:  pgtype.Int4{Int32: int32(report.DuplicateTimestamps), Valid: true},
+		BackwardJumps:        pgtype.Int4{Int32: int32(report.BackwardJumps), Valid: true},
+		GapCount:             pgtype.Int4{Int32: int32(len(report.Gaps)), Valid: true},
+		MissingSeconds:       pgtype.Int4{Int32: int32(report.MissingSeconds), Valid: true},
+		TimelineSorted:       pgtype.Bool{Bool: report.Sorted, Valid: true},
+		TimelineDeduplicated: pgtype.Bool{Bool: report.Deduplicated, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("could not store timeline quality: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit transaction: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package loader
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+)
+
+// timeline returns one record per offset from t0, in the given order. The
+// power demand holds the record's input position to tell duplicates apart.
+func timeline(offsets ...int) []ztbus.TripTelemetry {
+	records := make([]ztbus.TripTelemetry, len(offsets))
+	for i, at := range offsets {
+		records[i] = ztbus.TripTelemetry{TimeUnix: t0 + at, ElectricPowerDemand: float64(i)}
+	}
+	return records
+}
+
+func TestTimelineCheckApply(t *testing.T) {
+	// a gap as offsets from t0 and its length in seconds
+	type gap struct{ start, end, duration int }
+
+	tests := []struct {
+		name          string
+		check         TimelineCheck
+		offsets       []int
+		duplicates    int
+		backwards     int
+		gaps          []gap
+		missing       int
+		sorted, dedup bool
+		wantPositions []int // input positions of the records to load
+	}{
+		{
+			name:          "clean",
+			offsets:       []int{0, 1, 2, 3},
+			wantPositions: []int{0, 1, 2, 3},
+		},
+		{
+			name:          "duplicate",
+			offsets:       []int{0, 1, 1, 2},
+			duplicates:    1,
+			wantPositions: []int{0, 1, 2, 3},
+		},
+		{
+			name:          "backwards",
+			offsets:       []int{0, 1, 3, 2, 4},
+			backwards:     1,
+			wantPositions: []int{0, 1, 2, 3, 4},
+		},
+		{
+			// a jump back onto a seen second counts as both
+			name:          "jump back onto a seen second",
+			offsets:       []int{0, 1, 2, 1, 3},
+			duplicates:    1,
+			backwards:     1,
+			wantPositions: []int{0, 1, 2, 3, 4},
+		},
+		{
+			// a gap of exactly the threshold is not reported
+			name:          "gaps",
+			offsets:       []int{0, 5, 6, 20},
+			gaps:          []gap{{6, 20, 14}},
+			missing:       13,
+			wantPositions: []int{0, 1, 2, 3},
+		},
+		{
+			// gaps are found on the ordered timeline even when not sorting
+			name:          "gap in unsorted records",
+			offsets:       []int{20, 0, 1},
+			backwards:     1,
+			gaps:          []gap{{1, 20, 19}},
+			missing:       18,
+			wantPositions: []int{0, 1, 2},
+		},
+		{
+			name:          "sort",
+			check:         TimelineCheck{Sort: true},
+			offsets:       []int{0, 2, 1, 3},
+			backwards:     1,
+			sorted:        true,
+			wantPositions: []int{0, 2, 1, 3},
+		},
+		{
+			name:          "sort keeps ordered records",
+			check:         TimelineCheck{Sort: true},
+			offsets:       []int{0, 1, 2},
+			wantPositions: []int{0, 1, 2},
+		},
+		{
+			// the first of the duplicates is kept
+			name:          "dedupe",
+			check:         TimelineCheck{Dedupe: true},
+			offsets:       []int{0, 1, 1, 2, 2},
+			duplicates:    2,
+			dedup:         true,
+			wantPositions: []int{0, 1, 3},
+		},
+		{
+			// sorting is stable, so the earlier of two duplicates is still kept
+			name:          "sort and dedupe",
+			check:         TimelineCheck{Sort: true, Dedupe: true},
+			offsets:       []int{2, 0, 1, 0, 2},
+			duplicates:    2,
+			backwards:     2,
+			sorted:        true,
+			dedup:         true,
+			wantPositions: []int{1, 2, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.check
+			c.GapThreshold = 5 * time.Second
+			report, out := c.Apply(7, timeline(tt.offsets...))
+
+			if report.DuplicateTimestamps != tt.duplicates || report.BackwardJumps != tt.backwards {
+				t.Errorf(
+					"Apply() found %d duplicates and %d backward jumps, want %d and %d",
+					report.DuplicateTimestamps, report.BackwardJumps, tt.duplicates, tt.backwards,
+				)
+			}
+			var gaps []gap
+			for _, g := range report.Gaps {
+				if g.TripID != 7 {
+					t.Errorf("gap is for trip %d, want 7", g.TripID)
+				}
+				gaps = append(gaps, gap{
+					int(g.GapStart.Time.Unix() - t0),
+					int(g.GapEnd.Time.Unix() - t0),
+					int(g.DurationS),
+				})
+			}
+			if !reflect.DeepEqual(gaps, tt.gaps) || report.MissingSeconds != tt.missing {
+				t.Errorf(
+					"Apply() gaps = %v missing %d, want %v missing %d",
+					gaps, report.MissingSeconds, tt.gaps, tt.missing,
+				)
+			}
+			if report.Sorted != tt.sorted || report.Deduplicated != tt.dedup {
+				t.Errorf(
+					"Apply() sorted %v deduplicated %v, want %v and %v",
+					report.Sorted, report.Deduplicated, tt.sorted, tt.dedup,
+				)
+			}
+
+			positions := make([]int, len(out))
+			for i, r := range out {
+				positions[i] = int(r.ElectricPowerDemand)
+			}
+			if !reflect.DeepEqual(positions, tt.wantPositions) {
+				t.Errorf("Apply() kept records %v, want %v", positions, tt.wantPositions)
+			}
+		})
+	}
+}
+
+func TestNewTimelineCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{"disabled", Options{}, false},
+		{"enabled", Options{CheckTimeline: true, GapThreshold: 5 * time.Second}, false},
+		{"sort without check", Options{SortTelemetry: true}, true},
+		{"dedupe without check", Options{DedupeTelemetry: true}, true},
+		{"sub-second threshold", Options{CheckTimeline: true, GapThreshold: 500 * time.Millisecond}, true},
+		{"fractional threshold", Options{CheckTimeline: true, GapThreshold: 1500 * time.Millisecond}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTimelineCheck(tt.opts); (err != nil) != tt.wantErr {
+				t.Errorf("NewTimelineCheck() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
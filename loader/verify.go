@@ -0,0 +1,179 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// metrics compared against metaData.csv. The absolute floor avoids flagging
+// tiny trips where a relative tolerance would be meaninglessly small.
+var metadataMetrics = []struct {
+	name  string
+	floor float64
+}{
+	{"driven_distance_m", 10},       // m
+	{"energy_consumption_j", 36000}, // J (0.01 kWh)
+	{"passengers_mean", 0.5},
+	{"passengers_min", 0.5},
+	{"passengers_max", 0.5},
+	{"temperature_mean", 0.5}, // K
+	{"temperature_min", 0.5},  // K
+	{"temperature_max", 0.5},  // K
+}
+
+// tripStats are the metaData.csv summaries recomputed from a trip's telemetry
+type tripStats struct {
+	DrivenDistance    float64 // m, trapezoidal integration of odometry_vehicleSpeed
+	EnergyConsumption float64 // J, trapezoidal integration of electric_powerDemand
+	Passengers        windowStat
+	Temperature       windowStat
+}
+
+// computeTripStats recomputes the metadata summaries the same way the ZTBus
+// authors did. Samples that do not move forward in time are not integrated,
+// and unavailable values contribute nothing to the integrals.
+func computeTripStats(records []ztbus.TripTelemetry) tripStats {
+	var stats tripStats
+	for i, t := range records {
+		if t.ItcsNumberOfPassengers != nil {
+			stats.Passengers.add(float64(*t.ItcsNumberOfPassengers))
+		}
+		stats.Temperature.add(t.TemperatureAmbient)
+
+		if i == 0 {
+			continue
+		}
+		prev := records[i-1]
+		dt := float64(t.TimeUnix - prev.TimeUnix)
+		if dt <= 0 {
+			continue
+		}
+		stats.DrivenDistance += trapezoid(prev.OdometryVehicleSpeed, t.OdometryVehicleSpeed, dt)
+		stats.EnergyConsumption += trapezoid(prev.ElectricPowerDemand, t.ElectricPowerDemand, dt)
+	}
+	return stats
+}
+
+// trapezoid integrates over one step, skipping steps with an unavailable end
+func trapezoid(a, b, dt float64) float64 {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return 0
+	}
+	return 0.5 * (a + b) * dt
+}
+
+// MetadataCheck compares metaData.csv against the telemetry of each trip
+type MetadataCheck struct {
+	Tolerance float64 // relative
+}
+
+// NewMetadataCheck builds a MetadataCheck from the load options
+func NewMetadataCheck(opts Options) (MetadataCheck, error) {
+	if !opts.Verify {
+		return MetadataCheck{}, nil
+	}
+	if opts.VerifyTolerance <= 0 {
+		return MetadataCheck{}, fmt.Errorf(
+			"option VerifyTolerance must be positive, got %g",
+			opts.VerifyTolerance,
+		)
+	}
+	return MetadataCheck{Tolerance: opts.VerifyTolerance}, nil
+}
+
+// Enabled reports whether verification was requested
+func (c MetadataCheck) Enabled() bool {
+	return c.Tolerance > 0
+}
+
+func (c MetadataCheck) agrees(meta, calc pgtype.Float8, floor float64) bool {
+	if !meta.Valid || !calc.Valid {
+		// nothing to compare, e.g. a trip without passenger counts
+		return meta.Valid == calc.Valid
+	}
+	return math.Abs(meta.Float64-calc.Float64) <= max(floor, c.Tolerance*math.Abs(meta.Float64))
+}
+
+func float8(v float64) pgtype.Float8 {
+	return pgtype.Float8{Float64: v, Valid: !math.IsNaN(v)}
+}
+
+// aggFloat8 returns the mean, min and max of a stat, NULL if it is empty
+func aggFloat8(s windowStat) (pgtype.Float8, pgtype.Float8, pgtype.Float8) {
+	if s.n == 0 {
+		return pgtype.Float8{}, pgtype.Float8{}, pgtype.Float8{}
+	}
+	return float8(s.sum / float64(s.n)), float8(s.min), float8(s.max)
+}
+
+// Check recomputes the trip's summaries and compares them with its metadata
+func (c MetadataCheck) Check(
+	tripID int32,
+	m ztbus.Metadata,
+	records []ztbus.TripTelemetry,
+) postgres.UpsertTripQualityParams {
+	stats := computeTripStats(records)
+
+	p := postgres.UpsertTripQualityParams{
+		TripID:                 tripID,
+		Tolerance:              float8(c.Tolerance),
+		DrivenDistanceMMeta:    float8(m.DrivenDistance),
+		DrivenDistanceMCalc:    float8(stats.DrivenDistance),
+		EnergyConsumptionJMeta: float8(float64(m.EnergyConsumption)),
+		EnergyConsumptionJCalc: float8(stats.EnergyConsumption),
+		PassengersMeanMeta:     float8(m.ItcsNumberOfPassengersMean),
+		PassengersMinMeta:      float8(float64(m.ItcsNumberOfPassengersMin)),
+		PassengersMaxMeta:      float8(float64(m.ItcsNumberOfPassengersMax)),
+		TemperatureMeanMeta:    float8(m.TemperatureAmbientMean),
+		TemperatureMinMeta:     float8(m.TemperatureAmbientMin),
+		TemperatureMaxMeta:     float8(m.TemperatureAmbientMax),
+	}
+	p.PassengersMeanCalc, p.PassengersMinCalc, p.PassengersMaxCalc = aggFloat8(stats.Passengers)
+	p.TemperatureMeanCalc, p.TemperatureMinCalc, p.TemperatureMaxCalc = aggFloat8(stats.Temperature)
+
+	// passenger min/max are parsed as integers, so a NaN in metaData.csv
+	// shows up as 0. The mean tells whether counts were available at all.
+	if !p.PassengersMeanMeta.Valid {
+		p.PassengersMinMeta = pgtype.Float8{}
+		p.PassengersMaxMeta = pgtype.Float8{}
+	}
+
+	pairs := map[string][2]pgtype.Float8{
+		"driven_distance_m":    {p.DrivenDistanceMMeta, p.DrivenDistanceMCalc},
+		"energy_consumption_j": {p.EnergyConsumptionJMeta, p.EnergyConsumptionJCalc},
+		"passengers_mean":      {p.PassengersMeanMeta, p.PassengersMeanCalc},
+		"passengers_min":       {p.PassengersMinMeta, p.PassengersMinCalc},
+		"passengers_max":       {p.PassengersMaxMeta, p.PassengersMaxCalc},
+		"temperature_mean":     {p.TemperatureMeanMeta, p.TemperatureMeanCalc},
+		"temperature_min":      {p.TemperatureMinMeta, p.TemperatureMinCalc},
+		"temperature_max":      {p.TemperatureMaxMeta, p.TemperatureMaxCalc},
+	}
+	p.Mismatches = []string{}
+	for _, metric := range metadataMetrics {
+		pair := pairs[metric.name]
+		if !c.agrees(pair[0], pair[1], metric.floor) {
+			p.Mismatches = append(p.Mismatches, metric.name)
+		}
+	}
+	p.MetadataConsistent = pgtype.Bool{Bool: len(p.Mismatches) == 0, Valid: true}
+
+	return p
+}
+
+// writeTripQuality stores the result of a metadata check
+func writeTripQuality(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	p postgres.UpsertTripQualityParams,
+) error {
+	if err := postgres.New(pool).UpsertTripQuality(ctx, p); err != nil {
+		return fmt.Errorf("could not store trip quality: %v", err)
+	}
+	return nil
+}
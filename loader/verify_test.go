@@ -0,0 +1,199 @@
+package loader
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+)
+
+// verifyRecords returns 11 records over 10 s at a constant speed, drawing
+// 360 kW with 5 passengers at 290 K
+func verifyRecords(speed float64, passengers bool) []ztbus.TripTelemetry {
+	records := make([]ztbus.TripTelemetry, 11)
+	for i := range records {
+		records[i] = ztbus.TripTelemetry{
+			TimeUnix:             t0 + i,
+			ElectricPowerDemand:  360_000,
+			OdometryVehicleSpeed: speed,
+			TemperatureAmbient:   290,
+		}
+		if passengers {
+			n := 5
+			records[i].ItcsNumberOfPassengers = &n
+		}
+	}
+	return records
+}
+
+// verifyMeta matches verifyRecords at 20 m/s
+func verifyMeta() ztbus.Metadata {
+	return ztbus.Metadata{
+		DrivenDistance:             200,
+		EnergyConsumption:          3_600_000,
+		ItcsNumberOfPassengersMean: 5,
+		ItcsNumberOfPassengersMin:  5,
+		ItcsNumberOfPassengersMax:  5,
+		TemperatureAmbientMean:     290,
+		TemperatureAmbientMin:      290,
+		TemperatureAmbientMax:      290,
+	}
+}
+
+func TestMetadataCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		tolerance  float64
+		speed      float64
+		passengers bool
+		meta       func(m *ztbus.Metadata)
+		want       []string
+	}{
+		{
+			name:       "consistent",
+			tolerance:  0.25,
+			speed:      20,
+			passengers: true,
+			meta:       func(m *ztbus.Metadata) {},
+			want:       []string{},
+		},
+		{
+			// 40 m off, exactly a quarter of the recorded 160 m
+			name:       "at the tolerance",
+			tolerance:  0.25,
+			speed:      20,
+			passengers: true,
+			meta:       func(m *ztbus.Metadata) { m.DrivenDistance = 160 },
+			want:       []string{},
+		},
+		{
+			name:       "beyond the tolerance",
+			tolerance:  0.25,
+			speed:      20,
+			passengers: true,
+			meta:       func(m *ztbus.Metadata) { m.DrivenDistance = 159 },
+			want:       []string{"driven_distance_m"},
+		},
+		{
+			// the tolerance is relative to metaData.csv, not the telemetry
+			name:       "relative to the metadata",
+			tolerance:  0.25,
+			speed:      20,
+			passengers: true,
+			meta:       func(m *ztbus.Metadata) { m.EnergyConsumption = 4_800_000 },
+			want:       []string{},
+		},
+		{
+			name:       "floor for a short trip",
+			tolerance:  0.25,
+			speed:      0,
+			passengers: true,
+			meta:       func(m *ztbus.Metadata) { m.DrivenDistance = 10 },
+			want:       []string{},
+		},
+		{
+			name:       "beyond the floor",
+			tolerance:  0.25,
+			speed:      0,
+			passengers: true,
+			meta:       func(m *ztbus.Metadata) { m.DrivenDistance = 10.5 },
+			want:       []string{"driven_distance_m"},
+		},
+		{
+			name:       "temperature floor",
+			tolerance:  0.001,
+			speed:      20,
+			passengers: true,
+			meta: func(m *ztbus.Metadata) {
+				m.TemperatureAmbientMin = 289.5
+				m.TemperatureAmbientMax = 290.75
+			},
+			want: []string{"temperature_max"},
+		},
+		{
+			// NaN counts in metaData.csv parse as a min and max of 0
+			name:      "passengers unavailable in both",
+			tolerance: 0.25,
+			speed:     20,
+			meta: func(m *ztbus.Metadata) {
+				m.ItcsNumberOfPassengersMean = math.NaN()
+				m.ItcsNumberOfPassengersMin = 0
+				m.ItcsNumberOfPassengersMax = 0
+			},
+			want: []string{},
+		},
+		{
+			name:       "passengers only in the telemetry",
+			tolerance:  0.25,
+			speed:      20,
+			passengers: true,
+			meta: func(m *ztbus.Metadata) {
+				m.ItcsNumberOfPassengersMean = math.NaN()
+				m.ItcsNumberOfPassengersMin = 0
+				m.ItcsNumberOfPassengersMax = 0
+			},
+			want: []string{"passengers_mean", "passengers_min", "passengers_max"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := verifyMeta()
+			tt.meta(&m)
+			c := MetadataCheck{Tolerance: tt.tolerance}
+			p := c.Check(7, m, verifyRecords(tt.speed, tt.passengers))
+
+			if !reflect.DeepEqual(p.Mismatches, tt.want) {
+				t.Errorf("Check() mismatches = %v, want %v", p.Mismatches, tt.want)
+			}
+			if !p.MetadataConsistent.Valid || p.MetadataConsistent.Bool != (len(tt.want) == 0) {
+				t.Errorf("Check() consistent = %+v with mismatches %v", p.MetadataConsistent, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeTripStats(t *testing.T) {
+	records := verifyRecords(20, true)
+	// a repeated timestamp and an unavailable power reading
+	records[4].TimeUnix = records[3].TimeUnix
+	records[8].ElectricPowerDemand = math.NaN()
+
+	stats := computeTripStats(records)
+	// the repeated second adds nothing and the step after it spans 2 s
+	if stats.DrivenDistance != 200 {
+		t.Errorf("distance = %g m, want 200", stats.DrivenDistance)
+	}
+	// the steps either side of the unavailable reading contribute nothing
+	if stats.EnergyConsumption != 8*360_000 {
+		t.Errorf("energy = %g J, want %d", stats.EnergyConsumption, 8*360_000)
+	}
+	if stats.Passengers.n != 11 || stats.Temperature.n != 11 {
+		t.Errorf("stats over %d and %d records, want 11", stats.Passengers.n, stats.Temperature.n)
+	}
+}
+
+func TestNewMetadataCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		enabled bool
+		wantErr bool
+	}{
+		{"disabled", Options{VerifyTolerance: -1}, false, false},
+		{"enabled", Options{Verify: true, VerifyTolerance: 0.05}, true, false},
+		{"zero tolerance", Options{Verify: true}, false, true},
+		{"negative tolerance", Options{Verify: true, VerifyTolerance: -0.05}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewMetadataCheck(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewMetadataCheck() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if c.Enabled() != tt.enabled {
+				t.Errorf("Enabled() = %v, want %v", c.Enabled(), tt.enabled)
+			}
+		})
+	}
+}
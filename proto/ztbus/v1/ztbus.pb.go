@@ -0,0 +1,1794 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: ztbus/v1/ztbus.proto
+
+package ztbusv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Bus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	BusNumber     *string                `protobuf:"bytes,2,opt,name=bus_number,json=busNumber,proto3,oneof" json:"bus_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Bus) Reset() {
+	*x = Bus{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bus) ProtoMessage() {}
+
+func (x *Bus) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bus.ProtoReflect.Descriptor instead.
+func (*Bus) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Bus) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Bus) GetBusNumber() string {
+	if x != nil && x.BusNumber != nil {
+		return *x.BusNumber
+	}
+	return ""
+}
+
+type BusRoute struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	RouteCode     *string                `protobuf:"bytes,2,opt,name=route_code,json=routeCode,proto3,oneof" json:"route_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BusRoute) Reset() {
+	*x = BusRoute{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BusRoute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BusRoute) ProtoMessage() {}
+
+func (x *BusRoute) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BusRoute.ProtoReflect.Descriptor instead.
+func (*BusRoute) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BusRoute) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BusRoute) GetRouteCode() string {
+	if x != nil && x.RouteCode != nil {
+		return *x.RouteCode
+	}
+	return ""
+}
+
+type Trip struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Id                   int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	BusId                *int32                 `protobuf:"varint,3,opt,name=bus_id,json=busId,proto3,oneof" json:"bus_id,omitempty"`
+	RouteId              *int32                 `protobuf:"varint,4,opt,name=route_id,json=routeId,proto3,oneof" json:"route_id,omitempty"`
+	StartTime            *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime              *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	DrivenDistanceKm     *float32               `protobuf:"fixed32,7,opt,name=driven_distance_km,json=drivenDistanceKm,proto3,oneof" json:"driven_distance_km,omitempty"`
+	EnergyConsumptionKwh *int32                 `protobuf:"varint,8,opt,name=energy_consumption_kwh,json=energyConsumptionKwh,proto3,oneof" json:"energy_consumption_kwh,omitempty"`
+	ItcsPassengersMean   *float32               `protobuf:"fixed32,9,opt,name=itcs_passengers_mean,json=itcsPassengersMean,proto3,oneof" json:"itcs_passengers_mean,omitempty"`
+	ItcsPassengersMin    *int32                 `protobuf:"varint,10,opt,name=itcs_passengers_min,json=itcsPassengersMin,proto3,oneof" json:"itcs_passengers_min,omitempty"`
+	ItcsPassengersMax    *int32                 `protobuf:"varint,11,opt,name=itcs_passengers_max,json=itcsPassengersMax,proto3,oneof" json:"itcs_passengers_max,omitempty"`
+	GridAvailableMean    *float32               `protobuf:"fixed32,12,opt,name=grid_available_mean,json=gridAvailableMean,proto3,oneof" json:"grid_available_mean,omitempty"`
+	AmbTemperatureMean   *float32               `protobuf:"fixed32,13,opt,name=amb_temperature_mean,json=ambTemperatureMean,proto3,oneof" json:"amb_temperature_mean,omitempty"`
+	AmbTemperatureMin    *float32               `protobuf:"fixed32,14,opt,name=amb_temperature_min,json=ambTemperatureMin,proto3,oneof" json:"amb_temperature_min,omitempty"`
+	AmbTemperatureMax    *float32               `protobuf:"fixed32,15,opt,name=amb_temperature_max,json=ambTemperatureMax,proto3,oneof" json:"amb_temperature_max,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Trip) Reset() {
+	*x = Trip{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Trip) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trip) ProtoMessage() {}
+
+func (x *Trip) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trip.ProtoReflect.Descriptor instead.
+func (*Trip) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Trip) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Trip) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Trip) GetBusId() int32 {
+	if x != nil && x.BusId != nil {
+		return *x.BusId
+	}
+	return 0
+}
+
+func (x *Trip) GetRouteId() int32 {
+	if x != nil && x.RouteId != nil {
+		return *x.RouteId
+	}
+	return 0
+}
+
+func (x *Trip) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *Trip) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *Trip) GetDrivenDistanceKm() float32 {
+	if x != nil && x.DrivenDistanceKm != nil {
+		return *x.DrivenDistanceKm
+	}
+	return 0
+}
+
+func (x *Trip) GetEnergyConsumptionKwh() int32 {
+	if x != nil && x.EnergyConsumptionKwh != nil {
+		return *x.EnergyConsumptionKwh
+	}
+	return 0
+}
+
+func (x *Trip) GetItcsPassengersMean() float32 {
+	if x != nil && x.ItcsPassengersMean != nil {
+		return *x.ItcsPassengersMean
+	}
+	return 0
+}
+
+func (x *Trip) GetItcsPassengersMin() int32 {
+	if x != nil && x.ItcsPassengersMin != nil {
+		return *x.ItcsPassengersMin
+	}
+	return 0
+}
+
+func (x *Trip) GetItcsPassengersMax() int32 {
+	if x != nil && x.ItcsPassengersMax != nil {
+		return *x.ItcsPassengersMax
+	}
+	return 0
+}
+
+func (x *Trip) GetGridAvailableMean() float32 {
+	if x != nil && x.GridAvailableMean != nil {
+		return *x.GridAvailableMean
+	}
+	return 0
+}
+
+func (x *Trip) GetAmbTemperatureMean() float32 {
+	if x != nil && x.AmbTemperatureMean != nil {
+		return *x.AmbTemperatureMean
+	}
+	return 0
+}
+
+func (x *Trip) GetAmbTemperatureMin() float32 {
+	if x != nil && x.AmbTemperatureMin != nil {
+		return *x.AmbTemperatureMin
+	}
+	return 0
+}
+
+func (x *Trip) GetAmbTemperatureMax() float32 {
+	if x != nil && x.AmbTemperatureMax != nil {
+		return *x.AmbTemperatureMax
+	}
+	return 0
+}
+
+// Telemetry is one sample. Units are as loaded: radians, m/s, W and °C.
+type Telemetry struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	Id                        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	TripId                    int32                  `protobuf:"varint,2,opt,name=trip_id,json=tripId,proto3" json:"trip_id,omitempty"`
+	Time                      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	ElectricPowerDemand       *float32               `protobuf:"fixed32,4,opt,name=electric_power_demand,json=electricPowerDemand,proto3,oneof" json:"electric_power_demand,omitempty"`
+	TemperatureAmbient        *float32               `protobuf:"fixed32,5,opt,name=temperature_ambient,json=temperatureAmbient,proto3,oneof" json:"temperature_ambient,omitempty"`
+	TractionBrakePressure     *float32               `protobuf:"fixed32,6,opt,name=traction_brake_pressure,json=tractionBrakePressure,proto3,oneof" json:"traction_brake_pressure,omitempty"`
+	TractionTractionForce     *float32               `protobuf:"fixed32,7,opt,name=traction_traction_force,json=tractionTractionForce,proto3,oneof" json:"traction_traction_force,omitempty"`
+	GnssAltitude              *float64               `protobuf:"fixed64,8,opt,name=gnss_altitude,json=gnssAltitude,proto3,oneof" json:"gnss_altitude,omitempty"`
+	GnssCourse                *float32               `protobuf:"fixed32,9,opt,name=gnss_course,json=gnssCourse,proto3,oneof" json:"gnss_course,omitempty"`
+	GnssLatitude              *float64               `protobuf:"fixed64,10,opt,name=gnss_latitude,json=gnssLatitude,proto3,oneof" json:"gnss_latitude,omitempty"`
+	GnssLongitude             *float64               `protobuf:"fixed64,11,opt,name=gnss_longitude,json=gnssLongitude,proto3,oneof" json:"gnss_longitude,omitempty"`
+	ItcsBusRouteId            *int32                 `protobuf:"varint,12,opt,name=itcs_bus_route_id,json=itcsBusRouteId,proto3,oneof" json:"itcs_bus_route_id,omitempty"`
+	ItcsNumberOfPassengers    *int32                 `protobuf:"varint,13,opt,name=itcs_number_of_passengers,json=itcsNumberOfPassengers,proto3,oneof" json:"itcs_number_of_passengers,omitempty"`
+	OdometryArticulationAngle *float32               `protobuf:"fixed32,14,opt,name=odometry_articulation_angle,json=odometryArticulationAngle,proto3,oneof" json:"odometry_articulation_angle,omitempty"`
+	OdometrySteeringAngle     *float32               `protobuf:"fixed32,15,opt,name=odometry_steering_angle,json=odometrySteeringAngle,proto3,oneof" json:"odometry_steering_angle,omitempty"`
+	OdometryVehicleSpeed      *float32               `protobuf:"fixed32,16,opt,name=odometry_vehicle_speed,json=odometryVehicleSpeed,proto3,oneof" json:"odometry_vehicle_speed,omitempty"`
+	OdometryWheelSpeedFl      *float32               `protobuf:"fixed32,17,opt,name=odometry_wheel_speed_fl,json=odometryWheelSpeedFl,proto3,oneof" json:"odometry_wheel_speed_fl,omitempty"`
+	OdometryWheelSpeedFr      *float32               `protobuf:"fixed32,18,opt,name=odometry_wheel_speed_fr,json=odometryWheelSpeedFr,proto3,oneof" json:"odometry_wheel_speed_fr,omitempty"`
+	OdometryWheelSpeedMl      *float32               `protobuf:"fixed32,19,opt,name=odometry_wheel_speed_ml,json=odometryWheelSpeedMl,proto3,oneof" json:"odometry_wheel_speed_ml,omitempty"`
+	OdometryWheelSpeedMr      *float32               `protobuf:"fixed32,20,opt,name=odometry_wheel_speed_mr,json=odometryWheelSpeedMr,proto3,oneof" json:"odometry_wheel_speed_mr,omitempty"`
+	OdometryWheelSpeedRl      *float32               `protobuf:"fixed32,21,opt,name=odometry_wheel_speed_rl,json=odometryWheelSpeedRl,proto3,oneof" json:"odometry_wheel_speed_rl,omitempty"`
+	OdometryWheelSpeedRr      *float32               `protobuf:"fixed32,22,opt,name=odometry_wheel_speed_rr,json=odometryWheelSpeedRr,proto3,oneof" json:"odometry_wheel_speed_rr,omitempty"`
+	StatusDoorIsOpen          *bool                  `protobuf:"varint,23,opt,name=status_door_is_open,json=statusDoorIsOpen,proto3,oneof" json:"status_door_is_open,omitempty"`
+	StatusGridIsAvailable     *bool                  `protobuf:"varint,24,opt,name=status_grid_is_available,json=statusGridIsAvailable,proto3,oneof" json:"status_grid_is_available,omitempty"`
+	StatusHaltBrakeIsActive   *bool                  `protobuf:"varint,25,opt,name=status_halt_brake_is_active,json=statusHaltBrakeIsActive,proto3,oneof" json:"status_halt_brake_is_active,omitempty"`
+	StatusParkBrakeIsActive   *bool                  `protobuf:"varint,26,opt,name=status_park_brake_is_active,json=statusParkBrakeIsActive,proto3,oneof" json:"status_park_brake_is_active,omitempty"`
+	StopId                    *int32                 `protobuf:"varint,27,opt,name=stop_id,json=stopId,proto3,oneof" json:"stop_id,omitempty"`
+	RouteDistanceM            *float32               `protobuf:"fixed32,28,opt,name=route_distance_m,json=routeDistanceM,proto3,oneof" json:"route_distance_m,omitempty"`
+	RouteOffsetM              *float32               `protobuf:"fixed32,29,opt,name=route_offset_m,json=routeOffsetM,proto3,oneof" json:"route_offset_m,omitempty"`
+	RoutePositionEstimated    *bool                  `protobuf:"varint,30,opt,name=route_position_estimated,json=routePositionEstimated,proto3,oneof" json:"route_position_estimated,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *Telemetry) Reset() {
+	*x = Telemetry{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Telemetry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Telemetry) ProtoMessage() {}
+
+func (x *Telemetry) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Telemetry.ProtoReflect.Descriptor instead.
+func (*Telemetry) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Telemetry) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Telemetry) GetTripId() int32 {
+	if x != nil {
+		return x.TripId
+	}
+	return 0
+}
+
+func (x *Telemetry) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Telemetry) GetElectricPowerDemand() float32 {
+	if x != nil && x.ElectricPowerDemand != nil {
+		return *x.ElectricPowerDemand
+	}
+	return 0
+}
+
+func (x *Telemetry) GetTemperatureAmbient() float32 {
+	if x != nil && x.TemperatureAmbient != nil {
+		return *x.TemperatureAmbient
+	}
+	return 0
+}
+
+func (x *Telemetry) GetTractionBrakePressure() float32 {
+	if x != nil && x.TractionBrakePressure != nil {
+		return *x.TractionBrakePressure
+	}
+	return 0
+}
+
+func (x *Telemetry) GetTractionTractionForce() float32 {
+	if x != nil && x.TractionTractionForce != nil {
+		return *x.TractionTractionForce
+	}
+	return 0
+}
+
+func (x *Telemetry) GetGnssAltitude() float64 {
+	if x != nil && x.GnssAltitude != nil {
+		return *x.GnssAltitude
+	}
+	return 0
+}
+
+func (x *Telemetry) GetGnssCourse() float32 {
+	if x != nil && x.GnssCourse != nil {
+		return *x.GnssCourse
+	}
+	return 0
+}
+
+func (x *Telemetry) GetGnssLatitude() float64 {
+	if x != nil && x.GnssLatitude != nil {
+		return *x.GnssLatitude
+	}
+	return 0
+}
+
+func (x *Telemetry) GetGnssLongitude() float64 {
+	if x != nil && x.GnssLongitude != nil {
+		return *x.GnssLongitude
+	}
+	return 0
+}
+
+func (x *Telemetry) GetItcsBusRouteId() int32 {
+	if x != nil && x.ItcsBusRouteId != nil {
+		return *x.ItcsBusRouteId
+	}
+	return 0
+}
+
+func (x *Telemetry) GetItcsNumberOfPassengers() int32 {
+	if x != nil && x.ItcsNumberOfPassengers != nil {
+		return *x.ItcsNumberOfPassengers
+	}
+	return 0
+}
+
+func (x *Telemetry) GetOdometryArticulationAngle() float32 {
+	if x != nil && x.OdometryArticulationAngle != nil {
+		return *x.OdometryArticulationAngle
+	}
+	return 0
+}
+
+func (x *Telemetry) GetOdometrySteeringAngle() float32 {
+	if x != nil && x.OdometrySteeringAngle != nil {
+		return *x.OdometrySteeringAngle
+	}
+	return 0
+}
+
+func (x *Telemetry) GetOdometryVehicleSpeed() float32 {
+	if x != nil && x.OdometryVehicleSpeed != nil {
+		return *x.OdometryVehicleSpeed
+	}
+	return 0
+}
+
+func (x *Telemetry) GetOdometryWheelSpeedFl() float32 {
+	if x != nil && x.OdometryWheelSpeedFl != nil {
+		return *x.OdometryWheelSpeedFl
+	}
+	return 0
+}
+
+func (x *Telemetry) GetOdometryWheelSpeedFr() float32 {
+	if x != nil && x.OdometryWheelSpeedFr != nil {
+		return *x.OdometryWheelSpeedFr
+	}
+	return 0
+}
+
+func (x *Telemetry) GetOdometryWheelSpeedMl() float32 {
+	if x != nil && x.OdometryWheelSpeedMl != nil {
+		return *x.OdometryWheelSpeedMl
+	}
+	return 0
+}
+
+func (x *Telemetry) GetOdometryWheelSpeedMr() float32 {
+	if x != nil && x.OdometryWheelSpeedMr != nil {
+		return *x.OdometryWheelSpeedMr
+	}
+	return 0
+}
+
+func (x *Telemetry) GetOdometryWheelSpeedRl() float32 {
+	if x != nil && x.OdometryWheelSpeedRl != nil {
+		return *x.OdometryWheelSpeedRl
+	}
+	return 0
+}
+
+func (x *Telemetry) GetOdometryWheelSpeedRr() float32 {
+	if x != nil && x.OdometryWheelSpeedRr != nil {
+		return *x.OdometryWheelSpeedRr
+	}
+	return 0
+}
+
+func (x *Telemetry) GetStatusDoorIsOpen() bool {
+	if x != nil && x.StatusDoorIsOpen != nil {
+		return *x.StatusDoorIsOpen
+	}
+	return false
+}
+
+func (x *Telemetry) GetStatusGridIsAvailable() bool {
+	if x != nil && x.StatusGridIsAvailable != nil {
+		return *x.StatusGridIsAvailable
+	}
+	return false
+}
+
+func (x *Telemetry) GetStatusHaltBrakeIsActive() bool {
+	if x != nil && x.StatusHaltBrakeIsActive != nil {
+		return *x.StatusHaltBrakeIsActive
+	}
+	return false
+}
+
+func (x *Telemetry) GetStatusParkBrakeIsActive() bool {
+	if x != nil && x.StatusParkBrakeIsActive != nil {
+		return *x.StatusParkBrakeIsActive
+	}
+	return false
+}
+
+func (x *Telemetry) GetStopId() int32 {
+	if x != nil && x.StopId != nil {
+		return *x.StopId
+	}
+	return 0
+}
+
+func (x *Telemetry) GetRouteDistanceM() float32 {
+	if x != nil && x.RouteDistanceM != nil {
+		return *x.RouteDistanceM
+	}
+	return 0
+}
+
+func (x *Telemetry) GetRouteOffsetM() float32 {
+	if x != nil && x.RouteOffsetM != nil {
+		return *x.RouteOffsetM
+	}
+	return 0
+}
+
+func (x *Telemetry) GetRoutePositionEstimated() bool {
+	if x != nil && x.RoutePositionEstimated != nil {
+		return *x.RoutePositionEstimated
+	}
+	return false
+}
+
+type ListBusesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBusesRequest) Reset() {
+	*x = ListBusesRequest{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBusesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBusesRequest) ProtoMessage() {}
+
+func (x *ListBusesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBusesRequest.ProtoReflect.Descriptor instead.
+func (*ListBusesRequest) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{4}
+}
+
+type ListBusesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Buses         []*Bus                 `protobuf:"bytes,1,rep,name=buses,proto3" json:"buses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBusesResponse) Reset() {
+	*x = ListBusesResponse{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBusesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBusesResponse) ProtoMessage() {}
+
+func (x *ListBusesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBusesResponse.ProtoReflect.Descriptor instead.
+func (*ListBusesResponse) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListBusesResponse) GetBuses() []*Bus {
+	if x != nil {
+		return x.Buses
+	}
+	return nil
+}
+
+type ListRoutesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoutesRequest) Reset() {
+	*x = ListRoutesRequest{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoutesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoutesRequest) ProtoMessage() {}
+
+func (x *ListRoutesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoutesRequest.ProtoReflect.Descriptor instead.
+func (*ListRoutesRequest) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{6}
+}
+
+type ListRoutesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Routes        []*BusRoute            `protobuf:"bytes,1,rep,name=routes,proto3" json:"routes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoutesResponse) Reset() {
+	*x = ListRoutesResponse{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoutesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoutesResponse) ProtoMessage() {}
+
+func (x *ListRoutesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoutesResponse.ProtoReflect.Descriptor instead.
+func (*ListRoutesResponse) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListRoutesResponse) GetRoutes() []*BusRoute {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+type ListAllTripsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAllTripsRequest) Reset() {
+	*x = ListAllTripsRequest{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAllTripsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAllTripsRequest) ProtoMessage() {}
+
+func (x *ListAllTripsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAllTripsRequest.ProtoReflect.Descriptor instead.
+func (*ListAllTripsRequest) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{8}
+}
+
+type ListAllTripsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trips         []*Trip                `protobuf:"bytes,1,rep,name=trips,proto3" json:"trips,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAllTripsResponse) Reset() {
+	*x = ListAllTripsResponse{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAllTripsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAllTripsResponse) ProtoMessage() {}
+
+func (x *ListAllTripsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAllTripsResponse.ProtoReflect.Descriptor instead.
+func (*ListAllTripsResponse) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListAllTripsResponse) GetTrips() []*Trip {
+	if x != nil {
+		return x.Trips
+	}
+	return nil
+}
+
+type GetTripByNameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTripByNameRequest) Reset() {
+	*x = GetTripByNameRequest{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTripByNameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTripByNameRequest) ProtoMessage() {}
+
+func (x *GetTripByNameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTripByNameRequest.ProtoReflect.Descriptor instead.
+func (*GetTripByNameRequest) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetTripByNameRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetTripByNameResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trip          *Trip                  `protobuf:"bytes,1,opt,name=trip,proto3" json:"trip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTripByNameResponse) Reset() {
+	*x = GetTripByNameResponse{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTripByNameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTripByNameResponse) ProtoMessage() {}
+
+func (x *GetTripByNameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTripByNameResponse.ProtoReflect.Descriptor instead.
+func (*GetTripByNameResponse) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetTripByNameResponse) GetTrip() *Trip {
+	if x != nil {
+		return x.Trip
+	}
+	return nil
+}
+
+type GetTripsByBusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BusId         int32                  `protobuf:"varint,1,opt,name=bus_id,json=busId,proto3" json:"bus_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTripsByBusRequest) Reset() {
+	*x = GetTripsByBusRequest{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTripsByBusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTripsByBusRequest) ProtoMessage() {}
+
+func (x *GetTripsByBusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTripsByBusRequest.ProtoReflect.Descriptor instead.
+func (*GetTripsByBusRequest) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetTripsByBusRequest) GetBusId() int32 {
+	if x != nil {
+		return x.BusId
+	}
+	return 0
+}
+
+type GetTripsByBusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trips         []*Trip                `protobuf:"bytes,1,rep,name=trips,proto3" json:"trips,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTripsByBusResponse) Reset() {
+	*x = GetTripsByBusResponse{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTripsByBusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTripsByBusResponse) ProtoMessage() {}
+
+func (x *GetTripsByBusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTripsByBusResponse.ProtoReflect.Descriptor instead.
+func (*GetTripsByBusResponse) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetTripsByBusResponse) GetTrips() []*Trip {
+	if x != nil {
+		return x.Trips
+	}
+	return nil
+}
+
+type GetTripsByRouteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RouteId       int32                  `protobuf:"varint,1,opt,name=route_id,json=routeId,proto3" json:"route_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTripsByRouteRequest) Reset() {
+	*x = GetTripsByRouteRequest{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTripsByRouteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTripsByRouteRequest) ProtoMessage() {}
+
+func (x *GetTripsByRouteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTripsByRouteRequest.ProtoReflect.Descriptor instead.
+func (*GetTripsByRouteRequest) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetTripsByRouteRequest) GetRouteId() int32 {
+	if x != nil {
+		return x.RouteId
+	}
+	return 0
+}
+
+type GetTripsByRouteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trips         []*Trip                `protobuf:"bytes,1,rep,name=trips,proto3" json:"trips,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTripsByRouteResponse) Reset() {
+	*x = GetTripsByRouteResponse{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTripsByRouteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTripsByRouteResponse) ProtoMessage() {}
+
+func (x *GetTripsByRouteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTripsByRouteResponse.ProtoReflect.Descriptor instead.
+func (*GetTripsByRouteResponse) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetTripsByRouteResponse) GetTrips() []*Trip {
+	if x != nil {
+		return x.Trips
+	}
+	return nil
+}
+
+// GetTripsByTimeRangeRequest selects the trips lying within a time range.
+// An unset bound is open.
+type GetTripsByTimeRangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartTimeFrom *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time_from,json=startTimeFrom,proto3" json:"start_time_from,omitempty"`
+	EndTimeTo     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time_to,json=endTimeTo,proto3" json:"end_time_to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTripsByTimeRangeRequest) Reset() {
+	*x = GetTripsByTimeRangeRequest{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTripsByTimeRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTripsByTimeRangeRequest) ProtoMessage() {}
+
+func (x *GetTripsByTimeRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTripsByTimeRangeRequest.ProtoReflect.Descriptor instead.
+func (*GetTripsByTimeRangeRequest) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetTripsByTimeRangeRequest) GetStartTimeFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTimeFrom
+	}
+	return nil
+}
+
+func (x *GetTripsByTimeRangeRequest) GetEndTimeTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTimeTo
+	}
+	return nil
+}
+
+type GetTripsByTimeRangeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trips         []*Trip                `protobuf:"bytes,1,rep,name=trips,proto3" json:"trips,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTripsByTimeRangeResponse) Reset() {
+	*x = GetTripsByTimeRangeResponse{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTripsByTimeRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTripsByTimeRangeResponse) ProtoMessage() {}
+
+func (x *GetTripsByTimeRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTripsByTimeRangeResponse.ProtoReflect.Descriptor instead.
+func (*GetTripsByTimeRangeResponse) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetTripsByTimeRangeResponse) GetTrips() []*Trip {
+	if x != nil {
+		return x.Trips
+	}
+	return nil
+}
+
+type GetTelemetryByTripRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TripId        int32                  `protobuf:"varint,1,opt,name=trip_id,json=tripId,proto3" json:"trip_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTelemetryByTripRequest) Reset() {
+	*x = GetTelemetryByTripRequest{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTelemetryByTripRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTelemetryByTripRequest) ProtoMessage() {}
+
+func (x *GetTelemetryByTripRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTelemetryByTripRequest.ProtoReflect.Descriptor instead.
+func (*GetTelemetryByTripRequest) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetTelemetryByTripRequest) GetTripId() int32 {
+	if x != nil {
+		return x.TripId
+	}
+	return 0
+}
+
+// GetTelemetryByTripResponse is one chunk of the stream
+type GetTelemetryByTripResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Telemetry     []*Telemetry           `protobuf:"bytes,1,rep,name=telemetry,proto3" json:"telemetry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTelemetryByTripResponse) Reset() {
+	*x = GetTelemetryByTripResponse{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTelemetryByTripResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTelemetryByTripResponse) ProtoMessage() {}
+
+func (x *GetTelemetryByTripResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTelemetryByTripResponse.ProtoReflect.Descriptor instead.
+func (*GetTelemetryByTripResponse) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetTelemetryByTripResponse) GetTelemetry() []*Telemetry {
+	if x != nil {
+		return x.Telemetry
+	}
+	return nil
+}
+
+type ListTelemetryInRangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TripId        int32                  `protobuf:"varint,1,opt,name=trip_id,json=tripId,proto3" json:"trip_id,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTelemetryInRangeRequest) Reset() {
+	*x = ListTelemetryInRangeRequest{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTelemetryInRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTelemetryInRangeRequest) ProtoMessage() {}
+
+func (x *ListTelemetryInRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTelemetryInRangeRequest.ProtoReflect.Descriptor instead.
+func (*ListTelemetryInRangeRequest) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListTelemetryInRangeRequest) GetTripId() int32 {
+	if x != nil {
+		return x.TripId
+	}
+	return 0
+}
+
+func (x *ListTelemetryInRangeRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ListTelemetryInRangeRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+// ListTelemetryInRangeResponse is one chunk of the stream
+type ListTelemetryInRangeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Telemetry     []*Telemetry           `protobuf:"bytes,1,rep,name=telemetry,proto3" json:"telemetry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTelemetryInRangeResponse) Reset() {
+	*x = ListTelemetryInRangeResponse{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTelemetryInRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTelemetryInRangeResponse) ProtoMessage() {}
+
+func (x *ListTelemetryInRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTelemetryInRangeResponse.ProtoReflect.Descriptor instead.
+func (*ListTelemetryInRangeResponse) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListTelemetryInRangeResponse) GetTelemetry() []*Telemetry {
+	if x != nil {
+		return x.Telemetry
+	}
+	return nil
+}
+
+// ListTelemetryPageRequest selects the telemetry in [start_time, end_time)
+// after the (after_trip_id, after_time) cursor, which is unset for the first
+// page. Rows are ordered by trip ID and time.
+type ListTelemetryPageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	AfterTripId   int32                  `protobuf:"varint,3,opt,name=after_trip_id,json=afterTripId,proto3" json:"after_trip_id,omitempty"`
+	AfterTime     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=after_time,json=afterTime,proto3" json:"after_time,omitempty"`
+	BusId         *int32                 `protobuf:"varint,5,opt,name=bus_id,json=busId,proto3,oneof" json:"bus_id,omitempty"`
+	RouteId       *int32                 `protobuf:"varint,6,opt,name=route_id,json=routeId,proto3,oneof" json:"route_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,7,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTelemetryPageRequest) Reset() {
+	*x = ListTelemetryPageRequest{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTelemetryPageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTelemetryPageRequest) ProtoMessage() {}
+
+func (x *ListTelemetryPageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTelemetryPageRequest.ProtoReflect.Descriptor instead.
+func (*ListTelemetryPageRequest) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListTelemetryPageRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ListTelemetryPageRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *ListTelemetryPageRequest) GetAfterTripId() int32 {
+	if x != nil {
+		return x.AfterTripId
+	}
+	return 0
+}
+
+func (x *ListTelemetryPageRequest) GetAfterTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AfterTime
+	}
+	return nil
+}
+
+func (x *ListTelemetryPageRequest) GetBusId() int32 {
+	if x != nil && x.BusId != nil {
+		return *x.BusId
+	}
+	return 0
+}
+
+func (x *ListTelemetryPageRequest) GetRouteId() int32 {
+	if x != nil && x.RouteId != nil {
+		return *x.RouteId
+	}
+	return 0
+}
+
+func (x *ListTelemetryPageRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListTelemetryPageResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Telemetry []*Telemetry           `protobuf:"bytes,1,rep,name=telemetry,proto3" json:"telemetry,omitempty"`
+	// next_page is the cursor of the next page, unset on the last page
+	NextPage      *ListTelemetryPageRequest `protobuf:"bytes,2,opt,name=next_page,json=nextPage,proto3" json:"next_page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTelemetryPageResponse) Reset() {
+	*x = ListTelemetryPageResponse{}
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTelemetryPageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTelemetryPageResponse) ProtoMessage() {}
+
+func (x *ListTelemetryPageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ztbus_v1_ztbus_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTelemetryPageResponse.ProtoReflect.Descriptor instead.
+func (*ListTelemetryPageResponse) Descriptor() ([]byte, []int) {
+	return file_ztbus_v1_ztbus_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListTelemetryPageResponse) GetTelemetry() []*Telemetry {
+	if x != nil {
+		return x.Telemetry
+	}
+	return nil
+}
+
+func (x *ListTelemetryPageResponse) GetNextPage() *ListTelemetryPageRequest {
+	if x != nil {
+		return x.NextPage
+	}
+	return nil
+}
+
+var File_ztbus_v1_ztbus_proto protoreflect.FileDescriptor
+
+const file_ztbus_v1_ztbus_proto_rawDesc = "" +
+	"\n" +
+	"\x14ztbus/v1/ztbus.proto\x12\bztbus.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"H\n" +
+	"\x03Bus\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\"\n" +
+	"\n" +
+	"bus_number\x18\x02 \x01(\tH\x00R\tbusNumber\x88\x01\x01B\r\n" +
+	"\v_bus_number\"M\n" +
+	"\bBusRoute\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\"\n" +
+	"\n" +
+	"route_code\x18\x02 \x01(\tH\x00R\trouteCode\x88\x01\x01B\r\n" +
+	"\v_route_code\"\xb1\a\n" +
+	"\x04Trip\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\x06bus_id\x18\x03 \x01(\x05H\x00R\x05busId\x88\x01\x01\x12\x1e\n" +
+	"\broute_id\x18\x04 \x01(\x05H\x01R\arouteId\x88\x01\x01\x129\n" +
+	"\n" +
+	"start_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x121\n" +
+	"\x12driven_distance_km\x18\a \x01(\x02H\x02R\x10drivenDistanceKm\x88\x01\x01\x129\n" +
+	"\x16energy_consumption_kwh\x18\b \x01(\x05H\x03R\x14energyConsumptionKwh\x88\x01\x01\x125\n" +
+	"\x14itcs_passengers_mean\x18\t \x01(\x02H\x04R\x12itcsPassengersMean\x88\x01\x01\x123\n" +
+	"\x13itcs_passengers_min\x18\n" +
+	" \x01(\x05H\x05R\x11itcsPassengersMin\x88\x01\x01\x123\n" +
+	"\x13itcs_passengers_max\x18\v \x01(\x05H\x06R\x11itcsPassengersMax\x88\x01\x01\x123\n" +
+	"\x13grid_available_mean\x18\f \x01(\x02H\aR\x11gridAvailableMean\x88\x01\x01\x125\n" +
+	"\x14amb_temperature_mean\x18\r \x01(\x02H\bR\x12ambTemperatureMean\x88\x01\x01\x123\n" +
+	"\x13amb_temperature_min\x18\x0e \x01(\x02H\tR\x11ambTemperatureMin\x88\x01\x01\x123\n" +
+	"\x13amb_temperature_max\x18\x0f \x01(\x02H\n" +
+	"R\x11ambTemperatureMax\x88\x01\x01B\t\n" +
+	"\a_bus_idB\v\n" +
+	"\t_route_idB\x15\n" +
+	"\x13_driven_distance_kmB\x19\n" +
+	"\x17_energy_consumption_kwhB\x17\n" +
+	"\x15_itcs_passengers_meanB\x16\n" +
+	"\x14_itcs_passengers_minB\x16\n" +
+	"\x14_itcs_passengers_maxB\x16\n" +
+	"\x14_grid_available_meanB\x17\n" +
+	"\x15_amb_temperature_meanB\x16\n" +
+	"\x14_amb_temperature_minB\x16\n" +
+	"\x14_amb_temperature_max\"\xe1\x11\n" +
+	"\tTelemetry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\atrip_id\x18\x02 \x01(\x05R\x06tripId\x12.\n" +
+	"\x04time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x127\n" +
+	"\x15electric_power_demand\x18\x04 \x01(\x02H\x00R\x13electricPowerDemand\x88\x01\x01\x124\n" +
+	"\x13temperature_ambient\x18\x05 \x01(\x02H\x01R\x12temperatureAmbient\x88\x01\x01\x12;\n" +
+	"\x17traction_brake_pressure\x18\x06 \x01(\x02H\x02R\x15tractionBrakePressure\x88\x01\x01\x12;\n" +
+	"\x17traction_traction_force\x18\a \x01(\x02H\x03R\x15tractionTractionForce\x88\x01\x01\x12(\n" +
+	"\rgnss_altitude\x18\b \x01(\x01H\x04R\fgnssAltitude\x88\x01\x01\x12$\n" +
+	"\vgnss_course\x18\t \x01(\x02H\x05R\n" +
+	"gnssCourse\x88\x01\x01\x12(\n" +
+	"\rgnss_latitude\x18\n" +
+	" \x01(\x01H\x06R\fgnssLatitude\x88\x01\x01\x12*\n" +
+	"\x0egnss_longitude\x18\v \x01(\x01H\aR\rgnssLongitude\x88\x01\x01\x12.\n" +
+	"\x11itcs_bus_route_id\x18\f \x01(\x05H\bR\x0eitcsBusRouteId\x88\x01\x01\x12>\n" +
+	"\x19itcs_number_of_passengers\x18\r \x01(\x05H\tR\x16itcsNumberOfPassengers\x88\x01\x01\x12C\n" +
+	"\x1bodometry_articulation_angle\x18\x0e \x01(\x02H\n" +
+	"R\x19odometryArticulationAngle\x88\x01\x01\x12;\n" +
+	"\x17odometry_steering_angle\x18\x0f \x01(\x02H\vR\x15odometrySteeringAngle\x88\x01\x01\x129\n" +
+	"\x16odometry_vehicle_speed\x18\x10 \x01(\x02H\fR\x14odometryVehicleSpeed\x88\x01\x01\x12:\n" +
+	"\x17odometry_wheel_speed_fl\x18\x11 \x01(\x02H\rR\x14odometryWheelSpeedFl\x88\x01\x01\x12:\n" +
+	"\x17odometry_wheel_speed_fr\x18\x12 \x01(\x02H\x0eR\x14odometryWheelSpeedFr\x88\x01\x01\x12:\n" +
+	"\x17odometry_wheel_speed_ml\x18\x13 \x01(\x02H\x0fR\x14odometryWheelSpeedMl\x88\x01\x01\x12:\n" +
+	"\x17odometry_wheel_speed_mr\x18\x14 \x01(\x02H\x10R\x14odometryWheelSpeedMr\x88\x01\x01\x12:\n" +
+	"\x17odometry_wheel_speed_rl\x18\x15 \x01(\x02H\x11R\x14odometryWheelSpeedRl\x88\x01\x01\x12:\n" +
+	"\x17odometry_wheel_speed_rr\x18\x16 \x01(\x02H\x12R\x14odometryWheelSpeedRr\x88\x01\x01\x122\n" +
+	"\x13status_door_is_open\x18\x17 \x01(\bH\x13R\x10statusDoorIsOpen\x88\x01\x01\x12<\n" +
+	"\x18status_grid_is_available\x18\x18 \x01(\bH\x14R\x15statusGridIsAvailable\x88\x01\x01\x12A\n" +
+	"\x1bstatus_halt_brake_is_active\x18\x19 \x01(\bH\x15R\x17statusHaltBrakeIsActive\x88\x01\x01\x12A\n" +
+	"\x1bstatus_park_brake_is_active\x18\x1a \x01(\bH\x16R\x17statusParkBrakeIsActive\x88\x01\x01\x12\x1c\n" +
+	"\astop_id\x18\x1b \x01(\x05H\x17R\x06stopId\x88\x01\x01\x12-\n" +
+	"\x10route_distance_m\x18\x1c \x01(\x02H\x18R\x0erouteDistanceM\x88\x01\x01\x12)\n" +
+	"\x0eroute_offset_m\x18\x1d \x01(\x02H\x19R\frouteOffsetM\x88\x01\x01\x12=\n" +
+	"\x18route_position_estimated\x18\x1e \x01(\bH\x1aR\x16routePositionEstimated\x88\x01\x01B\x18\n" +
+	"\x16_electric_power_demandB\x16\n" +
+	"\x14_temperature_ambientB\x1a\n" +
+	"\x18_traction_brake_pressureB\x1a\n" +
+	"\x18_traction_traction_forceB\x10\n" +
+	"\x0e_gnss_altitudeB\x0e\n" +
+	"\f_gnss_courseB\x10\n" +
+	"\x0e_gnss_latitudeB\x11\n" +
+	"\x0f_gnss_longitudeB\x14\n" +
+	"\x12_itcs_bus_route_idB\x1c\n" +
+	"\x1a_itcs_number_of_passengersB\x1e\n" +
+	"\x1c_odometry_articulation_angleB\x1a\n" +
+	"\x18_odometry_steering_angleB\x19\n" +
+	"\x17_odometry_vehicle_speedB\x1a\n" +
+	"\x18_odometry_wheel_speed_flB\x1a\n" +
+	"\x18_odometry_wheel_speed_frB\x1a\n" +
+	"\x18_odometry_wheel_speed_mlB\x1a\n" +
+	"\x18_odometry_wheel_speed_mrB\x1a\n" +
+	"\x18_odometry_wheel_speed_rlB\x1a\n" +
+	"\x18_odometry_wheel_speed_rrB\x16\n" +
+	"\x14_status_door_is_openB\x1b\n" +
+	"\x19_status_grid_is_availableB\x1e\n" +
+	"\x1c_status_halt_brake_is_activeB\x1e\n" +
+	"\x1c_status_park_brake_is_activeB\n" +
+	"\n" +
+	"\b_stop_idB\x13\n" +
+	"\x11_route_distance_mB\x11\n" +
+	"\x0f_route_offset_mB\x1b\n" +
+	"\x19_route_position_estimated\"\x12\n" +
+	"\x10ListBusesRequest\"8\n" +
+	"\x11ListBusesResponse\x12#\n" +
+	"\x05buses\x18\x01 \x03(\v2\r.ztbus.v1.BusR\x05buses\"\x13\n" +
+	"\x11ListRoutesRequest\"@\n" +
+	"\x12ListRoutesResponse\x12*\n" +
+	"\x06routes\x18\x01 \x03(\v2\x12.ztbus.v1.BusRouteR\x06routes\"\x15\n" +
+	"\x13ListAllTripsRequest\"<\n" +
+	"\x14ListAllTripsResponse\x12$\n" +
+	"\x05trips\x18\x01 \x03(\v2\x0e.ztbus.v1.TripR\x05trips\"*\n" +
+	"\x14GetTripByNameRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\";\n" +
+	"\x15GetTripByNameResponse\x12\"\n" +
+	"\x04trip\x18\x01 \x01(\v2\x0e.ztbus.v1.TripR\x04trip\"-\n" +
+	"\x14GetTripsByBusRequest\x12\x15\n" +
+	"\x06bus_id\x18\x01 \x01(\x05R\x05busId\"=\n" +
+	"\x15GetTripsByBusResponse\x12$\n" +
+	"\x05trips\x18\x01 \x03(\v2\x0e.ztbus.v1.TripR\x05trips\"3\n" +
+	"\x16GetTripsByRouteRequest\x12\x19\n" +
+	"\broute_id\x18\x01 \x01(\x05R\arouteId\"?\n" +
+	"\x17GetTripsByRouteResponse\x12$\n" +
+	"\x05trips\x18\x01 \x03(\v2\x0e.ztbus.v1.TripR\x05trips\"\x9c\x01\n" +
+	"\x1aGetTripsByTimeRangeRequest\x12B\n" +
+	"\x0fstart_time_from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\rstartTimeFrom\x12:\n" +
+	"\vend_time_to\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tendTimeTo\"C\n" +
+	"\x1bGetTripsByTimeRangeResponse\x12$\n" +
+	"\x05trips\x18\x01 \x03(\v2\x0e.ztbus.v1.TripR\x05trips\"4\n" +
+	"\x19GetTelemetryByTripRequest\x12\x17\n" +
+	"\atrip_id\x18\x01 \x01(\x05R\x06tripId\"O\n" +
+	"\x1aGetTelemetryByTripResponse\x121\n" +
+	"\ttelemetry\x18\x01 \x03(\v2\x13.ztbus.v1.TelemetryR\ttelemetry\"\xa8\x01\n" +
+	"\x1bListTelemetryInRangeRequest\x12\x17\n" +
+	"\atrip_id\x18\x01 \x01(\x05R\x06tripId\x129\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\"Q\n" +
+	"\x1cListTelemetryInRangeResponse\x121\n" +
+	"\ttelemetry\x18\x01 \x03(\v2\x13.ztbus.v1.TelemetryR\ttelemetry\"\xdc\x02\n" +
+	"\x18ListTelemetryPageRequest\x129\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\"\n" +
+	"\rafter_trip_id\x18\x03 \x01(\x05R\vafterTripId\x129\n" +
+	"\n" +
+	"after_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tafterTime\x12\x1a\n" +
+	"\x06bus_id\x18\x05 \x01(\x05H\x00R\x05busId\x88\x01\x01\x12\x1e\n" +
+	"\broute_id\x18\x06 \x01(\x05H\x01R\arouteId\x88\x01\x01\x12\x1b\n" +
+	"\tpage_size\x18\a \x01(\x05R\bpageSizeB\t\n" +
+	"\a_bus_idB\v\n" +
+	"\t_route_id\"\x8f\x01\n" +
+	"\x19ListTelemetryPageResponse\x121\n" +
+	"\ttelemetry\x18\x01 \x03(\v2\x13.ztbus.v1.TelemetryR\ttelemetry\x12?\n" +
+	"\tnext_page\x18\x02 \x01(\v2\".ztbus.v1.ListTelemetryPageRequestR\bnextPage2\xf6\x06\n" +
+	"\fZTBusService\x12D\n" +
+	"\tListBuses\x12\x1a.ztbus.v1.ListBusesRequest\x1a\x1b.ztbus.v1.ListBusesResponse\x12G\n" +
+	"\n" +
+	"ListRoutes\x12\x1b.ztbus.v1.ListRoutesRequest\x1a\x1c.ztbus.v1.ListRoutesResponse\x12M\n" +
+	"\fListAllTrips\x12\x1d.ztbus.v1.ListAllTripsRequest\x1a\x1e.ztbus.v1.ListAllTripsResponse\x12P\n" +
+	"\rGetTripByName\x12\x1e.ztbus.v1.GetTripByNameRequest\x1a\x1f.ztbus.v1.GetTripByNameResponse\x12P\n" +
+	"\rGetTripsByBus\x12\x1e.ztbus.v1.GetTripsByBusRequest\x1a\x1f.ztbus.v1.GetTripsByBusResponse\x12V\n" +
+	"\x0fGetTripsByRoute\x12 .ztbus.v1.GetTripsByRouteRequest\x1a!.ztbus.v1.GetTripsByRouteResponse\x12b\n" +
+	"\x13GetTripsByTimeRange\x12$.ztbus.v1.GetTripsByTimeRangeRequest\x1a%.ztbus.v1.GetTripsByTimeRangeResponse\x12a\n" +
+	"\x12GetTelemetryByTrip\x12#.ztbus.v1.GetTelemetryByTripRequest\x1a$.ztbus.v1.GetTelemetryByTripResponse0\x01\x12g\n" +
+	"\x14ListTelemetryInRange\x12%.ztbus.v1.ListTelemetryInRangeRequest\x1a&.ztbus.v1.ListTelemetryInRangeResponse0\x01\x12\\\n" +
+	"\x11ListTelemetryPage\x12\".ztbus.v1.ListTelemetryPageRequest\x1a#.ztbus.v1.ListTelemetryPageResponseB<Z:github.com/Predixus/orca-ztbus-prep/proto/ztbus/v1;ztbusv1b\x06proto3"
+
+var (
+	file_ztbus_v1_ztbus_proto_rawDescOnce sync.Once
+	file_ztbus_v1_ztbus_proto_rawDescData []byte
+)
+
+func file_ztbus_v1_ztbus_proto_rawDescGZIP() []byte {
+	file_ztbus_v1_ztbus_proto_rawDescOnce.Do(func() {
+		file_ztbus_v1_ztbus_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ztbus_v1_ztbus_proto_rawDesc), len(file_ztbus_v1_ztbus_proto_rawDesc)))
+	})
+	return file_ztbus_v1_ztbus_proto_rawDescData
+}
+
+var file_ztbus_v1_ztbus_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_ztbus_v1_ztbus_proto_goTypes = []any{
+	(*Bus)(nil),                          // 0: ztbus.v1.Bus
+	(*BusRoute)(nil),                     // 1: ztbus.v1.BusRoute
+	(*Trip)(nil),                         // 2: ztbus.v1.Trip
+	(*Telemetry)(nil),                    // 3: ztbus.v1.Telemetry
+	(*ListBusesRequest)(nil),             // 4: ztbus.v1.ListBusesRequest
+	(*ListBusesResponse)(nil),            // 5: ztbus.v1.ListBusesResponse
+	(*ListRoutesRequest)(nil),            // 6: ztbus.v1.ListRoutesRequest
+	(*ListRoutesResponse)(nil),           // 7: ztbus.v1.ListRoutesResponse
+	(*ListAllTripsRequest)(nil),          // 8: ztbus.v1.ListAllTripsRequest
+	(*ListAllTripsResponse)(nil),         // 9: ztbus.v1.ListAllTripsResponse
+	(*GetTripByNameRequest)(nil),         // 10: ztbus.v1.GetTripByNameRequest
+	(*GetTripByNameResponse)(nil),        // 11: ztbus.v1.GetTripByNameResponse
+	(*GetTripsByBusRequest)(nil),         // 12: ztbus.v1.GetTripsByBusRequest
+	(*GetTripsByBusResponse)(nil),        // 13: ztbus.v1.GetTripsByBusResponse
+	(*GetTripsByRouteRequest)(nil),       // 14: ztbus.v1.GetTripsByRouteRequest
+	(*GetTripsByRouteResponse)(nil),      // 15: ztbus.v1.GetTripsByRouteResponse
+	(*GetTripsByTimeRangeRequest)(nil),   // 16: ztbus.v1.GetTripsByTimeRangeRequest
+	(*GetTripsByTimeRangeResponse)(nil),  // 17: ztbus.v1.GetTripsByTimeRangeResponse
+	(*GetTelemetryByTripRequest)(nil),    // 18: ztbus.v1.GetTelemetryByTripRequest
+	(*GetTelemetryByTripResponse)(nil),   // 19: ztbus.v1.GetTelemetryByTripResponse
+	(*ListTelemetryInRangeRequest)(nil),  // 20: ztbus.v1.ListTelemetryInRangeRequest
+	(*ListTelemetryInRangeResponse)(nil), // 21: ztbus.v1.ListTelemetryInRangeResponse
+	(*ListTelemetryPageRequest)(nil),     // 22: ztbus.v1.ListTelemetryPageRequest
+	(*ListTelemetryPageResponse)(nil),    // 23: ztbus.v1.ListTelemetryPageResponse
+	(*timestamppb.Timestamp)(nil),        // 24: google.protobuf.Timestamp
+}
+var file_ztbus_v1_ztbus_proto_depIdxs = []int32{
+	24, // 0: ztbus.v1.Trip.start_time:type_name -> google.protobuf.Timestamp
+	24, // 1: ztbus.v1.Trip.end_time:type_name -> google.protobuf.Timestamp
+	24, // 2: ztbus.v1.Telemetry.time:type_name -> google.protobuf.Timestamp
+	0,  // 3: ztbus.v1.ListBusesResponse.buses:type_name -> ztbus.v1.Bus
+	1,  // 4: ztbus.v1.ListRoutesResponse.routes:type_name -> ztbus.v1.BusRoute
+	2,  // 5: ztbus.v1.ListAllTripsResponse.trips:type_name -> ztbus.v1.Trip
+	2,  // 6: ztbus.v1.GetTripByNameResponse.trip:type_name -> ztbus.v1.Trip
+	2,  // 7: ztbus.v1.GetTripsByBusResponse.trips:type_name -> ztbus.v1.Trip
+	2,  // 8: ztbus.v1.GetTripsByRouteResponse.trips:type_name -> ztbus.v1.Trip
+	24, // 9: ztbus.v1.GetTripsByTimeRangeRequest.start_time_from:type_name -> google.protobuf.Timestamp
+	24, // 10: ztbus.v1.GetTripsByTimeRangeRequest.end_time_to:type_name -> google.protobuf.Timestamp
+	2,  // 11: ztbus.v1.GetTripsByTimeRangeResponse.trips:type_name -> ztbus.v1.Trip
+	3,  // 12: ztbus.v1.GetTelemetryByTripResponse.telemetry:type_name -> ztbus.v1.Telemetry
+	24, // 13: ztbus.v1.ListTelemetryInRangeRequest.start_time:type_name -> google.protobuf.Timestamp
+	24, // 14: ztbus.v1.ListTelemetryInRangeRequest.end_time:type_name -> google.protobuf.Timestamp
+	3,  // 15: ztbus.v1.ListTelemetryInRangeResponse.telemetry:type_name -> ztbus.v1.Telemetry
+	24, // 16: ztbus.v1.ListTelemetryPageRequest.start_time:type_name -> google.protobuf.Timestamp
+	24, // 17: ztbus.v1.ListTelemetryPageRequest.end_time:type_name -> google.protobuf.Timestamp
+	24, // 18: ztbus.v1.ListTelemetryPageRequest.after_time:type_name -> google.protobuf.Timestamp
+	3,  // 19: ztbus.v1.ListTelemetryPageResponse.telemetry:type_name -> ztbus.v1.Telemetry
+	22, // 20: ztbus.v1.ListTelemetryPageResponse.next_page:type_name -> ztbus.v1.ListTelemetryPageRequest
+	4,  // 21: ztbus.v1.ZTBusService.ListBuses:input_type -> ztbus.v1.ListBusesRequest
+	6,  // 22: ztbus.v1.ZTBusService.ListRoutes:input_type -> ztbus.v1.ListRoutesRequest
+	8,  // 23: ztbus.v1.ZTBusService.ListAllTrips:input_type -> ztbus.v1.ListAllTripsRequest
+	10, // 24: ztbus.v1.ZTBusService.GetTripByName:input_type -> ztbus.v1.GetTripByNameRequest
+	12, // 25: ztbus.v1.ZTBusService.GetTripsByBus:input_type -> ztbus.v1.GetTripsByBusRequest
+	14, // 26: ztbus.v1.ZTBusService.GetTripsByRoute:input_type -> ztbus.v1.GetTripsByRouteRequest
+	16, // 27: ztbus.v1.ZTBusService.GetTripsByTimeRange:input_type -> ztbus.v1.GetTripsByTimeRangeRequest
+	18, // 28: ztbus.v1.ZTBusService.GetTelemetryByTrip:input_type -> ztbus.v1.GetTelemetryByTripRequest
+	20, // 29: ztbus.v1.ZTBusService.ListTelemetryInRange:input_type -> ztbus.v1.ListTelemetryInRangeRequest
+	22, // 30: ztbus.v1.ZTBusService.ListTelemetryPage:input_type -> ztbus.v1.ListTelemetryPageRequest
+	5,  // 31: ztbus.v1.ZTBusService.ListBuses:output_type -> ztbus.v1.ListBusesResponse
+	7,  // 32: ztbus.v1.ZTBusService.ListRoutes:output_type -> ztbus.v1.ListRoutesResponse
+	9,  // 33: ztbus.v1.ZTBusService.ListAllTrips:output_type -> ztbus.v1.ListAllTripsResponse
+	11, // 34: ztbus.v1.ZTBusService.GetTripByName:output_type -> ztbus.v1.GetTripByNameResponse
+	13, // 35: ztbus.v1.ZTBusService.GetTripsByBus:output_type -> ztbus.v1.GetTripsByBusResponse
+	15, // 36: ztbus.v1.ZTBusService.GetTripsByRoute:output_type -> ztbus.v1.GetTripsByRouteResponse
+	17, // 37: ztbus.v1.ZTBusService.GetTripsByTimeRange:output_type -> ztbus.v1.GetTripsByTimeRangeResponse
+	19, // 38: ztbus.v1.ZTBusService.GetTelemetryByTrip:output_type -> ztbus.v1.GetTelemetryByTripResponse
+	21, // 39: ztbus.v1.ZTBusService.ListTelemetryInRange:output_type -> ztbus.v1.ListTelemetryInRangeResponse
+	23, // 40: ztbus.v1.ZTBusService.ListTelemetryPage:output_type -> ztbus.v1.ListTelemetryPageResponse
+	31, // [31:41] is the sub-list for method output_type
+	21, // [21:31] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
+}
+
+func init() { file_ztbus_v1_ztbus_proto_init() }
+func file_ztbus_v1_ztbus_proto_init() {
+	if File_ztbus_v1_ztbus_proto != nil {
+		return
+	}
+	file_ztbus_v1_ztbus_proto_msgTypes[0].OneofWrappers = []any{}
+	file_ztbus_v1_ztbus_proto_msgTypes[1].OneofWrappers = []any{}
+	file_ztbus_v1_ztbus_proto_msgTypes[2].OneofWrappers = []any{}
+	file_ztbus_v1_ztbus_proto_msgTypes[3].OneofWrappers = []any{}
+	file_ztbus_v1_ztbus_proto_msgTypes[22].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ztbus_v1_ztbus_proto_rawDesc), len(file_ztbus_v1_ztbus_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   24,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ztbus_v1_ztbus_proto_goTypes,
+		DependencyIndexes: file_ztbus_v1_ztbus_proto_depIdxs,
+		MessageInfos:      file_ztbus_v1_ztbus_proto_msgTypes,
+	}.Build()
+	File_ztbus_v1_ztbus_proto = out.File
+	file_ztbus_v1_ztbus_proto_goTypes = nil
+	file_ztbus_v1_ztbus_proto_depIdxs = nil
+}
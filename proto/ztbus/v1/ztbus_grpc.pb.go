@@ -0,0 +1,486 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: ztbus/v1/ztbus.proto
+
+package ztbusv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ZTBusService_ListBuses_FullMethodName            = "/ztbus.v1.ZTBusService/ListBuses"
+	ZTBusService_ListRoutes_FullMethodName           = "/ztbus.v1.ZTBusService/ListRoutes"
+	ZTBusService_ListAllTrips_FullMethodName         = "/ztbus.v1.ZTBusService/ListAllTrips"
+	ZTBusService_GetTripByName_FullMethodName        = "/ztbus.v1.ZTBusService/GetTripByName"
+	ZTBusService_GetTripsByBus_FullMethodName        = "/ztbus.v1.ZTBusService/GetTripsByBus"
+	ZTBusService_GetTripsByRoute_FullMethodName      = "/ztbus.v1.ZTBusService/GetTripsByRoute"
+	ZTBusService_GetTripsByTimeRange_FullMethodName  = "/ztbus.v1.ZTBusService/GetTripsByTimeRange"
+	ZTBusService_GetTelemetryByTrip_FullMethodName   = "/ztbus.v1.ZTBusService/GetTelemetryByTrip"
+	ZTBusService_ListTelemetryInRange_FullMethodName = "/ztbus.v1.ZTBusService/ListTelemetryInRange"
+	ZTBusService_ListTelemetryPage_FullMethodName    = "/ztbus.v1.ZTBusService/ListTelemetryPage"
+)
+
+// ZTBusServiceClient is the client API for ZTBusService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ZTBusService gives read access to the loaded ZTBus data. Each RPC mirrors
+// the query of the same name in query.sql. Nullable columns are optional
+// fields, and nullable times are unset timestamps.
+type ZTBusServiceClient interface {
+	ListBuses(ctx context.Context, in *ListBusesRequest, opts ...grpc.CallOption) (*ListBusesResponse, error)
+	ListRoutes(ctx context.Context, in *ListRoutesRequest, opts ...grpc.CallOption) (*ListRoutesResponse, error)
+	ListAllTrips(ctx context.Context, in *ListAllTripsRequest, opts ...grpc.CallOption) (*ListAllTripsResponse, error)
+	GetTripByName(ctx context.Context, in *GetTripByNameRequest, opts ...grpc.CallOption) (*GetTripByNameResponse, error)
+	GetTripsByBus(ctx context.Context, in *GetTripsByBusRequest, opts ...grpc.CallOption) (*GetTripsByBusResponse, error)
+	GetTripsByRoute(ctx context.Context, in *GetTripsByRouteRequest, opts ...grpc.CallOption) (*GetTripsByRouteResponse, error)
+	GetTripsByTimeRange(ctx context.Context, in *GetTripsByTimeRangeRequest, opts ...grpc.CallOption) (*GetTripsByTimeRangeResponse, error)
+	// GetTelemetryByTrip streams the telemetry of a trip in time order
+	GetTelemetryByTrip(ctx context.Context, in *GetTelemetryByTripRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetTelemetryByTripResponse], error)
+	// ListTelemetryInRange streams the telemetry of a trip between two times
+	ListTelemetryInRange(ctx context.Context, in *ListTelemetryInRangeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ListTelemetryInRangeResponse], error)
+	// ListTelemetryPage returns one keyset page of the telemetry of all trips in
+	// a time range
+	ListTelemetryPage(ctx context.Context, in *ListTelemetryPageRequest, opts ...grpc.CallOption) (*ListTelemetryPageResponse, error)
+}
+
+type zTBusServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewZTBusServiceClient(cc grpc.ClientConnInterface) ZTBusServiceClient {
+	return &zTBusServiceClient{cc}
+}
+
+func (c *zTBusServiceClient) ListBuses(ctx context.Context, in *ListBusesRequest, opts ...grpc.CallOption) (*ListBusesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBusesResponse)
+	err := c.cc.Invoke(ctx, ZTBusService_ListBuses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *zTBusServiceClient) ListRoutes(ctx context.Context, in *ListRoutesRequest, opts ...grpc.CallOption) (*ListRoutesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRoutesResponse)
+	err := c.cc.Invoke(ctx, ZTBusService_ListRoutes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *zTBusServiceClient) ListAllTrips(ctx context.Context, in *ListAllTripsRequest, opts ...grpc.CallOption) (*ListAllTripsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAllTripsResponse)
+	err := c.cc.Invoke(ctx, ZTBusService_ListAllTrips_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *zTBusServiceClient) GetTripByName(ctx context.Context, in *GetTripByNameRequest, opts ...grpc.CallOption) (*GetTripByNameResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTripByNameResponse)
+	err := c.cc.Invoke(ctx, ZTBusService_GetTripByName_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *zTBusServiceClient) GetTripsByBus(ctx context.Context, in *GetTripsByBusRequest, opts ...grpc.CallOption) (*GetTripsByBusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTripsByBusResponse)
+	err := c.cc.Invoke(ctx, ZTBusService_GetTripsByBus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *zTBusServiceClient) GetTripsByRoute(ctx context.Context, in *GetTripsByRouteRequest, opts ...grpc.CallOption) (*GetTripsByRouteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTripsByRouteResponse)
+	err := c.cc.Invoke(ctx, ZTBusService_GetTripsByRoute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *zTBusServiceClient) GetTripsByTimeRange(ctx context.Context, in *GetTripsByTimeRangeRequest, opts ...grpc.CallOption) (*GetTripsByTimeRangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTripsByTimeRangeResponse)
+	err := c.cc.Invoke(ctx, ZTBusService_GetTripsByTimeRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *zTBusServiceClient) GetTelemetryByTrip(ctx context.Context, in *GetTelemetryByTripRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetTelemetryByTripResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ZTBusService_ServiceDesc.Streams[0], ZTBusService_GetTelemetryByTrip_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetTelemetryByTripRequest, GetTelemetryByTripResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ZTBusService_GetTelemetryByTripClient = grpc.ServerStreamingClient[GetTelemetryByTripResponse]
+
+func (c *zTBusServiceClient) ListTelemetryInRange(ctx context.Context, in *ListTelemetryInRangeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ListTelemetryInRangeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ZTBusService_ServiceDesc.Streams[1], ZTBusService_ListTelemetryInRange_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListTelemetryInRangeRequest, ListTelemetryInRangeResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ZTBusService_ListTelemetryInRangeClient = grpc.ServerStreamingClient[ListTelemetryInRangeResponse]
+
+func (c *zTBusServiceClient) ListTelemetryPage(ctx context.Context, in *ListTelemetryPageRequest, opts ...grpc.CallOption) (*ListTelemetryPageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTelemetryPageResponse)
+	err := c.cc.Invoke(ctx, ZTBusService_ListTelemetryPage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ZTBusServiceServer is the server API for ZTBusService service.
+// All implementations must embed UnimplementedZTBusServiceServer
+// for forward compatibility.
+//
+// ZTBusService gives read access to the loaded ZTBus data. Each RPC mirrors
+// the query of the same name in query.sql. Nullable columns are optional
+// fields, and nullable times are unset timestamps.
+type ZTBusServiceServer interface {
+	ListBuses(context.Context, *ListBusesRequest) (*ListBusesResponse, error)
+	ListRoutes(context.Context, *ListRoutesRequest) (*ListRoutesResponse, error)
+	ListAllTrips(context.Context, *ListAllTripsRequest) (*ListAllTripsResponse, error)
+	GetTripByName(context.Context, *GetTripByNameRequest) (*GetTripByNameResponse, error)
+	GetTripsByBus(context.Context, *GetTripsByBusRequest) (*GetTripsByBusResponse, error)
+	GetTripsByRoute(context.Context, *GetTripsByRouteRequest) (*GetTripsByRouteResponse, error)
+	GetTripsByTimeRange(context.Context, *GetTripsByTimeRangeRequest) (*GetTripsByTimeRangeResponse, error)
+	// GetTelemetryByTrip streams the telemetry of a trip in time order
+	GetTelemetryByTrip(*GetTelemetryByTripRequest, grpc.ServerStreamingServer[GetTelemetryByTripResponse]) error
+	// ListTelemetryInRange streams the telemetry of a trip between two times
+	ListTelemetryInRange(*ListTelemetryInRangeRequest, grpc.ServerStreamingServer[ListTelemetryInRangeResponse]) error
+	// ListTelemetryPage returns one keyset page of the telemetry of all trips in
+	// a time range
+	ListTelemetryPage(context.Context, *ListTelemetryPageRequest) (*ListTelemetryPageResponse, error)
+	mustEmbedUnimplementedZTBusServiceServer()
+}
+
+// UnimplementedZTBusServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedZTBusServiceServer struct{}
+
+func (UnimplementedZTBusServiceServer) ListBuses(context.Context, *ListBusesRequest) (*ListBusesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListBuses not implemented")
+}
+func (UnimplementedZTBusServiceServer) ListRoutes(context.Context, *ListRoutesRequest) (*ListRoutesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListRoutes not implemented")
+}
+func (UnimplementedZTBusServiceServer) ListAllTrips(context.Context, *ListAllTripsRequest) (*ListAllTripsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAllTrips not implemented")
+}
+func (UnimplementedZTBusServiceServer) GetTripByName(context.Context, *GetTripByNameRequest) (*GetTripByNameResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTripByName not implemented")
+}
+func (UnimplementedZTBusServiceServer) GetTripsByBus(context.Context, *GetTripsByBusRequest) (*GetTripsByBusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTripsByBus not implemented")
+}
+func (UnimplementedZTBusServiceServer) GetTripsByRoute(context.Context, *GetTripsByRouteRequest) (*GetTripsByRouteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTripsByRoute not implemented")
+}
+func (UnimplementedZTBusServiceServer) GetTripsByTimeRange(context.Context, *GetTripsByTimeRangeRequest) (*GetTripsByTimeRangeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTripsByTimeRange not implemented")
+}
+func (UnimplementedZTBusServiceServer) GetTelemetryByTrip(*GetTelemetryByTripRequest, grpc.ServerStreamingServer[GetTelemetryByTripResponse]) error {
+	return status.Error(codes.Unimplemented, "method GetTelemetryByTrip not implemented")
+}
+func (UnimplementedZTBusServiceServer) ListTelemetryInRange(*ListTelemetryInRangeRequest, grpc.ServerStreamingServer[ListTelemetryInRangeResponse]) error {
+	return status.Error(codes.Unimplemented, "method ListTelemetryInRange not implemented")
+}
+func (UnimplementedZTBusServiceServer) ListTelemetryPage(context.Context, *ListTelemetryPageRequest) (*ListTelemetryPageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTelemetryPage not implemented")
+}
+func (UnimplementedZTBusServiceServer) mustEmbedUnimplementedZTBusServiceServer() {}
+func (UnimplementedZTBusServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeZTBusServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ZTBusServiceServer will
+// result in compilation errors.
+type UnsafeZTBusServiceServer interface {
+	mustEmbedUnimplementedZTBusServiceServer()
+}
+
+func RegisterZTBusServiceServer(s grpc.ServiceRegistrar, srv ZTBusServiceServer) {
+	// If the following call panics, it indicates UnimplementedZTBusServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ZTBusService_ServiceDesc, srv)
+}
+
+func _ZTBusService_ListBuses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBusesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZTBusServiceServer).ListBuses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ZTBusService_ListBuses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZTBusServiceServer).ListBuses(ctx, req.(*ListBusesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ZTBusService_ListRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRoutesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZTBusServiceServer).ListRoutes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ZTBusService_ListRoutes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZTBusServiceServer).ListRoutes(ctx, req.(*ListRoutesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ZTBusService_ListAllTrips_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAllTripsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZTBusServiceServer).ListAllTrips(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ZTBusService_ListAllTrips_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZTBusServiceServer).ListAllTrips(ctx, req.(*ListAllTripsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ZTBusService_GetTripByName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTripByNameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZTBusServiceServer).GetTripByName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ZTBusService_GetTripByName_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZTBusServiceServer).GetTripByName(ctx, req.(*GetTripByNameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ZTBusService_GetTripsByBus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTripsByBusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZTBusServiceServer).GetTripsByBus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ZTBusService_GetTripsByBus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZTBusServiceServer).GetTripsByBus(ctx, req.(*GetTripsByBusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ZTBusService_GetTripsByRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTripsByRouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZTBusServiceServer).GetTripsByRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ZTBusService_GetTripsByRoute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZTBusServiceServer).GetTripsByRoute(ctx, req.(*GetTripsByRouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ZTBusService_GetTripsByTimeRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTripsByTimeRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZTBusServiceServer).GetTripsByTimeRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ZTBusService_GetTripsByTimeRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZTBusServiceServer).GetTripsByTimeRange(ctx, req.(*GetTripsByTimeRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ZTBusService_GetTelemetryByTrip_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetTelemetryByTripRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ZTBusServiceServer).GetTelemetryByTrip(m, &grpc.GenericServerStream[GetTelemetryByTripRequest, GetTelemetryByTripResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ZTBusService_GetTelemetryByTripServer = grpc.ServerStreamingServer[GetTelemetryByTripResponse]
+
+func _ZTBusService_ListTelemetryInRange_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListTelemetryInRangeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ZTBusServiceServer).ListTelemetryInRange(m, &grpc.GenericServerStream[ListTelemetryInRangeRequest, ListTelemetryInRangeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ZTBusService_ListTelemetryInRangeServer = grpc.ServerStreamingServer[ListTelemetryInRangeResponse]
+
+func _ZTBusService_ListTelemetryPage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTelemetryPageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZTBusServiceServer).ListTelemetryPage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ZTBusService_ListTelemetryPage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZTBusServiceServer).ListTelemetryPage(ctx, req.(*ListTelemetryPageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ZTBusService_ServiceDesc is the grpc.ServiceDesc for ZTBusService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ZTBusService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ztbus.v1.ZTBusService",
+	HandlerType: (*ZTBusServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListBuses",
+			Handler:    _ZTBusService_ListBuses_Handler,
+		},
+		{
+			MethodName: "ListRoutes",
+			Handler:    _ZTBusService_ListRoutes_Handler,
+		},
+		{
+			MethodName: "ListAllTrips",
+			Handler:    _ZTBusService_ListAllTrips_Handler,
+		},
+		{
+			MethodName: "GetTripByName",
+			Handler:    _ZTBusService_GetTripByName_Handler,
+		},
+		{
+			MethodName: "GetTripsByBus",
+			Handler:    _ZTBusService_GetTripsByBus_Handler,
+		},
+		{
+			MethodName: "GetTripsByRoute",
+			Handler:    _ZTBusService_GetTripsByRoute_Handler,
+		},
+		{
+			MethodName: "GetTripsByTimeRange",
+			Handler:    _ZTBusService_GetTripsByTimeRange_Handler,
+		},
+		{
+			MethodName: "ListTelemetryPage",
+			Handler:    _ZTBusService_ListTelemetryPage_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetTelemetryByTrip",
+			Handler:       _ZTBusService_GetTelemetryByTrip_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListTelemetryInRange",
+			Handler:       _ZTBusService_ListTelemetryInRange_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "ztbus/v1/ztbus.proto",
+}
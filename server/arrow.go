@@ -0,0 +1,331 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"reflect"
+	"slices"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DefaultArrowBatchRows is the number of rows per record batch
+const DefaultArrowBatchRows = 65536
+
+// arrowColumn is one field of a generated struct as an Arrow column
+type arrowColumn struct {
+	field  arrow.Field
+	append func(b array.Builder, v reflect.Value)
+}
+
+// newArrowColumn maps a field of a generated struct to an Arrow column. Plain
+// Go types are never null, pgtype values are null unless the column is listed
+// as not null.
+func newArrowColumn(name string, t reflect.Type, notNull bool) (arrowColumn, error) {
+	c := arrowColumn{field: arrow.Field{Name: name, Nullable: !notNull}}
+	switch t {
+	case reflect.TypeFor[int64]():
+		c.field.Type, c.field.Nullable = arrow.PrimitiveTypes.Int64, false
+		c.append = func(b array.Builder, v reflect.Value) { b.(*array.Int64Builder).Append(v.Int()) }
+	case reflect.TypeFor[int32]():
+		c.field.Type, c.field.Nullable = arrow.PrimitiveTypes.Int32, false
+		c.append = func(b array.Builder, v reflect.Value) { b.(*array.Int32Builder).Append(int32(v.Int())) }
+	case reflect.TypeFor[float32]():
+		c.field.Type, c.field.Nullable = arrow.PrimitiveTypes.Float32, false
+		c.append = func(b array.Builder, v reflect.Value) { b.(*array.Float32Builder).Append(float32(v.Float())) }
+	case reflect.TypeFor[float64]():
+		c.field.Type, c.field.Nullable = arrow.PrimitiveTypes.Float64, false
+		c.append = func(b array.Builder, v reflect.Value) { b.(*array.Float64Builder).Append(v.Float()) }
+	case reflect.TypeFor[bool]():
+		c.field.Type, c.field.Nullable = arrow.FixedWidthTypes.Boolean, false
+		c.append = func(b array.Builder, v reflect.Value) { b.(*array.BooleanBuilder).Append(v.Bool()) }
+	case reflect.TypeFor[string]():
+		c.field.Type, c.field.Nullable = arrow.BinaryTypes.String, false
+		c.append = func(b array.Builder, v reflect.Value) { b.(*array.StringBuilder).Append(v.String()) }
+	case reflect.TypeFor[pgtype.Int4]():
+		c.field.Type = arrow.PrimitiveTypes.Int32
+		c.append = func(b array.Builder, v reflect.Value) {
+			i := v.Interface().(pgtype.Int4)
+			appendOrNull(b.(*array.Int32Builder), i.Int32, i.Valid)
+		}
+	case reflect.TypeFor[pgtype.Int8]():
+		c.field.Type = arrow.PrimitiveTypes.Int64
+		c.append = func(b array.Builder, v reflect.Value) {
+			i := v.Interface().(pgtype.Int8)
+			appendOrNull(b.(*array.Int64Builder), i.Int64, i.Valid)
+		}
+	case reflect.TypeFor[pgtype.Float4]():
+		c.field.Type = arrow.PrimitiveTypes.Float32
+		c.append = func(b array.Builder, v reflect.Value) {
+			f := v.Interface().(pgtype.Float4)
+			appendOrNull(b.(*array.Float32Builder), f.Float32, f.Valid)
+		}
+	case reflect.TypeFor[pgtype.Float8]():
+		c.field.Type = arrow.PrimitiveTypes.Float64
+		c.append = func(b array.Builder, v reflect.Value) {
+			f := v.Interface().(pgtype.Float8)
+			appendOrNull(b.(*array.Float64Builder), f.Float64, f.Valid)
+		}
+	case reflect.TypeFor[pgtype.Bool]():
+		c.field.Type = arrow.FixedWidthTypes.Boolean
+		c.append = func(b array.Builder, v reflect.Value) {
+			x := v.Interface().(pgtype.Bool)
+			appendOrNull(b.(*array.BooleanBuilder), x.Bool, x.Valid)
+		}
+	case reflect.TypeFor[pgtype.Text]():
+		c.field.Type = arrow.BinaryTypes.String
+		c.append = func(b array.Builder, v reflect.Value) {
+			s := v.Interface().(pgtype.Text)
+			appendOrNull(b.(*array.StringBuilder), s.String, s.Valid)
+		}
+	case reflect.TypeFor[pgtype.Timestamptz]():
+		c.field.Type = arrow.FixedWidthTypes.Timestamp_us
+		c.append = func(b array.Builder, v reflect.Value) {
+			ts := v.Interface().(pgtype.Timestamptz)
+			if !ts.Valid || ts.InfinityModifier != pgtype.Finite {
+				b.AppendNull()
+				return
+			}
+			b.(*array.TimestampBuilder).Append(arrow.Timestamp(ts.Time.UnixMicro()))
+		}
+	default:
+		return c, fmt.Errorf("%s: unsupported field type %s", name, t)
+	}
+	return c, nil
+}
+
+// appendOrNull appends a value, or a null if it is not valid
+func appendOrNull[T any, B interface {
+	Append(T)
+	AppendNull()
+}](b B, v T, valid bool) {
+	if valid {
+		b.Append(v)
+	} else {
+		b.AppendNull()
+	}
+}
+
+// arrowTable converts generated structs into Arrow record batches
+type arrowTable[T any] struct {
+	schema  *arrow.Schema
+	columns []arrowColumn
+}
+
+// newArrowTable builds the schema of T, with the listed columns not null
+func newArrowTable[T any](notNull ...string) (*arrowTable[T], error) {
+	t := reflect.TypeFor[T]()
+	names := recordColumns(t)
+	table := &arrowTable[T]{columns: make([]arrowColumn, len(names))}
+	fields := make([]arrow.Field, len(names))
+	for i, name := range names {
+		c, err := newArrowColumn(name, t.Field(i).Type, slices.Contains(notNull, name))
+		if err != nil {
+			return nil, err
+		}
+		table.columns[i], fields[i] = c, c.field
+	}
+	table.schema = arrow.NewSchema(fields, nil)
+	return table, nil
+}
+
+// batches groups items into record batches of up to size rows. Each batch
+// must be released by the caller.
+func (t *arrowTable[T]) batches(
+	mem memory.Allocator,
+	items iter.Seq2[T, error],
+	size int,
+) iter.Seq2[arrow.RecordBatch, error] {
+	return func(yield func(arrow.RecordBatch, error) bool) {
+		b := array.NewRecordBuilder(mem, t.schema)
+		defer b.Release()
+		b.Reserve(size)
+		n := 0
+		for item, err := range items {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			v := reflect.ValueOf(item)
+			for i, c := range t.columns {
+				c.append(b.Field(i), v.Field(i))
+			}
+			if n++; n == size {
+				if !yield(b.NewRecordBatch(), nil) {
+					return
+				}
+				b.Reserve(size)
+				n = 0
+			}
+		}
+		if n > 0 {
+			yield(b.NewRecordBatch(), nil)
+		}
+	}
+}
+
+// telemetryArrow is the Arrow schema of the TelemetryWithRoute model
+var telemetryArrow = func() *arrowTable[postgres.TelemetryWithRoute] {
+	t, err := newArrowTable[postgres.TelemetryWithRoute]("time")
+	if err != nil {
+		panic(err)
+	}
+	return t
+}()
+
+// TelemetrySelection picks the telemetry to export: one trip, optionally
+// limited to a time range, or all trips in a time range, optionally of one bus
+// or route. Times are RFC3339 or YYYY-MM-DD.
+type TelemetrySelection struct {
+	Trip  string `json:"trip,omitempty"`
+	Bus   string `json:"bus,omitempty"`
+	Route string `json:"route,omitempty"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
+
+// rows resolves the selection into a stream of telemetry ordered by trip and
+// time. Errors in the selection itself are returned as apiErrors.
+func (sel TelemetrySelection) rows(ctx context.Context, q *postgres.Queries) (iter.Seq2[postgres.TelemetryWithRoute, error], error) {
+	from, err := filterTimestamp("from", sel.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := filterTimestamp("to", sel.To)
+	if err != nil {
+		return nil, err
+	}
+
+	if sel.Trip != "" {
+		if sel.Bus != "" || sel.Route != "" {
+			return nil, badRequest("a trip cannot be combined with a bus or route")
+		}
+		trip, err := q.GetTripByName(ctx, sel.Trip)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, notFound("no trip '%s'", sel.Trip)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !from.Valid && !to.Valid {
+			return q.StreamTelemetryByTrip(ctx, trip.ID), nil
+		}
+		arg := postgres.ListTelemetryInRangeParams{TripID: trip.ID, StartTime: from, EndTime: to}
+		if !from.Valid {
+			arg.StartTime = trip.StartTime
+		}
+		if !to.Valid {
+			arg.EndTime = trip.EndTime
+		}
+		return q.StreamTelemetryInRange(ctx, arg), nil
+	}
+
+	if !from.Valid || !to.Valid {
+		return nil, badRequest("from and to are required unless a trip is given")
+	}
+	arg := postgres.ListTelemetryPageParams{
+		StartTime: from,
+		EndTime:   to,
+		AfterTime: pgtype.Timestamptz{InfinityModifier: pgtype.NegativeInfinity, Valid: true},
+		PageSize:  telemetryPageSize,
+	}
+	if arg.BusID, err = lookupBusID(ctx, q, sel.Bus); err != nil {
+		return nil, err
+	}
+	if arg.RouteID, err = lookupRouteID(ctx, q, sel.Route); err != nil {
+		return nil, err
+	}
+	return q.StreamTelemetryPages(ctx, arg), nil
+}
+
+// ExportOptions sets how ExportArrow writes a file
+type ExportOptions struct {
+	BatchRows   int    // rows per record batch
+	Compression string // buffer compression: none, lz4 or zstd
+}
+
+// DefaultExportOptions returns the options of the export command
+func DefaultExportOptions() ExportOptions {
+	return ExportOptions{BatchRows: DefaultArrowBatchRows, Compression: "zstd"}
+}
+
+// Validate checks the options
+func (opts ExportOptions) Validate() error {
+	if opts.BatchRows < 1 {
+		return fmt.Errorf("option BatchRows must be at least 1, got %d", opts.BatchRows)
+	}
+	switch opts.Compression {
+	case "none", "lz4", "zstd":
+		return nil
+	}
+	return fmt.Errorf("option Compression must be none, lz4 or zstd, got '%s'", opts.Compression)
+}
+
+// ExportSummary counts what ExportArrow wrote
+type ExportSummary struct {
+	Rows    int64
+	Batches int64
+}
+
+// ExportArrow writes the telemetry of a selection to an Arrow IPC file. The
+// file is only created once the selection has been resolved.
+func ExportArrow(
+	ctx context.Context,
+	q *postgres.Queries,
+	sel TelemetrySelection,
+	path string,
+	opts ExportOptions,
+) (ExportSummary, error) {
+	var summary ExportSummary
+	if err := opts.Validate(); err != nil {
+		return summary, err
+	}
+	mem := memory.DefaultAllocator
+	ipcOpts := []ipc.Option{ipc.WithSchema(telemetryArrow.schema), ipc.WithAllocator(mem)}
+	switch opts.Compression {
+	case "lz4":
+		ipcOpts = append(ipcOpts, ipc.WithLZ4())
+	case "zstd":
+		ipcOpts = append(ipcOpts, ipc.WithZstd())
+	}
+
+	rows, err := sel.rows(ctx, q)
+	if err != nil {
+		return summary, err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return summary, err
+	}
+	defer f.Close()
+	w, err := ipc.NewFileWriter(f, ipcOpts...)
+	if err != nil {
+		return summary, fmt.Errorf("could not start %s: %v", path, err)
+	}
+	for batch, err := range telemetryArrow.batches(mem, rows, opts.BatchRows) {
+		if err != nil {
+			return summary, fmt.Errorf("could not read telemetry: %v", err)
+		}
+		summary.Rows += batch.NumRows()
+		summary.Batches++
+		err = w.Write(batch)
+		batch.Release()
+		if err != nil {
+			return summary, fmt.Errorf("could not write %s: %v", path, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return summary, fmt.Errorf("could not write %s: %v", path, err)
+	}
+	return summary, f.Close()
+}
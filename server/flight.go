@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// flightServer serves telemetry as Arrow record batches over Arrow Flight.
+// Tickets and descriptor commands are TelemetrySelections as JSON, e.g.
+// {"route": "33", "from": "2022-03-01", "to": "2022-04-01"}.
+type flightServer struct {
+	flight.BaseFlightServer
+	q         *postgres.Queries
+	batchRows int
+}
+
+// selection decodes a ticket or descriptor command
+func (f *flightServer) selection(cmd []byte) (TelemetrySelection, error) {
+	var sel TelemetrySelection
+	if err := json.Unmarshal(cmd, &sel); err != nil {
+		return sel, status.Errorf(codes.InvalidArgument, "invalid telemetry selection: %v", err)
+	}
+	return sel, nil
+}
+
+// GetFlightInfo checks a selection and returns the ticket to fetch it with.
+// The number of rows is not known until the selection is read.
+func (f *flightServer) GetFlightInfo(
+	ctx context.Context,
+	desc *flight.FlightDescriptor,
+) (*flight.FlightInfo, error) {
+	if desc.Type != flight.DescriptorCMD {
+		return nil, status.Error(codes.InvalidArgument, "descriptor must be a command")
+	}
+	sel, err := f.selection(desc.Cmd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := sel.rows(ctx, f.q); err != nil {
+		return nil, grpcError(err)
+	}
+	return &flight.FlightInfo{
+		Schema:           flight.SerializeSchema(telemetryArrow.schema, memory.DefaultAllocator),
+		FlightDescriptor: desc,
+		Endpoint:         []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: desc.Cmd}}},
+		TotalRecords:     -1,
+		TotalBytes:       -1,
+	}, nil
+}
+
+// GetSchema returns the telemetry schema, which is the same for all selections
+func (f *flightServer) GetSchema(
+	ctx context.Context,
+	desc *flight.FlightDescriptor,
+) (*flight.SchemaResult, error) {
+	return &flight.SchemaResult{
+		Schema: flight.SerializeSchema(telemetryArrow.schema, memory.DefaultAllocator),
+	}, nil
+}
+
+// DoGet streams the telemetry of a selection
+func (f *flightServer) DoGet(tkt *flight.Ticket, stream flight.FlightService_DoGetServer) error {
+	sel, err := f.selection(tkt.Ticket)
+	if err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	rows, err := sel.rows(ctx, f.q)
+	if err != nil {
+		return grpcError(err)
+	}
+
+	mem := memory.DefaultAllocator
+	w := flight.NewRecordWriter(stream, ipc.WithSchema(telemetryArrow.schema), ipc.WithAllocator(mem))
+	defer w.Close()
+	for batch, err := range telemetryArrow.batches(mem, rows, f.batchRows) {
+		if err != nil {
+			return grpcError(err)
+		}
+		err = w.Write(batch)
+		batch.Release()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,362 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"log/slog"
+	"net/http"
+
+	ztbusv1 "github.com/Predixus/orca-ztbus-prep/proto/ztbus/v1"
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// grpcTelemetryChunk is the number of telemetry rows per streamed message
+const grpcTelemetryChunk = 1000
+
+// grpcServer implements ztbusv1.ZTBusServiceServer on the generated queries
+type grpcServer struct {
+	ztbusv1.UnimplementedZTBusServiceServer
+	q        *postgres.Queries
+	maxLimit int
+}
+
+// grpcError converts an error into a gRPC status
+func grpcError(err error) error {
+	var apiErr *apiError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.status == http.StatusBadRequest:
+		return status.Error(codes.InvalidArgument, apiErr.msg)
+	case errors.As(err, &apiErr) && apiErr.status == http.StatusNotFound:
+		return status.Error(codes.NotFound, apiErr.msg)
+	case errors.Is(err, pgx.ErrNoRows):
+		return status.Error(codes.NotFound, "not found")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	slog.Error("rpc failed", "error", err)
+	return status.Error(codes.Internal, "internal server error")
+}
+
+func optText(t pgtype.Text) *string {
+	if !t.Valid {
+		return nil
+	}
+	return &t.String
+}
+
+func optInt4(i pgtype.Int4) *int32 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int32
+}
+
+func optFloat4(f pgtype.Float4) *float32 {
+	if !f.Valid {
+		return nil
+	}
+	return &f.Float32
+}
+
+func optFloat8(f pgtype.Float8) *float64 {
+	if !f.Valid {
+		return nil
+	}
+	return &f.Float64
+}
+
+func optBool(b pgtype.Bool) *bool {
+	if !b.Valid {
+		return nil
+	}
+	return &b.Bool
+}
+
+// toTimestamp converts a time, leaving null and infinite times unset
+func toTimestamp(t pgtype.Timestamptz) *timestamppb.Timestamp {
+	if !t.Valid || t.InfinityModifier != pgtype.Finite {
+		return nil
+	}
+	return timestamppb.New(t.Time)
+}
+
+// fromTimestamp converts a time, with an unset time as the given infinity
+func fromTimestamp(ts *timestamppb.Timestamp, unset pgtype.InfinityModifier) pgtype.Timestamptz {
+	if ts == nil {
+		return pgtype.Timestamptz{InfinityModifier: unset, Valid: true}
+	}
+	return pgtype.Timestamptz{Time: ts.AsTime(), Valid: true}
+}
+
+func busToProto(b postgres.Bus) *ztbusv1.Bus {
+	return &ztbusv1.Bus{Id: b.ID, BusNumber: optText(b.BusNumber)}
+}
+
+func routeToProto(r postgres.BusRoute) *ztbusv1.BusRoute {
+	return &ztbusv1.BusRoute{Id: r.ID, RouteCode: optText(r.RouteCode)}
+}
+
+func tripToProto(t postgres.Trip) *ztbusv1.Trip {
+	return &ztbusv1.Trip{
+		Id:                   t.ID,
+		Name:                 t.Name,
+		BusId:                optInt4(t.BusID),
+		RouteId:              optInt4(t.RouteID),
+		StartTime:            toTimestamp(t.StartTime),
+		EndTime:              toTimestamp(t.EndTime),
+		DrivenDistanceKm:     optFloat4(t.DrivenDistanceKm),
+		EnergyConsumptionKwh: optInt4(t.EnergyConsumptionKwh),
+		ItcsPassengersMean:   optFloat4(t.ItcsPassengersMean),
+		ItcsPassengersMin:    optInt4(t.ItcsPassengersMin),
+		ItcsPassengersMax:    optInt4(t.ItcsPassengersMax),
+		GridAvailableMean:    optFloat4(t.GridAvailableMean),
+		AmbTemperatureMean:   optFloat4(t.AmbTemperatureMean),
+		AmbTemperatureMin:    optFloat4(t.AmbTemperatureMin),
+		AmbTemperatureMax:    optFloat4(t.AmbTemperatureMax),
+	}
+}
+
+func tripsToProto(trips []postgres.Trip) []*ztbusv1.Trip {
+	out := make([]*ztbusv1.Trip, len(trips))
+	for i, t := range trips {
+		out[i] = tripToProto(t)
+	}
+	return out
+}
+
+func telemetryToProto(t postgres.TelemetryWithRoute) *ztbusv1.Telemetry {
+	return &ztbusv1.Telemetry{
+		Id:                        t.ID,
+		TripId:                    t.TripID,
+		Time:                      toTimestamp(t.Time),
+		ElectricPowerDemand:       optFloat4(t.ElectricPowerDemand),
+		TemperatureAmbient:        optFloat4(t.TemperatureAmbient),
+		TractionBrakePressure:     optFloat4(t.TractionBrakePressure),
+		TractionTractionForce:     optFloat4(t.TractionTractionForce),
+		GnssAltitude:              optFloat8(t.GnssAltitude),
+		GnssCourse:                optFloat4(t.GnssCourse),
+		GnssLatitude:              optFloat8(t.GnssLatitude),
+		GnssLongitude:             optFloat8(t.GnssLongitude),
+		ItcsBusRouteId:            optInt4(t.ItcsBusRouteID),
+		ItcsNumberOfPassengers:    optInt4(t.ItcsNumberOfPassengers),
+		OdometryArticulationAngle: optFloat4(t.OdometryArticulationAngle),
+		OdometrySteeringAngle:     optFloat4(t.OdometrySteeringAngle),
+		OdometryVehicleSpeed:      optFloat4(t.OdometryVehicleSpeed),
+		OdometryWheelSpeedFl:      optFloat4(t.OdometryWheelSpeedFl),
+		OdometryWheelSpeedFr:      optFloat4(t.OdometryWheelSpeedFr),
+		OdometryWheelSpeedMl:      optFloat4(t.OdometryWheelSpeedMl),
+		OdometryWheelSpeedMr:      optFloat4(t.OdometryWheelSpeedMr),
+		OdometryWheelSpeedRl:      optFloat4(t.OdometryWheelSpeedRl),
+		OdometryWheelSpeedRr:      optFloat4(t.OdometryWheelSpeedRr),
+		StatusDoorIsOpen:          optBool(t.StatusDoorIsOpen),
+		StatusGridIsAvailable:     optBool(t.StatusGridIsAvailable),
+		StatusHaltBrakeIsActive:   optBool(t.StatusHaltBrakeIsActive),
+		StatusParkBrakeIsActive:   optBool(t.StatusParkBrakeIsActive),
+		StopId:                    optInt4(t.StopID),
+		RouteDistanceM:            optFloat4(t.RouteDistanceM),
+		RouteOffsetM:              optFloat4(t.RouteOffsetM),
+		RoutePositionEstimated:    optBool(t.RoutePositionEstimated),
+	}
+}
+
+// sendTelemetry sends rows in chunks of grpcTelemetryChunk
+func sendTelemetry(rows iter.Seq2[postgres.TelemetryWithRoute, error], send func([]*ztbusv1.Telemetry) error) error {
+	chunk := make([]*ztbusv1.Telemetry, 0, grpcTelemetryChunk)
+	for t, err := range rows {
+		if err != nil {
+			return grpcError(err)
+		}
+		if chunk = append(chunk, telemetryToProto(t)); len(chunk) == grpcTelemetryChunk {
+			if err := send(chunk); err != nil {
+				return err
+			}
+			chunk = make([]*ztbusv1.Telemetry, 0, grpcTelemetryChunk)
+		}
+	}
+	if len(chunk) > 0 {
+		return send(chunk)
+	}
+	return nil
+}
+
+func (s *grpcServer) ListBuses(
+	ctx context.Context,
+	req *ztbusv1.ListBusesRequest,
+) (*ztbusv1.ListBusesResponse, error) {
+	buses, err := s.q.ListBuses(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resp := &ztbusv1.ListBusesResponse{Buses: make([]*ztbusv1.Bus, len(buses))}
+	for i, b := range buses {
+		resp.Buses[i] = busToProto(b)
+	}
+	return resp, nil
+}
+
+func (s *grpcServer) ListRoutes(
+	ctx context.Context,
+	req *ztbusv1.ListRoutesRequest,
+) (*ztbusv1.ListRoutesResponse, error) {
+	routes, err := s.q.ListRoutes(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resp := &ztbusv1.ListRoutesResponse{Routes: make([]*ztbusv1.BusRoute, len(routes))}
+	for i, r := range routes {
+		resp.Routes[i] = routeToProto(r)
+	}
+	return resp, nil
+}
+
+func (s *grpcServer) ListAllTrips(
+	ctx context.Context,
+	req *ztbusv1.ListAllTripsRequest,
+) (*ztbusv1.ListAllTripsResponse, error) {
+	trips, err := s.q.ListAllTrips(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &ztbusv1.ListAllTripsResponse{Trips: tripsToProto(trips)}, nil
+}
+
+func (s *grpcServer) GetTripByName(
+	ctx context.Context,
+	req *ztbusv1.GetTripByNameRequest,
+) (*ztbusv1.GetTripByNameResponse, error) {
+	trip, err := s.q.GetTripByName(ctx, req.GetName())
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "no trip '%s'", req.GetName())
+	}
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &ztbusv1.GetTripByNameResponse{Trip: tripToProto(trip)}, nil
+}
+
+func (s *grpcServer) GetTripsByBus(
+	ctx context.Context,
+	req *ztbusv1.GetTripsByBusRequest,
+) (*ztbusv1.GetTripsByBusResponse, error) {
+	trips, err := s.q.GetTripsByBus(ctx, pgtype.Int4{Int32: req.GetBusId(), Valid: true})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &ztbusv1.GetTripsByBusResponse{Trips: tripsToProto(trips)}, nil
+}
+
+func (s *grpcServer) GetTripsByRoute(
+	ctx context.Context,
+	req *ztbusv1.GetTripsByRouteRequest,
+) (*ztbusv1.GetTripsByRouteResponse, error) {
+	trips, err := s.q.GetTripsByRoute(ctx, pgtype.Int4{Int32: req.GetRouteId(), Valid: true})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &ztbusv1.GetTripsByRouteResponse{Trips: tripsToProto(trips)}, nil
+}
+
+func (s *grpcServer) GetTripsByTimeRange(
+	ctx context.Context,
+	req *ztbusv1.GetTripsByTimeRangeRequest,
+) (*ztbusv1.GetTripsByTimeRangeResponse, error) {
+	trips, err := s.q.GetTripsByTimeRange(ctx, postgres.GetTripsByTimeRangeParams{
+		StartTimeFrom: fromTimestamp(req.GetStartTimeFrom(), pgtype.NegativeInfinity),
+		EndTimeTo:     fromTimestamp(req.GetEndTimeTo(), pgtype.Infinity),
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &ztbusv1.GetTripsByTimeRangeResponse{Trips: tripsToProto(trips)}, nil
+}
+
+func (s *grpcServer) GetTelemetryByTrip(
+	req *ztbusv1.GetTelemetryByTripRequest,
+	stream grpc.ServerStreamingServer[ztbusv1.GetTelemetryByTripResponse],
+) error {
+	rows := s.q.StreamTelemetryByTrip(stream.Context(), req.GetTripId())
+	return sendTelemetry(rows, func(chunk []*ztbusv1.Telemetry) error {
+		return stream.Send(&ztbusv1.GetTelemetryByTripResponse{Telemetry: chunk})
+	})
+}
+
+// ListTelemetryInRange streams the telemetry of a trip between two times. An
+// unset bound is open.
+func (s *grpcServer) ListTelemetryInRange(
+	req *ztbusv1.ListTelemetryInRangeRequest,
+	stream grpc.ServerStreamingServer[ztbusv1.ListTelemetryInRangeResponse],
+) error {
+	rows := s.q.StreamTelemetryInRange(stream.Context(), postgres.ListTelemetryInRangeParams{
+		TripID:    req.GetTripId(),
+		StartTime: fromTimestamp(req.GetStartTime(), pgtype.NegativeInfinity),
+		EndTime:   fromTimestamp(req.GetEndTime(), pgtype.Infinity),
+	})
+	return sendTelemetry(rows, func(chunk []*ztbusv1.Telemetry) error {
+		return stream.Send(&ztbusv1.ListTelemetryInRangeResponse{Telemetry: chunk})
+	})
+}
+
+// ListTelemetryPage returns one page, reading one row past it to tell whether
+// there is a next page
+func (s *grpcServer) ListTelemetryPage(
+	ctx context.Context,
+	req *ztbusv1.ListTelemetryPageRequest,
+) (*ztbusv1.ListTelemetryPageResponse, error) {
+	if req.GetStartTime() == nil || req.GetEndTime() == nil {
+		return nil, status.Error(codes.InvalidArgument, "start_time and end_time are required")
+	}
+	size := int(req.GetPageSize())
+	if size == 0 {
+		size = min(defaultPageLimit, s.maxLimit)
+	}
+	if size < 1 || size > s.maxLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be between 1 and %d", s.maxLimit)
+	}
+	arg := postgres.ListTelemetryPageParams{
+		StartTime:   fromTimestamp(req.GetStartTime(), pgtype.NegativeInfinity),
+		EndTime:     fromTimestamp(req.GetEndTime(), pgtype.Infinity),
+		AfterTripID: req.GetAfterTripId(),
+		AfterTime:   fromTimestamp(req.GetAfterTime(), pgtype.NegativeInfinity),
+		PageSize:    int32(size) + 1,
+	}
+	if req.BusId != nil {
+		arg.BusID = pgtype.Int4{Int32: req.GetBusId(), Valid: true}
+	}
+	if req.RouteId != nil {
+		arg.RouteID = pgtype.Int4{Int32: req.GetRouteId(), Valid: true}
+	}
+	page, err := s.q.ListTelemetryPage(ctx, arg)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &ztbusv1.ListTelemetryPageResponse{}
+	if len(page) > size {
+		page = page[:size]
+		last := page[len(page)-1]
+		resp.NextPage = &ztbusv1.ListTelemetryPageRequest{
+			StartTime:   req.GetStartTime(),
+			EndTime:     req.GetEndTime(),
+			AfterTripId: last.TripID,
+			AfterTime:   toTimestamp(last.Time),
+			BusId:       req.BusId,
+			RouteId:     req.RouteId,
+			PageSize:    int32(size),
+		}
+	}
+	resp.Telemetry = make([]*ztbusv1.Telemetry, len(page))
+	for i, t := range page {
+		resp.Telemetry[i] = telemetryToProto(t)
+	}
+	return resp, nil
+}
@@ -0,0 +1,234 @@
+package server
+
+import (
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// snakeCase turns a generated field name back into its column name, e.g.
+// BusID into bus_id
+func snakeCase(s string) string {
+	r := []rune(s)
+	var b strings.Builder
+	for i, c := range r {
+		if unicode.IsUpper(c) && i > 0 &&
+			(unicode.IsLower(r[i-1]) || (i+1 < len(r) && unicode.IsLower(r[i+1]))) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(c))
+	}
+	return b.String()
+}
+
+// recordColumns returns the column names of a generated model or row struct
+func recordColumns(t reflect.Type) []string {
+	columns := make([]string, t.NumField())
+	for i := range columns {
+		columns[i] = snakeCase(t.Field(i).Name)
+	}
+	return columns
+}
+
+// recordValue converts a field of a generated struct into nil, bool, int64,
+// json.Number, string or time.Time
+func recordValue(v any) (any, error) {
+	switch v := v.(type) {
+	case pgtype.Float4:
+		// keep float32 precision rather than widening to float64 digits
+		if !v.Valid || math.IsNaN(float64(v.Float32)) || math.IsInf(float64(v.Float32), 0) {
+			return nil, nil
+		}
+		return json.Number(strconv.FormatFloat(float64(v.Float32), 'g', -1, 32)), nil
+	case pgtype.Float8:
+		if !v.Valid || math.IsNaN(v.Float64) || math.IsInf(v.Float64, 0) {
+			return nil, nil
+		}
+		return json.Number(strconv.FormatFloat(v.Float64, 'g', -1, 64)), nil
+	case float32:
+		return json.Number(strconv.FormatFloat(float64(v), 'g', -1, 32)), nil
+	case float64:
+		return json.Number(strconv.FormatFloat(v, 'g', -1, 64)), nil
+	case int32:
+		return int64(v), nil
+	case int64, bool, string:
+		return v, nil
+	case []byte:
+		// JSONB
+		return json.RawMessage(v), nil
+	case driver.Valuer:
+		value, err := v.Value()
+		if t, ok := value.(time.Time); ok {
+			return t.UTC(), err
+		}
+		return value, err
+	}
+	return nil, fmt.Errorf("unsupported field type %T", v)
+}
+
+// recordEncoder converts generated structs into rows of the selected columns
+type recordEncoder[T any] struct {
+	all     []string
+	idx     []int
+	columns []string
+}
+
+// newRecordEncoder selects the columns in fields, or all if empty
+func newRecordEncoder[T any](fields []string) (*recordEncoder[T], error) {
+	e := &recordEncoder[T]{all: recordColumns(reflect.TypeFor[T]())}
+	var err error
+	if e.idx, err = selectColumns(e.all, fields); err != nil {
+		return nil, err
+	}
+	e.columns = make([]string, len(e.idx))
+	for i, j := range e.idx {
+		e.columns[i] = e.all[j]
+	}
+	return e, nil
+}
+
+// row returns the values of the selected columns of an item
+func (e *recordEncoder[T]) row(item T) ([]any, error) {
+	v := reflect.ValueOf(item)
+	row := make([]any, len(e.idx))
+	for i, j := range e.idx {
+		var err error
+		if row[i], err = recordValue(v.Field(j).Interface()); err != nil {
+			return nil, fmt.Errorf("%s: %v", e.all[j], err)
+		}
+	}
+	return row, nil
+}
+
+// selectColumns returns the indices of the selected columns, or of all
+func selectColumns(all []string, fields []string) ([]int, error) {
+	if len(fields) == 0 {
+		idx := make([]int, len(all))
+		for i := range idx {
+			idx[i] = i
+		}
+		return idx, nil
+	}
+	pos := make(map[string]int, len(all))
+	for i, c := range all {
+		pos[c] = i
+	}
+	idx := make([]int, len(fields))
+	for i, f := range fields {
+		j, ok := pos[f]
+		if !ok {
+			return nil, fmt.Errorf("unknown field '%s' (available: %s)", f, strings.Join(all, ", "))
+		}
+		idx[i] = j
+	}
+	return idx, nil
+}
+
+// response formats
+const (
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+	formatCSV    = "csv"
+)
+
+var formatContentTypes = map[string]string{
+	formatJSON:   "application/json",
+	formatNDJSON: "application/x-ndjson",
+	formatCSV:    "text/csv",
+}
+
+// recordWriter encodes records one at a time in one of the response formats
+type recordWriter struct {
+	w       io.Writer
+	format  string
+	columns []string
+	csv     *csv.Writer
+	rows    int
+}
+
+func newRecordWriter(w io.Writer, format string, columns []string) (*recordWriter, error) {
+	rw := &recordWriter{w: w, format: format, columns: columns}
+	switch format {
+	case formatJSON:
+		_, err := io.WriteString(w, "[")
+		return rw, err
+	case formatNDJSON:
+		return rw, nil
+	case formatCSV:
+		rw.csv = csv.NewWriter(w)
+		return rw, rw.csv.Write(columns)
+	}
+	return nil, fmt.Errorf("unsupported format '%s'", format)
+}
+
+// Write encodes one row, with values in column order
+func (rw *recordWriter) Write(row []any) error {
+	defer func() { rw.rows++ }()
+	if rw.format == formatCSV {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			cells[i] = csvCell(v)
+		}
+		return rw.csv.Write(cells)
+	}
+
+	var b strings.Builder
+	if rw.format == formatJSON && rw.rows > 0 {
+		b.WriteByte(',')
+	}
+	b.WriteByte('{')
+	for i, v := range row {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(rw.columns[i])
+		b.Write(key)
+		b.WriteByte(':')
+		value, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("%s: %v", rw.columns[i], err)
+		}
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	if rw.format == formatNDJSON {
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(rw.w, b.String())
+	return err
+}
+
+// Close finishes the encoding
+func (rw *recordWriter) Close() error {
+	switch rw.format {
+	case formatJSON:
+		_, err := io.WriteString(rw.w, "]\n")
+		return err
+	case formatCSV:
+		rw.csv.Flush()
+		return rw.csv.Error()
+	}
+	return nil
+}
+
+func csvCell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case json.RawMessage:
+		return string(v)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestSnakeCase(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"BusID", "bus_id"},
+		{"EnergyConsumptionKwh", "energy_consumption_kwh"},
+		{"OdometryWheelSpeedFl", "odometry_wheel_speed_fl"},
+		{"GtfsTripID", "gtfs_trip_id"},
+		{"ID", "id"},
+	}
+	for _, tt := range tests {
+		if got := snakeCase(tt.in); got != tt.want {
+			t.Errorf("snakeCase(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// record is a row with the field types of the generated models
+type record struct {
+	ID    int32
+	Name  pgtype.Text
+	Speed pgtype.Float4
+	Time  pgtype.Timestamptz
+}
+
+func TestRecordWriter(t *testing.T) {
+	items := []record{
+		{
+			ID:    1,
+			Name:  pgtype.Text{String: "a,b", Valid: true},
+			Speed: pgtype.Float4{Float32: 0.1, Valid: true},
+			Time:  pgtype.Timestamptz{Time: time.Unix(1_560_000_000, 0), Valid: true},
+		},
+		{ID: 2},
+	}
+	tests := []struct {
+		format string
+		fields []string
+		want   string
+	}{
+		{
+			format: formatJSON,
+			want: `[{"id":1,"name":"a,b","speed":0.1,"time":"2019-06-08T13:20:00Z"},` +
+				`{"id":2,"name":null,"speed":null,"time":null}]` + "\n",
+		},
+		{
+			format: formatNDJSON,
+			fields: []string{"speed", "id"},
+			want:   `{"speed":0.1,"id":1}` + "\n" + `{"speed":null,"id":2}` + "\n",
+		},
+		{
+			format: formatCSV,
+			want:   "id,name,speed,time\n1,\"a,b\",0.1,2019-06-08T13:20:00Z\n2,,,\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			enc, err := newRecordEncoder[record](tt.fields)
+			if err != nil {
+				t.Fatalf("newRecordEncoder() error = %v", err)
+			}
+			var b strings.Builder
+			rw, err := newRecordWriter(&b, tt.format, enc.columns)
+			if err != nil {
+				t.Fatalf("newRecordWriter() error = %v", err)
+			}
+			for _, item := range items {
+				row, err := enc.row(item)
+				if err != nil {
+					t.Fatalf("row() error = %v", err)
+				}
+				if err := rw.Write(row); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := rw.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("records =\n%s\nwant\n%s", b.String(), tt.want)
+			}
+		})
+	}
+
+	if _, err := newRecordEncoder[record]([]string{"speed", "bus"}); err == nil {
+		t.Error("newRecordEncoder() with an unknown field returned no error")
+	}
+}
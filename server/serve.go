@@ -0,0 +1,637 @@
+// Package server serves the loaded database read only over HTTP, gRPC and
+// Arrow Flight, and exports telemetry to Arrow IPC files. Serve starts the
+// servers and ExportArrow writes an export.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/loader"
+	ztbusv1 "github.com/Predixus/orca-ztbus-prep/proto/ztbus/v1"
+	"github.com/Predixus/orca-ztbus-prep/store/postgres"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
+)
+
+// defaultPageLimit is the page size when a request gives no limit
+const defaultPageLimit = 1000
+
+// httpServer exposes the read queries over HTTP
+type httpServer struct {
+	q        *postgres.Queries
+	maxLimit int
+}
+
+// apiError is an error with the HTTP status to report it with
+type apiError struct {
+	status int
+	msg    string
+}
+
+func (e *apiError) Error() string { return e.msg }
+
+func badRequest(format string, args ...any) error {
+	return &apiError{status: http.StatusBadRequest, msg: fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...any) error {
+	return &apiError{status: http.StatusNotFound, msg: fmt.Sprintf(format, args...)}
+}
+
+// statusWriter records the status of a response and whether it has started
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// handle adapts a handler returning an error, reporting the error as JSON. An
+// error after the response started aborts the connection, so that clients see
+// a broken transfer rather than a response that ends cleanly but early.
+func handle(h func(w http.ResponseWriter, r *http.Request) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w}
+		err := h(sw, r)
+		if err != nil {
+			var apiErr *apiError
+			status, msg := http.StatusInternalServerError, "internal server error"
+			if errors.As(err, &apiErr) {
+				status, msg = apiErr.status, apiErr.msg
+			} else {
+				slog.Error("request failed", "url", r.URL.String(), "error", err)
+			}
+			if sw.status == 0 {
+				sw.Header().Set("Content-Type", "application/json")
+				sw.WriteHeader(status)
+				json.NewEncoder(sw).Encode(map[string]string{"error": msg})
+				err = nil
+			}
+		}
+		slog.Info(
+			"request",
+			"method", r.Method,
+			"url", r.URL.String(),
+			"status", sw.status,
+			"duration", time.Since(start),
+			"aborted", err != nil,
+		)
+		if err != nil {
+			// net/http closes the connection without logging the panic
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+// splitList splits a comma separated value, dropping empty entries
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// page is the pagination and presentation requested by a client
+type page struct {
+	offset  int
+	limit   int
+	limited bool // limit given by the client
+	fields  []string
+	format  string
+}
+
+// parsePage reads offset, limit, fields and format from the query string. The
+// format can also be negotiated with the Accept header.
+func (s *httpServer) parsePage(r *http.Request) (page, error) {
+	p := page{limit: min(defaultPageLimit, s.maxLimit), format: formatJSON}
+	query := r.URL.Query()
+	if v := query.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return p, badRequest("offset must be a non-negative integer")
+		}
+		p.offset = n
+	}
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > s.maxLimit {
+			return p, badRequest("limit must be between 1 and %d", s.maxLimit)
+		}
+		p.limit, p.limited = n, true
+	}
+	p.fields = splitList(query.Get("fields"))
+
+	if v := query.Get("format"); v != "" {
+		if _, ok := formatContentTypes[v]; !ok {
+			return p, badRequest("format must be json, ndjson or csv")
+		}
+		p.format = v
+		return p, nil
+	}
+	for _, accept := range splitList(r.Header.Get("Accept")) {
+		mediaType, _, _ := mime.ParseMediaType(accept)
+		for format, contentType := range formatContentTypes {
+			if mediaType == contentType {
+				p.format = format
+				return p, nil
+			}
+		}
+	}
+	return p, nil
+}
+
+// recordFlushRows is how often streamed responses are flushed to the client
+const recordFlushRows = 10000
+
+// writeRecords writes items in the requested format, flushing as it goes so
+// that long responses reach the client in chunks
+func writeRecords[T any](
+	w http.ResponseWriter,
+	p page,
+	enc *recordEncoder[T],
+	items iter.Seq2[T, error],
+) error {
+	w.Header().Set("Content-Type", formatContentTypes[p.format])
+	rw, err := newRecordWriter(w, p.format, enc.columns)
+	if err != nil {
+		return err
+	}
+	rc := http.NewResponseController(w)
+	n := 0
+	for item, err := range items {
+		if err != nil {
+			return err
+		}
+		row, err := enc.row(item)
+		if err != nil {
+			return err
+		}
+		if err := rw.Write(row); err != nil {
+			return err
+		}
+		if n++; n%recordFlushRows == 0 {
+			if err := rc.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return rw.Close()
+}
+
+// linkNext sets a Link header to the same request with the query changed
+func linkNext(w http.ResponseWriter, r *http.Request, set map[string]string) {
+	u := *r.URL
+	query := u.Query()
+	for k, v := range set {
+		query.Set(k, v)
+	}
+	u.RawQuery = query.Encode()
+	w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", u.RequestURI()))
+}
+
+// writePage writes the requested page of items. The total count is sent in
+// X-Total-Count, and a Link header points to the next page.
+func writePage[T any](w http.ResponseWriter, r *http.Request, p page, items []T) error {
+	enc, err := newRecordEncoder[T](p.fields)
+	if err != nil {
+		return badRequest("%v", err)
+	}
+	total := len(items)
+	items = items[min(p.offset, total):min(p.offset+p.limit, total)]
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	if next := p.offset + p.limit; next < total {
+		linkNext(w, r, map[string]string{
+			"offset": strconv.Itoa(next),
+			"limit":  strconv.Itoa(p.limit),
+		})
+	}
+	return writeRecords(w, p, enc, seqOf(items))
+}
+
+// writeStreamPage writes the requested page of a streamed result, reading no
+// further than one item past the page to tell whether there is a next page.
+// The total is unknown, so there is no X-Total-Count.
+func writeStreamPage[T any](
+	w http.ResponseWriter,
+	r *http.Request,
+	p page,
+	items iter.Seq2[T, error],
+) error {
+	enc, err := newRecordEncoder[T](p.fields)
+	if err != nil {
+		return badRequest("%v", err)
+	}
+	var buf []T
+	more, n := false, 0
+	for item, err := range items {
+		if err != nil {
+			return err
+		}
+		if n++; n <= p.offset {
+			continue
+		}
+		if len(buf) == p.limit {
+			more = true
+			break
+		}
+		buf = append(buf, item)
+	}
+	if more {
+		linkNext(w, r, map[string]string{
+			"offset": strconv.Itoa(p.offset + p.limit),
+			"limit":  strconv.Itoa(p.limit),
+		})
+	}
+	return writeRecords(w, p, enc, seqOf(buf))
+}
+
+// seqOf yields the items of a slice without errors
+func seqOf[T any](items []T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+// queryTime parses an optional from/to query parameter
+func queryTime(query url.Values, key string) (pgtype.Timestamptz, error) {
+	return filterTimestamp(key, query.Get(key))
+}
+
+// filterTimestamp parses an optional time filter, reporting errors as bad
+// requests
+func filterTimestamp(key, v string) (pgtype.Timestamptz, error) {
+	if v == "" {
+		return pgtype.Timestamptz{}, nil
+	}
+	t, err := loader.ParseFilterTime(v)
+	if err != nil {
+		return pgtype.Timestamptz{}, badRequest("%s: %v", key, err)
+	}
+	return pgtype.Timestamptz{Time: t, Valid: true}, nil
+}
+
+// lookupBusID looks up a bus by number, returning no ID for an empty number
+func lookupBusID(ctx context.Context, q *postgres.Queries, bus string) (pgtype.Int4, error) {
+	if bus == "" {
+		return pgtype.Int4{}, nil
+	}
+	buses, err := q.ListBuses(ctx)
+	if err != nil {
+		return pgtype.Int4{}, err
+	}
+	i := slices.IndexFunc(buses, func(b postgres.Bus) bool { return b.BusNumber.String == bus })
+	if i < 0 {
+		return pgtype.Int4{}, notFound("no bus '%s'", bus)
+	}
+	return pgtype.Int4{Int32: buses[i].ID, Valid: true}, nil
+}
+
+// lookupRouteID looks up a route by code, returning no ID for an empty code
+func lookupRouteID(ctx context.Context, q *postgres.Queries, route string) (pgtype.Int4, error) {
+	if route == "" {
+		return pgtype.Int4{}, nil
+	}
+	routes, err := q.ListRoutes(ctx)
+	if err != nil {
+		return pgtype.Int4{}, err
+	}
+	i := slices.IndexFunc(routes, func(r postgres.BusRoute) bool { return r.RouteCode.String == route })
+	if i < 0 {
+		return pgtype.Int4{}, notFound("no route '%s'", route)
+	}
+	return pgtype.Int4{Int32: routes[i].ID, Valid: true}, nil
+}
+
+func (s *httpServer) listBuses(w http.ResponseWriter, r *http.Request) error {
+	p, err := s.parsePage(r)
+	if err != nil {
+		return err
+	}
+	buses, err := s.q.ListBuses(r.Context())
+	if err != nil {
+		return err
+	}
+	return writePage(w, r, p, buses)
+}
+
+func (s *httpServer) listRoutes(w http.ResponseWriter, r *http.Request) error {
+	p, err := s.parsePage(r)
+	if err != nil {
+		return err
+	}
+	routes, err := s.q.ListRoutes(r.Context())
+	if err != nil {
+		return err
+	}
+	return writePage(w, r, p, routes)
+}
+
+// listTrips lists trips, filtered by bus number, route code and the time
+// range they fall in. The most selective filter picks the query and the others
+// are applied to its result.
+func (s *httpServer) listTrips(w http.ResponseWriter, r *http.Request) error {
+	p, err := s.parsePage(r)
+	if err != nil {
+		return err
+	}
+	ctx := r.Context()
+	query := r.URL.Query()
+	from, err := queryTime(query, "from")
+	if err != nil {
+		return err
+	}
+	to, err := queryTime(query, "to")
+	if err != nil {
+		return err
+	}
+
+	busID, err := lookupBusID(ctx, s.q, query.Get("bus"))
+	if err != nil {
+		return err
+	}
+	routeID, err := lookupRouteID(ctx, s.q, query.Get("route"))
+	if err != nil {
+		return err
+	}
+
+	var trips []postgres.Trip
+	switch {
+	case busID.Valid:
+		trips, err = s.q.GetTripsByBus(ctx, busID)
+	case routeID.Valid:
+		trips, err = s.q.GetTripsByRoute(ctx, routeID)
+	case from.Valid || to.Valid:
+		arg := postgres.GetTripsByTimeRangeParams{StartTimeFrom: from, EndTimeTo: to}
+		if !from.Valid {
+			arg.StartTimeFrom = pgtype.Timestamptz{InfinityModifier: pgtype.NegativeInfinity, Valid: true}
+		}
+		if !to.Valid {
+			arg.EndTimeTo = pgtype.Timestamptz{InfinityModifier: pgtype.Infinity, Valid: true}
+		}
+		trips, err = s.q.GetTripsByTimeRange(ctx, arg)
+	default:
+		trips, err = s.q.ListAllTrips(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	trips = slices.DeleteFunc(trips, func(t postgres.Trip) bool {
+		return (routeID.Valid && t.RouteID != routeID) ||
+			(from.Valid && t.StartTime.Time.Before(from.Time)) ||
+			(to.Valid && t.EndTime.Time.After(to.Time))
+	})
+	return writePage(w, r, p, trips)
+}
+
+// trip looks up the trip named in the path
+func (s *httpServer) trip(r *http.Request) (postgres.Trip, error) {
+	name := r.PathValue("name")
+	trip, err := s.q.GetTripByName(r.Context(), name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return trip, notFound("no trip '%s'", name)
+	}
+	return trip, err
+}
+
+func (s *httpServer) getTrip(w http.ResponseWriter, r *http.Request) error {
+	p, err := s.parsePage(r)
+	if err != nil {
+		return err
+	}
+	trip, err := s.trip(r)
+	if err != nil {
+		return err
+	}
+	return writePage(w, r, p, []postgres.Trip{trip})
+}
+
+// getTripTelemetry returns the telemetry of a trip, optionally limited to the
+// samples between from and to
+func (s *httpServer) getTripTelemetry(w http.ResponseWriter, r *http.Request) error {
+	p, err := s.parsePage(r)
+	if err != nil {
+		return err
+	}
+	query := r.URL.Query()
+	from, err := queryTime(query, "from")
+	if err != nil {
+		return err
+	}
+	to, err := queryTime(query, "to")
+	if err != nil {
+		return err
+	}
+	trip, err := s.trip(r)
+	if err != nil {
+		return err
+	}
+
+	telemetry := s.q.StreamTelemetryByTrip(r.Context(), trip.ID)
+	if from.Valid || to.Valid {
+		arg := postgres.ListTelemetryInRangeParams{TripID: trip.ID, StartTime: from, EndTime: to}
+		if !from.Valid {
+			arg.StartTime = trip.StartTime
+		}
+		if !to.Valid {
+			arg.EndTime = trip.EndTime
+		}
+		telemetry = s.q.StreamTelemetryInRange(r.Context(), arg)
+	}
+	return writeStreamPage(w, r, p, telemetry)
+}
+
+// telemetryPageSize is the number of rows read per keyset page
+const telemetryPageSize = 10000
+
+// listTelemetry returns the telemetry of all trips in a time range, ordered by
+// trip and time. Without a limit the whole range is streamed. With one, a Link
+// header carries the keyset cursor (after_trip, after_time) of the next page.
+func (s *httpServer) listTelemetry(w http.ResponseWriter, r *http.Request) error {
+	p, err := s.parsePage(r)
+	if err != nil {
+		return err
+	}
+	if p.offset > 0 {
+		return badRequest("use after_trip and after_time to page through telemetry")
+	}
+	ctx := r.Context()
+	query := r.URL.Query()
+	arg := postgres.ListTelemetryPageParams{
+		AfterTime: pgtype.Timestamptz{InfinityModifier: pgtype.NegativeInfinity, Valid: true},
+		PageSize:  telemetryPageSize,
+	}
+	if arg.StartTime, err = queryTime(query, "from"); err != nil {
+		return err
+	}
+	if arg.EndTime, err = queryTime(query, "to"); err != nil {
+		return err
+	}
+	if !arg.StartTime.Valid || !arg.EndTime.Valid {
+		return badRequest("from and to are required")
+	}
+	if v := query.Get("after_trip"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return badRequest("after_trip must be a trip id")
+		}
+		if arg.AfterTime, err = queryTime(query, "after_time"); err != nil {
+			return err
+		}
+		if !arg.AfterTime.Valid {
+			return badRequest("after_trip requires after_time")
+		}
+		arg.AfterTripID = int32(id)
+	}
+	if arg.BusID, err = lookupBusID(ctx, s.q, query.Get("bus")); err != nil {
+		return err
+	}
+	if arg.RouteID, err = lookupRouteID(ctx, s.q, query.Get("route")); err != nil {
+		return err
+	}
+
+	enc, err := newRecordEncoder[postgres.TelemetryWithRoute](p.fields)
+	if err != nil {
+		return badRequest("%v", err)
+	}
+	if !p.limited {
+		return writeRecords(w, p, enc, s.q.StreamTelemetryPages(ctx, arg))
+	}
+
+	arg.PageSize = int32(p.limit) + 1
+	page, err := s.q.ListTelemetryPage(ctx, arg)
+	if err != nil {
+		return err
+	}
+	if len(page) > p.limit {
+		page = page[:p.limit]
+		last := page[len(page)-1]
+		linkNext(w, r, map[string]string{
+			"after_trip": strconv.Itoa(int(last.TripID)),
+			"after_time": last.Time.Time.UTC().Format(time.RFC3339Nano),
+		})
+	}
+	return writeRecords(w, p, enc, seqOf(page))
+}
+
+func (s *httpServer) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("GET /buses", handle(s.listBuses))
+	mux.Handle("GET /routes", handle(s.listRoutes))
+	mux.Handle("GET /telemetry", handle(s.listTelemetry))
+	mux.Handle("GET /trips", handle(s.listTrips))
+	mux.Handle("GET /trips/{name}", handle(s.getTrip))
+	mux.Handle("GET /trips/{name}/telemetry", handle(s.getTripTelemetry))
+	return mux
+}
+
+// Config selects the servers Serve starts
+type Config struct {
+	Addr       string // HTTP API
+	FlightAddr string // Arrow Flight, disabled if empty
+	GRPCAddr   string // gRPC API, disabled if empty
+	MaxLimit   int    // largest page a client may request
+}
+
+// Serve serves the database read only until ctx is done or one of the
+// servers stops, then shuts the HTTP server down gracefully
+func Serve(ctx context.Context, pool *pgxpool.Pool, c Config) error {
+	if c.MaxLimit < 1 {
+		return fmt.Errorf("option MaxLimit must be at least 1, got %d", c.MaxLimit)
+	}
+
+	s := &httpServer{q: postgres.New(pool), maxLimit: c.MaxLimit}
+	srv := &http.Server{
+		Addr:              c.Addr,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errs := make(chan error, 3)
+	go func() {
+		slog.Info("serving", "addr", c.Addr)
+		errs <- srv.ListenAndServe()
+	}()
+
+	if c.FlightAddr != "" {
+		fsrv := flight.NewServerWithMiddleware(nil)
+		if err := fsrv.Init(c.FlightAddr); err != nil {
+			return fmt.Errorf("could not listen for Arrow Flight: %v", err)
+		}
+		fsrv.RegisterFlightService(&flightServer{q: s.q, batchRows: DefaultArrowBatchRows})
+		go func() {
+			slog.Info("serving Arrow Flight", "addr", fsrv.Addr().String())
+			if err := fsrv.Serve(); err != nil {
+				errs <- err
+			}
+		}()
+		defer fsrv.Shutdown()
+	}
+
+	if c.GRPCAddr != "" {
+		lis, err := net.Listen("tcp", c.GRPCAddr)
+		if err != nil {
+			return fmt.Errorf("could not listen for gRPC: %v", err)
+		}
+		gsrv := grpc.NewServer()
+		ztbusv1.RegisterZTBusServiceServer(gsrv, &grpcServer{q: s.q, maxLimit: c.MaxLimit})
+		go func() {
+			slog.Info("serving gRPC", "addr", lis.Addr().String())
+			if err := gsrv.Serve(lis); err != nil {
+				errs <- err
+			}
+		}()
+		defer gsrv.GracefulStop()
+	}
+
+	select {
+	case err := <-errs:
+		srv.Close()
+		return fmt.Errorf("server stopped: %v", err)
+	case <-ctx.Done():
+	}
+	slog.Info("shutting down")
+	shutdown, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdown)
+}
@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// deferredIndex is a telemetry index that a bulk load drops up front and
+// rebuilds once all data is in. The unique (trip_id, time) constraint is never
+// deferred, the staging upsert depends on it.
+type deferredIndex struct {
+	Name    string // index on the partitioned table
+	Suffix  string // appended to the partition name for the per partition index
+	Method  string // btree or gist
+	Columns string
+}
+
+var deferredTelemetryIndexes = []deferredIndex{
+	{Name: "idx_telemetry_time", Suffix: "time_idx", Method: "btree", Columns: "time"},
+	{Name: "idx_telemetry_stop_id", Suffix: "stop_id_idx", Method: "btree", Columns: "stop_id"},
+	// only present once the optional PostGIS migrations ran
+	{
+		Name:    "idx_telemetry_gnss_position",
+		Suffix:  "gnss_position_idx",
+		Method:  "gist",
+		Columns: "gnss_position",
+	},
+}
+
+// telemetryIndexes returns the deferred indexes whose column exists in this database
+func telemetryIndexes(ctx context.Context, pool *pgxpool.Pool) ([]deferredIndex, error) {
+	rows, err := pool.Query(ctx, `
+SELECT attname::text
+FROM pg_attribute
+WHERE attrelid = 'public.telemetry'::regclass
+  AND attnum > 0
+  AND NOT attisdropped`)
+	if err != nil {
+		return nil, fmt.Errorf("could not list telemetry columns: %v", err)
+	}
+	columns, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("could not list telemetry columns: %v", err)
+	}
+	var indexes []deferredIndex
+	for _, idx := range deferredTelemetryIndexes {
+		if slices.Contains(columns, idx.Columns) {
+			indexes = append(indexes, idx)
+		}
+	}
+	return indexes, nil
+}
+
+// BulkLoad defers index maintenance (and optionally WAL) for large loads
+type BulkLoad struct {
+	DeferIndexes bool
+	Unlogged     bool // set telemetry partitions UNLOGGED during the load
+}
+
+// Enabled reports whether a bulk load was requested
+func (b BulkLoad) Enabled() bool {
+	return b.DeferIndexes
+}
+
+// setPersistence switches all telemetry leaf partitions to LOGGED or UNLOGGED
+func setPersistence(ctx context.Context, pool *pgxpool.Pool, persistence string) error {
+	partitions, err := New(pool).ListTelemetryLeafPartitions(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list telemetry partitions: %v", err)
+	}
+	for _, partition := range partitions {
+		_, err := pool.Exec(ctx, fmt.Sprintf(
+			"ALTER TABLE %s SET %s",
+			pgx.Identifier{partition}.Sanitize(),
+			persistence,
+		))
+		if err != nil {
+			return fmt.Errorf("could not set %s %s: %v", partition, persistence, err)
+		}
+	}
+	return nil
+}
+
+// Prepare drops the deferred indexes and, if requested, stops WAL logging of
+// the telemetry partitions. Data in UNLOGGED partitions is lost on a crash.
+func (b BulkLoad) Prepare(ctx context.Context, pool *pgxpool.Pool) error {
+	indexes, err := telemetryIndexes(ctx, pool)
+	if err != nil {
+		return err
+	}
+	for _, idx := range indexes {
+		_, err := pool.Exec(ctx, "DROP INDEX IF EXISTS "+pgx.Identifier{idx.Name}.Sanitize())
+		if err != nil {
+			return fmt.Errorf("could not drop index %s: %v", idx.Name, err)
+		}
+	}
+	if b.Unlogged {
+		if err := setPersistence(ctx, pool, "UNLOGGED"); err != nil {
+			return err
+		}
+	}
+	slog.Info(
+		"bulk load prepared",
+		"deferred_indexes", len(indexes),
+		"unlogged", b.Unlogged,
+	)
+	return nil
+}
+
+// Finish restores logging, builds the deferred indexes and refreshes the
+// planner statistics. Each partition's index is built on its own connection
+// and attached to the parent index, which becomes valid once all are attached.
+// It is safe to run repeatedly.
+func (b BulkLoad) Finish(ctx context.Context, pool *pgxpool.Pool) error {
+	if b.Unlogged {
+		if err := setPersistence(ctx, pool, "LOGGED"); err != nil {
+			return err
+		}
+	}
+
+	partitions, err := New(pool).ListTelemetryPartitions(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list telemetry partitions: %v", err)
+	}
+	indexes, err := telemetryIndexes(ctx, pool)
+	if err != nil {
+		return err
+	}
+
+	type buildJob struct {
+		index     deferredIndex
+		partition string
+	}
+	var jobs []buildJob
+	for _, idx := range indexes {
+		_, err := pool.Exec(ctx, fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS %s ON ONLY telemetry USING %s (%s)",
+			pgx.Identifier{idx.Name}.Sanitize(),
+			idx.Method,
+			idx.Columns,
+		))
+		if err != nil {
+			return fmt.Errorf("could not create index %s: %v", idx.Name, err)
+		}
+		for _, partition := range partitions {
+			jobs = append(jobs, buildJob{index: idx, partition: partition})
+		}
+	}
+
+	slog.Info("building deferred indexes", "indexes", len(jobs))
+	queue := make(chan buildJob)
+	errs := make(chan error, len(jobs))
+	var wg sync.WaitGroup
+	for range WorkerCount {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				child := pgx.Identifier{job.partition + "_" + job.index.Suffix}.Sanitize()
+				_, err := pool.Exec(ctx, fmt.Sprintf(
+					"CREATE INDEX IF NOT EXISTS %s ON %s USING %s (%s)",
+					child,
+					pgx.Identifier{job.partition}.Sanitize(),
+					job.index.Method,
+					job.index.Columns,
+				))
+				if err == nil {
+					_, err = pool.Exec(ctx, fmt.Sprintf(
+						"ALTER INDEX %s ATTACH PARTITION %s",
+						pgx.Identifier{job.index.Name}.Sanitize(),
+						child,
+					))
+				}
+				if err != nil {
+					errs <- fmt.Errorf("index %s on %s: %v", job.index.Name, job.partition, err)
+				}
+			}
+		}()
+	}
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return fmt.Errorf("could not build deferred indexes: %v", err)
+	}
+
+	if _, err := pool.Exec(ctx, "ANALYZE telemetry"); err != nil {
+		return fmt.Errorf("could not analyze telemetry: %v", err)
+	}
+	slog.Info("bulk load finished", "partitions", len(partitions))
+	return nil
+}
@@ -0,0 +1,74 @@
+// Package postgres stores ZTBus data in PostgreSQL. It holds the sqlc
+// generated queries, the embedded migrations and the writers used to load
+// telemetry, partitions and indexes.
+package postgres
+
+import (
+	"embed"
+	"fmt"
+	"log/slog"
+	"net/url"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed migrations/*.sql
+var PostgresqlMigrations embed.FS
+
+// optional migrations that need the PostGIS extension on the server
+//
+//go:embed migrations/postgis/*.sql
+var PostgisMigrations embed.FS
+
+// Migrate applies the schema migrations
+func Migrate(connStr string) error {
+	d, err := iofs.New(PostgresqlMigrations, "migrations")
+	if err != nil {
+		return fmt.Errorf("failed to load embedded migrations: %w", err)
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", d, connStr)
+	if err != nil {
+		return fmt.Errorf("failed to create migrator: %w", err)
+	}
+
+	if err := m.Up(); err == migrate.ErrNoChange {
+		slog.Info("no migrations needed")
+	} else if err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	return nil
+}
+
+// MigratePostgis applies the optional PostGIS migrations. They are versioned
+// in their own table so they can be applied on top of any core version.
+func MigratePostgis(connStr string) error {
+	u, err := url.Parse(connStr)
+	if err != nil {
+		return fmt.Errorf("invalid connection string: %w", err)
+	}
+	query := u.Query()
+	query.Set("x-migrations-table", "schema_migrations_postgis")
+	u.RawQuery = query.Encode()
+
+	d, err := iofs.New(PostgisMigrations, "migrations/postgis")
+	if err != nil {
+		return fmt.Errorf("failed to load embedded PostGIS migrations: %w", err)
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", d, u.String())
+	if err != nil {
+		return fmt.Errorf("failed to create migrator: %w", err)
+	}
+
+	if err := m.Up(); err == migrate.ErrNoChange {
+		slog.Info("no PostGIS migrations needed")
+	} else if err != nil {
+		return fmt.Errorf("failed to run PostGIS migrations: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_sample_set_trips_trip_id;
+
+DROP TABLE IF EXISTS sample_set_trips;
+DROP TABLE IF EXISTS sample_sets;
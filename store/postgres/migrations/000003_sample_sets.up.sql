@@ -0,0 +1,21 @@
+-- Reproducible random subsets of trips
+CREATE TABLE sample_sets (
+    id SERIAL PRIMARY KEY,
+    name TEXT UNIQUE NOT NULL,
+    fraction DOUBLE PRECISION NOT NULL,
+    seed BIGINT NOT NULL,
+    stratified BOOLEAN NOT NULL DEFAULT FALSE,
+
+    -- trip filters that were active when the sample was drawn
+    filters TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Trips belonging to each sample set
+CREATE TABLE sample_set_trips (
+    sample_set_id INTEGER NOT NULL REFERENCES sample_sets(id) ON DELETE CASCADE,
+    trip_id INTEGER NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    PRIMARY KEY (sample_set_id, trip_id)
+);
+
+CREATE INDEX idx_sample_set_trips_trip_id ON sample_set_trips(trip_id);
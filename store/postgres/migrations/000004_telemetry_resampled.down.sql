@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_telemetry_resampled_time;
+
+DROP TABLE IF EXISTS telemetry_resampled;
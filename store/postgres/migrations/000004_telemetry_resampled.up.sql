@@ -0,0 +1,81 @@
+-- Telemetry aggregated into fixed windows at ingest time (see --resample)
+CREATE TABLE telemetry_resampled (
+  trip_id INTEGER NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+  time TIMESTAMP NOT NULL,
+  resolution_s INTEGER NOT NULL,
+  sample_count INTEGER NOT NULL,
+  itcs_bus_route_id INTEGER REFERENCES bus_routes(id) ON DELETE CASCADE,
+
+  -- Power and traction (window mean/min/max)
+  electric_power_demand_mean REAL,
+  electric_power_demand_min REAL,
+  electric_power_demand_max REAL,
+  temperature_ambient_mean REAL,
+  temperature_ambient_min REAL,
+  temperature_ambient_max REAL,
+  traction_brake_pressure_mean REAL,
+  traction_brake_pressure_min REAL,
+  traction_brake_pressure_max REAL,
+  traction_traction_force_mean REAL,
+  traction_traction_force_min REAL,
+  traction_traction_force_max REAL,
+
+  -- GNSS (window mean/min/max)
+  gnss_altitude_mean REAL,
+  gnss_altitude_min REAL,
+  gnss_altitude_max REAL,
+  gnss_latitude_mean REAL,
+  gnss_latitude_min REAL,
+  gnss_latitude_max REAL,
+  gnss_longitude_mean REAL,
+  gnss_longitude_min REAL,
+  gnss_longitude_max REAL,
+  gnss_course REAL, -- last value, headings are not averaged
+
+  -- ITCS (window mean/min/max)
+  itcs_number_of_passengers_mean REAL,
+  itcs_number_of_passengers_min REAL,
+  itcs_number_of_passengers_max REAL,
+
+  -- Odometry (window mean/min/max)
+  odometry_articulation_angle_mean REAL,
+  odometry_articulation_angle_min REAL,
+  odometry_articulation_angle_max REAL,
+  odometry_steering_angle_mean REAL,
+  odometry_steering_angle_min REAL,
+  odometry_steering_angle_max REAL,
+  odometry_vehicle_speed_mean REAL,
+  odometry_vehicle_speed_min REAL,
+  odometry_vehicle_speed_max REAL,
+  odometry_wheel_speed_fl_mean REAL,
+  odometry_wheel_speed_fl_min REAL,
+  odometry_wheel_speed_fl_max REAL,
+  odometry_wheel_speed_fr_mean REAL,
+  odometry_wheel_speed_fr_min REAL,
+  odometry_wheel_speed_fr_max REAL,
+  odometry_wheel_speed_ml_mean REAL,
+  odometry_wheel_speed_ml_min REAL,
+  odometry_wheel_speed_ml_max REAL,
+  odometry_wheel_speed_mr_mean REAL,
+  odometry_wheel_speed_mr_min REAL,
+  odometry_wheel_speed_mr_max REAL,
+  odometry_wheel_speed_rl_mean REAL,
+  odometry_wheel_speed_rl_min REAL,
+  odometry_wheel_speed_rl_max REAL,
+  odometry_wheel_speed_rr_mean REAL,
+  odometry_wheel_speed_rr_min REAL,
+  odometry_wheel_speed_rr_max REAL,
+
+  -- Statuses (fraction of the window the flag was set)
+  status_door_is_open_frac REAL,
+  status_grid_is_available_frac REAL,
+  status_halt_brake_is_active_frac REAL,
+  status_park_brake_is_active_frac REAL,
+
+  -- Last stop name seen in the window
+  itcs_stop_name TEXT,
+
+  PRIMARY KEY (trip_id, resolution_s, time)
+);
+
+CREATE INDEX idx_telemetry_resampled_time ON telemetry_resampled(resolution_s, time);
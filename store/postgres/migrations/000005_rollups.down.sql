@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_telemetry_minute_rollups_minute;
+
+DROP TABLE IF EXISTS trip_rollups;
+DROP TABLE IF EXISTS telemetry_minute_rollups;
@@ -0,0 +1,43 @@
+-- Per-minute telemetry aggregates, refreshed per trip after loading
+CREATE TABLE telemetry_minute_rollups (
+  trip_id INTEGER NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+  minute TIMESTAMP NOT NULL,
+  sample_count INTEGER NOT NULL,
+
+  -- Power
+  electric_power_demand_mean REAL,
+  electric_power_demand_min REAL,
+  electric_power_demand_max REAL,
+  energy_kwh REAL,
+
+  -- Motion
+  odometry_vehicle_speed_mean REAL,
+  odometry_vehicle_speed_max REAL,
+  distance_km REAL,
+
+  -- Context
+  itcs_number_of_passengers_mean REAL,
+  temperature_ambient_mean REAL,
+
+  -- Seconds spent with the status set
+  door_open_s REAL,
+  halt_brake_active_s REAL,
+
+  PRIMARY KEY (trip_id, minute)
+);
+
+-- Per-trip summary recomputed from telemetry (compare with the metaData.csv columns on trips)
+CREATE TABLE trip_rollups (
+  trip_id INTEGER PRIMARY KEY REFERENCES trips(id) ON DELETE CASCADE,
+  sample_count INTEGER NOT NULL,
+  first_time TIMESTAMP,
+  last_time TIMESTAMP,
+  energy_kwh REAL,
+  distance_km REAL,
+  door_open_s REAL,
+  halt_brake_active_s REAL,
+  park_brake_active_s REAL,
+  refreshed_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_telemetry_minute_rollups_minute ON telemetry_minute_rollups(minute);
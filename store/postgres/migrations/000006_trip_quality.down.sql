@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_trip_quality_inconsistent;
+
+DROP TABLE IF EXISTS trip_quality;
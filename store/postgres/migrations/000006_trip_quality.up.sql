@@ -0,0 +1,30 @@
+-- Cross-check of the metaData.csv summaries against values recomputed from telemetry
+CREATE TABLE trip_quality (
+  trip_id INTEGER PRIMARY KEY REFERENCES trips(id) ON DELETE CASCADE,
+  checked_at TIMESTAMP NOT NULL DEFAULT now(),
+  tolerance DOUBLE PRECISION NOT NULL,
+
+  -- false if any metric disagrees beyond the tolerance
+  metadata_consistent BOOLEAN NOT NULL,
+  mismatches TEXT[] NOT NULL DEFAULT '{}',
+
+  -- metadata value (_meta) and value recomputed from telemetry (_calc)
+  driven_distance_m_meta DOUBLE PRECISION,
+  driven_distance_m_calc DOUBLE PRECISION,
+  energy_consumption_j_meta DOUBLE PRECISION,
+  energy_consumption_j_calc DOUBLE PRECISION,
+  passengers_mean_meta DOUBLE PRECISION,
+  passengers_mean_calc DOUBLE PRECISION,
+  passengers_min_meta DOUBLE PRECISION,
+  passengers_min_calc DOUBLE PRECISION,
+  passengers_max_meta DOUBLE PRECISION,
+  passengers_max_calc DOUBLE PRECISION,
+  temperature_mean_meta DOUBLE PRECISION,
+  temperature_mean_calc DOUBLE PRECISION,
+  temperature_min_meta DOUBLE PRECISION,
+  temperature_min_calc DOUBLE PRECISION,
+  temperature_max_meta DOUBLE PRECISION,
+  temperature_max_calc DOUBLE PRECISION
+);
+
+CREATE INDEX idx_trip_quality_inconsistent ON trip_quality(trip_id) WHERE NOT metadata_consistent;
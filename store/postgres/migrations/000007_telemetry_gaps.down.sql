@@ -0,0 +1,10 @@
+ALTER TABLE trip_quality
+  DROP COLUMN IF EXISTS timeline_checked_at,
+  DROP COLUMN IF EXISTS duplicate_timestamps,
+  DROP COLUMN IF EXISTS backward_jumps,
+  DROP COLUMN IF EXISTS gap_count,
+  DROP COLUMN IF EXISTS missing_seconds,
+  DROP COLUMN IF EXISTS timeline_sorted,
+  DROP COLUMN IF EXISTS timeline_deduplicated;
+
+DROP TABLE IF EXISTS telemetry_gaps;
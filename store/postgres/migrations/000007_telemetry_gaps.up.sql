@@ -0,0 +1,20 @@
+-- Intervals without telemetry, bounded by the last sample before and the first after the gap
+CREATE TABLE telemetry_gaps (
+  trip_id INTEGER NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+  gap_start TIMESTAMP NOT NULL,
+  gap_end TIMESTAMP NOT NULL,
+  duration_s INTEGER NOT NULL,
+  PRIMARY KEY (trip_id, gap_start)
+);
+
+-- Timeline checks share trip_quality with the metadata cross-check, either may run alone
+ALTER TABLE trip_quality
+  ALTER COLUMN tolerance DROP NOT NULL,
+  ALTER COLUMN metadata_consistent DROP NOT NULL,
+  ADD COLUMN timeline_checked_at TIMESTAMP,
+  ADD COLUMN duplicate_timestamps INTEGER,
+  ADD COLUMN backward_jumps INTEGER,
+  ADD COLUMN gap_count INTEGER,
+  ADD COLUMN missing_seconds INTEGER,
+  ADD COLUMN timeline_sorted BOOLEAN,
+  ADD COLUMN timeline_deduplicated BOOLEAN;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS plausibility_violations;
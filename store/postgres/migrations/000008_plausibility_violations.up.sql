@@ -0,0 +1,12 @@
+-- Telemetry samples outside the plausibility rules, aggregated per trip, field and kind
+CREATE TABLE plausibility_violations (
+  trip_id INTEGER NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+  field TEXT NOT NULL,
+  kind TEXT NOT NULL, -- range, rate or deviation
+  violations INTEGER NOT NULL,
+  first_time TIMESTAMP NOT NULL,
+  last_time TIMESTAMP NOT NULL,
+  nulled BOOLEAN NOT NULL DEFAULT FALSE,
+  checked_at TIMESTAMP NOT NULL DEFAULT now(),
+  PRIMARY KEY (trip_id, field, kind)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS telemetry_staging;
+ALTER TABLE telemetry DROP CONSTRAINT IF EXISTS telemetry_trip_id_time_key;
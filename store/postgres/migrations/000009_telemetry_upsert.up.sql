@@ -0,0 +1,51 @@
+-- Keep the first copy of any sample loaded more than once before the constraint existed
+DELETE FROM telemetry a
+USING telemetry b
+WHERE a.trip_id = b.trip_id
+  AND a.time = b.time
+  AND a.id > b.id;
+
+-- One sample per trip and second. The constraint includes the partition column, so it is
+-- created on every existing partition and on those pg_partman creates later.
+ALTER TABLE telemetry ADD CONSTRAINT telemetry_trip_id_time_key UNIQUE (trip_id, time);
+
+-- Batches are copied here and upserted into telemetry within the same transaction. Rows never
+-- outlive their transaction, so concurrent loaders only ever see their own rows.
+CREATE UNLOGGED TABLE telemetry_staging (
+  seq BIGSERIAL,
+  trip_id INTEGER NOT NULL,
+  time TIMESTAMP NOT NULL,
+
+  electric_power_demand REAL,
+  temperature_ambient REAL,
+  traction_brake_pressure REAL,
+  traction_traction_force REAL,
+
+  -- GNSS
+  gnss_altitude REAL,
+  gnss_course REAL,
+  gnss_latitude REAL,
+  gnss_longitude REAL,
+
+  -- itcs
+  itcs_bus_route_id INTEGER,
+  itcs_number_of_passengers INTEGER,
+  itcs_stop_name TEXT,
+
+  -- Odometry
+  odometry_articulation_angle REAL,
+  odometry_steering_angle REAL,
+  odometry_vehicle_speed REAL,
+  odometry_wheel_speed_fl REAL,
+  odometry_wheel_speed_fr REAL,
+  odometry_wheel_speed_ml REAL,
+  odometry_wheel_speed_mr REAL,
+  odometry_wheel_speed_rl REAL,
+  odometry_wheel_speed_rr REAL,
+
+  -- Statuses
+  status_door_is_open BOOLEAN,
+  status_grid_is_available BOOLEAN,
+  status_halt_brake_is_active BOOLEAN,
+  status_park_brake_is_active BOOLEAN
+);
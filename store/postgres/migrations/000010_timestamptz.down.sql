@@ -0,0 +1,177 @@
+DROP VIEW IF EXISTS telemetry_local;
+DROP VIEW IF EXISTS trips_local;
+DROP TABLE IF EXISTS public_holidays;
+
+-- back to UTC wall clock TIMESTAMP values
+DELETE FROM public.part_config WHERE parent_table = 'public.telemetry';
+DROP TABLE IF EXISTS public.template_public_telemetry;
+
+ALTER TABLE telemetry RENAME TO telemetry_legacy;
+ALTER TABLE telemetry_legacy RENAME CONSTRAINT telemetry_pkey TO telemetry_legacy_pkey;
+ALTER TABLE telemetry_legacy
+  RENAME CONSTRAINT telemetry_trip_id_time_key TO telemetry_legacy_trip_id_time_key;
+
+-- free the partition names for pg_partman
+DO $$
+DECLARE
+  child regclass;
+BEGIN
+  FOR child IN
+    SELECT inhrelid::regclass FROM pg_inherits WHERE inhparent = 'telemetry_legacy'::regclass
+  LOOP
+    EXECUTE format('ALTER TABLE %s RENAME TO %I', child, 'legacy_' || child::text);
+  END LOOP;
+END $$;
+
+CREATE TABLE telemetry (
+  id BIGINT NOT NULL DEFAULT nextval('telemetry_id_seq'),
+  trip_id INTEGER NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+  time TIMESTAMP NOT NULL,
+
+  electric_power_demand REAL,
+  temperature_ambient REAL,
+  traction_brake_pressure REAL,
+  traction_traction_force REAL,
+
+  -- GNSS
+  gnss_altitude REAL,
+  gnss_course REAL,
+  gnss_latitude REAL,
+  gnss_longitude REAL,
+
+  -- itcs
+  itcs_bus_route_id INTEGER REFERENCES bus_routes(id) ON DELETE CASCADE,
+  itcs_number_of_passengers INTEGER,
+  itcs_stop_name TEXT,
+
+  -- Odometry
+  odometry_articulation_angle REAL,
+  odometry_steering_angle REAL,
+  odometry_vehicle_speed REAL,
+  odometry_wheel_speed_fl REAL,
+  odometry_wheel_speed_fr REAL,
+  odometry_wheel_speed_ml REAL,
+  odometry_wheel_speed_mr REAL,
+  odometry_wheel_speed_rl REAL,
+  odometry_wheel_speed_rr REAL,
+
+  -- Statuses
+  status_door_is_open BOOLEAN,
+  status_grid_is_available BOOLEAN,
+  status_halt_brake_is_active BOOLEAN,
+  status_park_brake_is_active BOOLEAN,
+
+  -- Primary key without partition column for pg_partman
+  PRIMARY KEY (id, time),
+  CONSTRAINT telemetry_trip_id_time_key UNIQUE (trip_id, time)
+) PARTITION BY RANGE (time);
+
+SELECT public.create_parent(
+    p_parent_table => 'public.telemetry',
+    p_control => 'time',
+    p_type => 'range',
+    p_interval => '1 month',
+    p_premake => 1,
+    p_start_partition => '2019-01-01'
+);
+
+INSERT INTO telemetry (
+  id,
+  trip_id,
+  time,
+  electric_power_demand,
+  temperature_ambient,
+  traction_brake_pressure,
+  traction_traction_force,
+  gnss_altitude,
+  gnss_course,
+  gnss_latitude,
+  gnss_longitude,
+  itcs_bus_route_id,
+  itcs_number_of_passengers,
+  itcs_stop_name,
+  odometry_articulation_angle,
+  odometry_steering_angle,
+  odometry_vehicle_speed,
+  odometry_wheel_speed_fl,
+  odometry_wheel_speed_fr,
+  odometry_wheel_speed_ml,
+  odometry_wheel_speed_mr,
+  odometry_wheel_speed_rl,
+  odometry_wheel_speed_rr,
+  status_door_is_open,
+  status_grid_is_available,
+  status_halt_brake_is_active,
+  status_park_brake_is_active
+)
+SELECT
+  id,
+  trip_id,
+  time AT TIME ZONE 'UTC',
+  electric_power_demand,
+  temperature_ambient,
+  traction_brake_pressure,
+  traction_traction_force,
+  gnss_altitude,
+  gnss_course,
+  gnss_latitude,
+  gnss_longitude,
+  itcs_bus_route_id,
+  itcs_number_of_passengers,
+  itcs_stop_name,
+  odometry_articulation_angle,
+  odometry_steering_angle,
+  odometry_vehicle_speed,
+  odometry_wheel_speed_fl,
+  odometry_wheel_speed_fr,
+  odometry_wheel_speed_ml,
+  odometry_wheel_speed_mr,
+  odometry_wheel_speed_rl,
+  odometry_wheel_speed_rr,
+  status_door_is_open,
+  status_grid_is_available,
+  status_halt_brake_is_active,
+  status_park_brake_is_active
+FROM telemetry_legacy;
+
+-- the id sequence outlives the legacy table
+ALTER SEQUENCE telemetry_id_seq OWNED BY telemetry.id;
+DROP TABLE telemetry_legacy;
+
+SELECT public.run_maintenance();
+
+ALTER TABLE plausibility_violations
+  ALTER COLUMN first_time TYPE TIMESTAMP USING first_time AT TIME ZONE 'UTC',
+  ALTER COLUMN last_time TYPE TIMESTAMP USING last_time AT TIME ZONE 'UTC',
+  ALTER COLUMN checked_at TYPE TIMESTAMP USING checked_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE telemetry_gaps
+  ALTER COLUMN gap_start TYPE TIMESTAMP USING gap_start AT TIME ZONE 'UTC',
+  ALTER COLUMN gap_end TYPE TIMESTAMP USING gap_end AT TIME ZONE 'UTC';
+
+ALTER TABLE trip_quality
+  ALTER COLUMN checked_at TYPE TIMESTAMP USING checked_at AT TIME ZONE current_setting('TimeZone'),
+  ALTER COLUMN timeline_checked_at TYPE TIMESTAMP
+    USING timeline_checked_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE trip_rollups
+  ALTER COLUMN first_time TYPE TIMESTAMP USING first_time AT TIME ZONE 'UTC',
+  ALTER COLUMN last_time TYPE TIMESTAMP USING last_time AT TIME ZONE 'UTC',
+  ALTER COLUMN refreshed_at TYPE TIMESTAMP
+    USING refreshed_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE telemetry_minute_rollups
+  ALTER COLUMN minute TYPE TIMESTAMP USING minute AT TIME ZONE 'UTC';
+
+ALTER TABLE telemetry_resampled
+  ALTER COLUMN time TYPE TIMESTAMP USING time AT TIME ZONE 'UTC';
+
+ALTER TABLE telemetry_staging
+  ALTER COLUMN time TYPE TIMESTAMP USING time AT TIME ZONE 'UTC';
+
+ALTER TABLE sample_sets
+  ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE trips
+  ALTER COLUMN start_time TYPE TIMESTAMP USING start_time AT TIME ZONE 'UTC',
+  ALTER COLUMN end_time TYPE TIMESTAMP USING end_time AT TIME ZONE 'UTC';
@@ -0,0 +1,276 @@
+-- Store instants as TIMESTAMPTZ. The loader used to write TIMESTAMP values as the wall clock of
+-- the loading machine's time zone. If that was not UTC, set it before migrating, e.g.
+--   ALTER DATABASE ztbus SET orca.legacy_timezone = 'Europe/Zurich';
+-- Columns filled by now() were written in the server's TimeZone and are converted with it.
+CREATE FUNCTION pg_temp.legacy_tz() RETURNS TEXT LANGUAGE sql AS $$
+  SELECT coalesce(nullif(current_setting('orca.legacy_timezone', true), ''), 'UTC')
+$$;
+
+ALTER TABLE trips
+  ALTER COLUMN start_time TYPE TIMESTAMPTZ USING start_time AT TIME ZONE pg_temp.legacy_tz(),
+  ALTER COLUMN end_time TYPE TIMESTAMPTZ USING end_time AT TIME ZONE pg_temp.legacy_tz();
+
+ALTER TABLE sample_sets
+  ALTER COLUMN created_at TYPE TIMESTAMPTZ
+    USING created_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE telemetry_staging
+  ALTER COLUMN time TYPE TIMESTAMPTZ USING time AT TIME ZONE pg_temp.legacy_tz();
+
+ALTER TABLE telemetry_resampled
+  ALTER COLUMN time TYPE TIMESTAMPTZ USING time AT TIME ZONE pg_temp.legacy_tz();
+
+ALTER TABLE telemetry_minute_rollups
+  ALTER COLUMN minute TYPE TIMESTAMPTZ USING minute AT TIME ZONE pg_temp.legacy_tz();
+
+ALTER TABLE trip_rollups
+  ALTER COLUMN first_time TYPE TIMESTAMPTZ USING first_time AT TIME ZONE pg_temp.legacy_tz(),
+  ALTER COLUMN last_time TYPE TIMESTAMPTZ USING last_time AT TIME ZONE pg_temp.legacy_tz(),
+  ALTER COLUMN refreshed_at TYPE TIMESTAMPTZ
+    USING refreshed_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE trip_quality
+  ALTER COLUMN checked_at TYPE TIMESTAMPTZ
+    USING checked_at AT TIME ZONE current_setting('TimeZone'),
+  ALTER COLUMN timeline_checked_at TYPE TIMESTAMPTZ
+    USING timeline_checked_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE telemetry_gaps
+  ALTER COLUMN gap_start TYPE TIMESTAMPTZ USING gap_start AT TIME ZONE pg_temp.legacy_tz(),
+  ALTER COLUMN gap_end TYPE TIMESTAMPTZ USING gap_end AT TIME ZONE pg_temp.legacy_tz();
+
+ALTER TABLE plausibility_violations
+  ALTER COLUMN first_time TYPE TIMESTAMPTZ USING first_time AT TIME ZONE pg_temp.legacy_tz(),
+  ALTER COLUMN last_time TYPE TIMESTAMPTZ USING last_time AT TIME ZONE pg_temp.legacy_tz(),
+  ALTER COLUMN checked_at TYPE TIMESTAMPTZ
+    USING checked_at AT TIME ZONE current_setting('TimeZone');
+
+-- telemetry.time is the partition key and cannot change type in place, so the table is
+-- rebuilt under pg_partman and the samples copied across
+DELETE FROM public.part_config WHERE parent_table = 'public.telemetry';
+DROP TABLE IF EXISTS public.template_public_telemetry;
+
+ALTER TABLE telemetry RENAME TO telemetry_legacy;
+ALTER TABLE telemetry_legacy RENAME CONSTRAINT telemetry_pkey TO telemetry_legacy_pkey;
+ALTER TABLE telemetry_legacy
+  RENAME CONSTRAINT telemetry_trip_id_time_key TO telemetry_legacy_trip_id_time_key;
+
+-- free the partition names for pg_partman
+DO $$
+DECLARE
+  child regclass;
+BEGIN
+  FOR child IN
+    SELECT inhrelid::regclass FROM pg_inherits WHERE inhparent = 'telemetry_legacy'::regclass
+  LOOP
+    EXECUTE format('ALTER TABLE %s RENAME TO %I', child, 'legacy_' || child::text);
+  END LOOP;
+END $$;
+
+CREATE TABLE telemetry (
+  id BIGINT NOT NULL DEFAULT nextval('telemetry_id_seq'),
+  trip_id INTEGER NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+  time TIMESTAMPTZ NOT NULL,
+
+  electric_power_demand REAL,
+  temperature_ambient REAL,
+  traction_brake_pressure REAL,
+  traction_traction_force REAL,
+
+  -- GNSS
+  gnss_altitude REAL,
+  gnss_course REAL,
+  gnss_latitude REAL,
+  gnss_longitude REAL,
+
+  -- itcs
+  itcs_bus_route_id INTEGER REFERENCES bus_routes(id) ON DELETE CASCADE,
+  itcs_number_of_passengers INTEGER,
+  itcs_stop_name TEXT,
+
+  -- Odometry
+  odometry_articulation_angle REAL,
+  odometry_steering_angle REAL,
+  odometry_vehicle_speed REAL,
+  odometry_wheel_speed_fl REAL,
+  odometry_wheel_speed_fr REAL,
+  odometry_wheel_speed_ml REAL,
+  odometry_wheel_speed_mr REAL,
+  odometry_wheel_speed_rl REAL,
+  odometry_wheel_speed_rr REAL,
+
+  -- Statuses
+  status_door_is_open BOOLEAN,
+  status_grid_is_available BOOLEAN,
+  status_halt_brake_is_active BOOLEAN,
+  status_park_brake_is_active BOOLEAN,
+
+  -- Primary key without partition column for pg_partman
+  PRIMARY KEY (id, time),
+  CONSTRAINT telemetry_trip_id_time_key UNIQUE (trip_id, time)
+) PARTITION BY RANGE (time);
+
+SELECT public.create_parent(
+    p_parent_table => 'public.telemetry',
+    p_control => 'time',
+    p_type => 'range',
+    p_interval => '1 month',
+    p_premake => 1,
+    p_start_partition => '2019-01-01'
+);
+
+INSERT INTO telemetry (
+  id,
+  trip_id,
+  time,
+  electric_power_demand,
+  temperature_ambient,
+  traction_brake_pressure,
+  traction_traction_force,
+  gnss_altitude,
+  gnss_course,
+  gnss_latitude,
+  gnss_longitude,
+  itcs_bus_route_id,
+  itcs_number_of_passengers,
+  itcs_stop_name,
+  odometry_articulation_angle,
+  odometry_steering_angle,
+  odometry_vehicle_speed,
+  odometry_wheel_speed_fl,
+  odometry_wheel_speed_fr,
+  odometry_wheel_speed_ml,
+  odometry_wheel_speed_mr,
+  odometry_wheel_speed_rl,
+  odometry_wheel_speed_rr,
+  status_door_is_open,
+  status_grid_is_available,
+  status_halt_brake_is_active,
+  status_park_brake_is_active
+)
+SELECT
+  id,
+  trip_id,
+  time AT TIME ZONE pg_temp.legacy_tz(),
+  electric_power_demand,
+  temperature_ambient,
+  traction_brake_pressure,
+  traction_traction_force,
+  gnss_altitude,
+  gnss_course,
+  gnss_latitude,
+  gnss_longitude,
+  itcs_bus_route_id,
+  itcs_number_of_passengers,
+  itcs_stop_name,
+  odometry_articulation_angle,
+  odometry_steering_angle,
+  odometry_vehicle_speed,
+  odometry_wheel_speed_fl,
+  odometry_wheel_speed_fr,
+  odometry_wheel_speed_ml,
+  odometry_wheel_speed_mr,
+  odometry_wheel_speed_rl,
+  odometry_wheel_speed_rr,
+  status_door_is_open,
+  status_grid_is_available,
+  status_halt_brake_is_active,
+  status_park_brake_is_active
+FROM telemetry_legacy;
+
+-- the id sequence outlives the legacy table
+ALTER SEQUENCE telemetry_id_seq OWNED BY telemetry.id;
+DROP TABLE telemetry_legacy;
+
+SELECT public.run_maintenance();
+
+-- Public holidays of the canton of Zurich
+CREATE TABLE public_holidays (
+  day DATE PRIMARY KEY,
+  name TEXT NOT NULL
+);
+
+INSERT INTO public_holidays (day, name) VALUES
+  ('2019-01-01', 'Neujahr'),
+  ('2019-01-02', 'Berchtoldstag'),
+  ('2019-04-19', 'Karfreitag'),
+  ('2019-04-22', 'Ostermontag'),
+  ('2019-05-01', 'Tag der Arbeit'),
+  ('2019-05-30', 'Auffahrt'),
+  ('2019-06-10', 'Pfingstmontag'),
+  ('2019-08-01', 'Bundesfeier'),
+  ('2019-12-25', 'Weihnachten'),
+  ('2019-12-26', 'Stephanstag'),
+  ('2020-01-01', 'Neujahr'),
+  ('2020-01-02', 'Berchtoldstag'),
+  ('2020-04-10', 'Karfreitag'),
+  ('2020-04-13', 'Ostermontag'),
+  ('2020-05-01', 'Tag der Arbeit'),
+  ('2020-05-21', 'Auffahrt'),
+  ('2020-06-01', 'Pfingstmontag'),
+  ('2020-08-01', 'Bundesfeier'),
+  ('2020-12-25', 'Weihnachten'),
+  ('2020-12-26', 'Stephanstag'),
+  ('2021-01-01', 'Neujahr'),
+  ('2021-01-02', 'Berchtoldstag'),
+  ('2021-04-02', 'Karfreitag'),
+  ('2021-04-05', 'Ostermontag'),
+  ('2021-05-01', 'Tag der Arbeit'),
+  ('2021-05-13', 'Auffahrt'),
+  ('2021-05-24', 'Pfingstmontag'),
+  ('2021-08-01', 'Bundesfeier'),
+  ('2021-12-25', 'Weihnachten'),
+  ('2021-12-26', 'Stephanstag'),
+  ('2022-01-01', 'Neujahr'),
+  ('2022-01-02', 'Berchtoldstag'),
+  ('2022-04-15', 'Karfreitag'),
+  ('2022-04-18', 'Ostermontag'),
+  ('2022-05-01', 'Tag der Arbeit'),
+  ('2022-05-26', 'Auffahrt'),
+  ('2022-06-06', 'Pfingstmontag'),
+  ('2022-08-01', 'Bundesfeier'),
+  ('2022-12-25', 'Weihnachten'),
+  ('2022-12-26', 'Stephanstag'),
+  ('2023-01-01', 'Neujahr'),
+  ('2023-01-02', 'Berchtoldstag'),
+  ('2023-04-07', 'Karfreitag'),
+  ('2023-04-10', 'Ostermontag'),
+  ('2023-05-01', 'Tag der Arbeit'),
+  ('2023-05-18', 'Auffahrt'),
+  ('2023-05-29', 'Pfingstmontag'),
+  ('2023-08-01', 'Bundesfeier'),
+  ('2023-12-25', 'Weihnachten'),
+  ('2023-12-26', 'Stephanstag');
+
+-- Zurich local time for service-day analysis. A service day runs until 04:00 the next
+-- morning, so late night trips count towards the day they started on.
+CREATE VIEW trips_local AS
+SELECT
+  t.id AS trip_id,
+  t.name,
+  (t.start_time AT TIME ZONE 'Europe/Zurich')::timestamp AS start_local,
+  (t.end_time AT TIME ZONE 'Europe/Zurich')::timestamp AS end_local,
+  s.service_day,
+  extract(hour FROM t.start_time AT TIME ZONE 'Europe/Zurich')::int AS start_hour,
+  extract(isodow FROM s.service_day)::int AS weekday,
+  h.name AS holiday
+FROM trips t
+CROSS JOIN LATERAL (
+  SELECT (t.start_time AT TIME ZONE 'Europe/Zurich' - INTERVAL '4 hours')::date AS service_day
+) s
+LEFT JOIN public_holidays h ON h.day = s.service_day;
+
+CREATE VIEW telemetry_local AS
+SELECT
+  t.trip_id,
+  t.time,
+  (t.time AT TIME ZONE 'Europe/Zurich')::timestamp AS local_time,
+  s.service_day,
+  extract(hour FROM t.time AT TIME ZONE 'Europe/Zurich')::int AS hour,
+  extract(isodow FROM s.service_day)::int AS weekday,
+  h.name AS holiday
+FROM telemetry t
+CROSS JOIN LATERAL (
+  SELECT (t.time AT TIME ZONE 'Europe/Zurich' - INTERVAL '4 hours')::date AS service_day
+) s
+LEFT JOIN public_holidays h ON h.day = s.service_day;
@@ -0,0 +1,6 @@
+-- Lookups by trip (GetTelemetryByTrip, ListTelemetryInRange) are served by the index behind
+-- telemetry_trip_id_time_key, which also covers trip_id alone. Only time needs its own index.
+-- See --bulk-load for deferring it during large loads.
+CREATE INDEX IF NOT EXISTS idx_telemetry_time ON telemetry(time);
+
+ANALYZE telemetry;
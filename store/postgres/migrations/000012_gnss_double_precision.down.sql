@@ -0,0 +1,9 @@
+ALTER TABLE telemetry_staging
+  ALTER COLUMN gnss_altitude TYPE REAL,
+  ALTER COLUMN gnss_latitude TYPE REAL,
+  ALTER COLUMN gnss_longitude TYPE REAL;
+
+ALTER TABLE telemetry
+  ALTER COLUMN gnss_altitude TYPE REAL,
+  ALTER COLUMN gnss_latitude TYPE REAL,
+  ALTER COLUMN gnss_longitude TYPE REAL;
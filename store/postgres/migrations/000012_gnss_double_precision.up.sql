@@ -0,0 +1,11 @@
+-- REAL keeps about 7 significant digits, which rounds radians to roughly 0.6 m on the ground.
+-- Positions are stored with full precision from here on.
+ALTER TABLE telemetry
+  ALTER COLUMN gnss_altitude TYPE DOUBLE PRECISION,
+  ALTER COLUMN gnss_latitude TYPE DOUBLE PRECISION,
+  ALTER COLUMN gnss_longitude TYPE DOUBLE PRECISION;
+
+ALTER TABLE telemetry_staging
+  ALTER COLUMN gnss_altitude TYPE DOUBLE PRECISION,
+  ALTER COLUMN gnss_latitude TYPE DOUBLE PRECISION,
+  ALTER COLUMN gnss_longitude TYPE DOUBLE PRECISION;
@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS idx_telemetry_stop_id;
+
+ALTER TABLE telemetry_staging
+  DROP COLUMN stop_id,
+  ADD COLUMN itcs_stop_name TEXT;
+
+ALTER TABLE telemetry ADD COLUMN itcs_stop_name TEXT;
+
+UPDATE telemetry t
+SET itcs_stop_name = s.name
+FROM stops s
+WHERE s.id = t.stop_id;
+
+ALTER TABLE telemetry DROP COLUMN stop_id;
+
+DROP TABLE IF EXISTS trip_stops;
+DROP TABLE IF EXISTS stops;
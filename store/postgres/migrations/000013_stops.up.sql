@@ -0,0 +1,105 @@
+-- One row per ITCS stop name. The position is the centroid of the GNSS fixes recorded with the
+-- doors open at the stop, in radians like the telemetry columns. All statistics are aggregated
+-- from trip_stops after each load.
+CREATE TABLE stops (
+  id SERIAL PRIMARY KEY,
+  name TEXT NOT NULL UNIQUE,
+  latitude DOUBLE PRECISION,
+  longitude DOUBLE PRECISION,
+  altitude DOUBLE PRECISION,
+  door_open_fixes INTEGER NOT NULL DEFAULT 0,
+  visits INTEGER NOT NULL DEFAULT 0,
+  trips INTEGER NOT NULL DEFAULT 0
+);
+
+-- Per trip contribution to the stop statistics, refreshed per trip after loading. A visit is a
+-- run of consecutive samples reporting the same stop.
+CREATE TABLE trip_stops (
+  trip_id INTEGER NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+  stop_id INTEGER NOT NULL REFERENCES stops(id) ON DELETE CASCADE,
+  visits INTEGER NOT NULL,
+  door_open_fixes INTEGER NOT NULL,
+  latitude DOUBLE PRECISION,
+  longitude DOUBLE PRECISION,
+  altitude DOUBLE PRECISION,
+  PRIMARY KEY (trip_id, stop_id)
+);
+
+CREATE INDEX idx_trip_stops_stop_id ON trip_stops(stop_id);
+
+-- ZTBus reports '-' while no stop is known
+INSERT INTO stops (name)
+SELECT DISTINCT itcs_stop_name
+FROM telemetry
+WHERE itcs_stop_name IS NOT NULL
+  AND itcs_stop_name <> '-'
+ORDER BY itcs_stop_name;
+
+ALTER TABLE telemetry ADD COLUMN stop_id INTEGER REFERENCES stops(id);
+
+UPDATE telemetry t
+SET stop_id = s.id
+FROM stops s
+WHERE s.name = t.itcs_stop_name;
+
+ALTER TABLE telemetry DROP COLUMN itcs_stop_name;
+
+ALTER TABLE telemetry_staging
+  DROP COLUMN itcs_stop_name,
+  ADD COLUMN stop_id INTEGER;
+
+UPDATE telemetry_resampled SET itcs_stop_name = NULL WHERE itcs_stop_name = '-';
+
+-- Per stop telemetry lookups, see --bulk-load for deferring it during large loads
+CREATE INDEX idx_telemetry_stop_id ON telemetry(stop_id);
+
+INSERT INTO trip_stops (trip_id, stop_id, visits, door_open_fixes, latitude, longitude, altitude)
+SELECT
+  trip_id,
+  stop_id,
+  count(*) FILTER (WHERE arrival),
+  count(*) FILTER (WHERE door_fix),
+  avg(gnss_latitude) FILTER (WHERE door_fix),
+  avg(gnss_longitude) FILTER (WHERE door_fix),
+  avg(gnss_altitude) FILTER (WHERE door_fix)
+FROM (
+  SELECT
+    trip_id,
+    stop_id,
+    gnss_latitude,
+    gnss_longitude,
+    gnss_altitude,
+    stop_id IS DISTINCT FROM lag(stop_id) OVER (PARTITION BY trip_id ORDER BY time) AS arrival,
+    coalesce(status_door_is_open, false)
+      AND gnss_latitude IS NOT NULL
+      AND gnss_longitude IS NOT NULL AS door_fix
+  FROM telemetry
+  WHERE stop_id IS NOT NULL
+) s
+GROUP BY trip_id, stop_id;
+
+UPDATE stops s
+SET
+  latitude = a.latitude,
+  longitude = a.longitude,
+  altitude = a.altitude,
+  door_open_fixes = a.door_open_fixes,
+  visits = a.visits,
+  trips = a.trips
+FROM (
+  SELECT
+    st.id,
+    sum(ts.latitude * ts.door_open_fixes) / nullif(sum(ts.door_open_fixes), 0) AS latitude,
+    sum(ts.longitude * ts.door_open_fixes) / nullif(sum(ts.door_open_fixes), 0) AS longitude,
+    sum(ts.altitude * ts.door_open_fixes)
+      / nullif(sum(ts.door_open_fixes) FILTER (WHERE ts.altitude IS NOT NULL), 0) AS altitude,
+    coalesce(sum(ts.door_open_fixes), 0) AS door_open_fixes,
+    coalesce(sum(ts.visits), 0) AS visits,
+    count(ts.trip_id) AS trips
+  FROM stops st
+  LEFT JOIN trip_stops ts ON ts.stop_id = st.id
+  GROUP BY st.id
+) a
+WHERE a.id = s.id;
+
+ANALYZE telemetry;
@@ -0,0 +1,19 @@
+-- Stops at which a trip opened its doors, derived from the telemetry while loading. Arrival and
+-- departure are the start and end of the standstill around the door openings.
+CREATE TABLE stop_events (
+  trip_id INTEGER NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+  seq INTEGER NOT NULL, -- order within the trip, from 1
+  stop_id INTEGER NOT NULL REFERENCES stops(id) ON DELETE CASCADE,
+  arrival_time TIMESTAMPTZ NOT NULL,
+  door_open_time TIMESTAMPTZ NOT NULL,
+  door_close_time TIMESTAMPTZ NOT NULL,
+  departure_time TIMESTAMPTZ, -- NULL if the telemetry ends before the bus moves again
+  dwell_s INTEGER, -- arrival to departure
+  door_open_s INTEGER NOT NULL,
+  passengers_before INTEGER,
+  passengers_after INTEGER,
+  passenger_change INTEGER,
+  PRIMARY KEY (trip_id, seq)
+);
+
+CREATE INDEX idx_stop_events_stop_id ON stop_events(stop_id, arrival_time);
@@ -0,0 +1,16 @@
+-- Consecutive stretches of a trip in the same phase, derived from the telemetry while loading
+CREATE TABLE trip_segments (
+  trip_id INTEGER NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+  seq INTEGER NOT NULL, -- order within the trip, from 1
+  phase TEXT NOT NULL CHECK (
+    phase IN ('driving_grid', 'driving_battery', 'dwelling', 'charging', 'idle')
+  ),
+  start_time TIMESTAMPTZ NOT NULL,
+  end_time TIMESTAMPTZ NOT NULL,
+  duration_s INTEGER NOT NULL,
+  energy_kwh REAL NOT NULL,
+  distance_km REAL NOT NULL,
+  PRIMARY KEY (trip_id, seq)
+);
+
+CREATE INDEX idx_trip_segments_phase ON trip_segments(phase, start_time);
@@ -0,0 +1,12 @@
+-- Energy features per trip, integrated from the telemetry while loading
+CREATE TABLE trip_energy (
+  trip_id INTEGER PRIMARY KEY REFERENCES trips(id) ON DELETE CASCADE,
+  energy_kwh DOUBLE PRECISION NOT NULL, -- consumed minus regenerated
+  consumed_kwh DOUBLE PRECISION NOT NULL,
+  regenerated_kwh DOUBLE PRECISION NOT NULL, -- while the power demand is negative
+  traction_kwh DOUBLE PRECISION NOT NULL, -- consumed while the traction force is positive
+  auxiliary_kwh DOUBLE PRECISION NOT NULL, -- consumed at any other time
+  distance_km DOUBLE PRECISION NOT NULL,
+  kwh_per_km DOUBLE PRECISION, -- NULL for trips that hardly moved
+  computed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS route_position_staging;
+
+ALTER TABLE telemetry
+  DROP COLUMN IF EXISTS route_distance_m,
+  DROP COLUMN IF EXISTS route_offset_m,
+  DROP COLUMN IF EXISTS route_position_estimated;
+
+DROP TABLE IF EXISTS route_shapes;
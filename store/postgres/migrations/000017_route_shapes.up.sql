@@ -0,0 +1,27 @@
+-- Canonical polyline per route, built from the GNSS traces of many trips. Vertices are in radians
+-- like the telemetry, distances_m is the distance along the shape at each vertex.
+CREATE TABLE route_shapes (
+  route_id INTEGER PRIMARY KEY REFERENCES bus_routes(id) ON DELETE CASCADE,
+  latitudes DOUBLE PRECISION[] NOT NULL,
+  longitudes DOUBLE PRECISION[] NOT NULL,
+  distances_m DOUBLE PRECISION[] NOT NULL,
+  length_m DOUBLE PRECISION NOT NULL,
+  trips INTEGER NOT NULL, -- trips the shape was built from
+  built_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Linear reference of each sample on its route's shape. Estimated positions were dead reckoned
+-- from speed and course where no usable GNSS fix was recorded, and have no offset.
+ALTER TABLE telemetry
+  ADD COLUMN route_distance_m REAL,
+  ADD COLUMN route_offset_m REAL,
+  ADD COLUMN route_position_estimated BOOLEAN;
+
+-- Snapped positions are copied here and applied to telemetry within the same transaction
+CREATE UNLOGGED TABLE route_position_staging (
+  trip_id INTEGER NOT NULL,
+  time TIMESTAMPTZ NOT NULL,
+  route_distance_m REAL,
+  route_offset_m REAL,
+  route_position_estimated BOOLEAN
+);
@@ -0,0 +1,8 @@
+DROP VIEW IF EXISTS stop_event_delays;
+DROP FUNCTION IF EXISTS gtfs_service_runs(TEXT, DATE);
+DROP TABLE IF EXISTS gtfs_calendar_dates;
+DROP TABLE IF EXISTS gtfs_calendar;
+DROP TABLE IF EXISTS gtfs_stop_times;
+DROP TABLE IF EXISTS gtfs_trips;
+DROP TABLE IF EXISTS gtfs_stops;
+DROP TABLE IF EXISTS gtfs_routes;
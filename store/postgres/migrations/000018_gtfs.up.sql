@@ -0,0 +1,130 @@
+-- Timetable imported from a GTFS feed with the import-gtfs command. Every import replaces the
+-- previous one. Identifiers are the feed's own, and routes and stops link to the ZTBus
+-- dimensions where their code or name matches.
+CREATE TABLE gtfs_routes (
+  id TEXT PRIMARY KEY,
+  agency_id TEXT,
+  short_name TEXT,
+  long_name TEXT,
+  route_type INTEGER NOT NULL,
+  bus_route_id INTEGER REFERENCES bus_routes(id) ON DELETE SET NULL
+);
+
+-- Positions are converted to radians like the telemetry columns
+CREATE TABLE gtfs_stops (
+  id TEXT PRIMARY KEY,
+  name TEXT NOT NULL,
+  latitude DOUBLE PRECISION,
+  longitude DOUBLE PRECISION,
+  location_type INTEGER NOT NULL DEFAULT 0,
+  parent_station TEXT,
+  stop_id INTEGER REFERENCES stops(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_gtfs_stops_stop_id ON gtfs_stops(stop_id);
+
+CREATE TABLE gtfs_trips (
+  id TEXT PRIMARY KEY,
+  gtfs_route_id TEXT NOT NULL REFERENCES gtfs_routes(id) ON DELETE CASCADE,
+  service_id TEXT NOT NULL,
+  headsign TEXT,
+  direction_id INTEGER
+);
+
+-- Times are seconds after midnight of the service day and exceed 24 hours for trips running past
+-- midnight, as in GTFS
+CREATE TABLE gtfs_stop_times (
+  gtfs_trip_id TEXT NOT NULL REFERENCES gtfs_trips(id) ON DELETE CASCADE,
+  stop_sequence INTEGER NOT NULL,
+  gtfs_stop_id TEXT NOT NULL REFERENCES gtfs_stops(id) ON DELETE CASCADE,
+  arrival_s INTEGER NOT NULL,
+  departure_s INTEGER NOT NULL,
+  PRIMARY KEY (gtfs_trip_id, stop_sequence)
+);
+
+CREATE INDEX idx_gtfs_stop_times_stop ON gtfs_stop_times(gtfs_stop_id, departure_s);
+
+CREATE TABLE gtfs_calendar (
+  service_id TEXT PRIMARY KEY,
+  monday BOOLEAN NOT NULL,
+  tuesday BOOLEAN NOT NULL,
+  wednesday BOOLEAN NOT NULL,
+  thursday BOOLEAN NOT NULL,
+  friday BOOLEAN NOT NULL,
+  saturday BOOLEAN NOT NULL,
+  sunday BOOLEAN NOT NULL,
+  start_date DATE NOT NULL,
+  end_date DATE NOT NULL
+);
+
+CREATE TABLE gtfs_calendar_dates (
+  service_id TEXT NOT NULL,
+  date DATE NOT NULL,
+  exception_type INTEGER NOT NULL, -- 1 added, 2 removed
+  PRIMARY KEY (service_id, date)
+);
+
+-- Whether a service runs on a day, from the weekly calendar and its exceptions
+CREATE FUNCTION gtfs_service_runs(service TEXT, day DATE) RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+  SELECT COALESCE(
+    (SELECT exception_type = 1 FROM gtfs_calendar_dates WHERE service_id = service AND date = day),
+    (
+      SELECT CASE extract(isodow FROM day)::int
+        WHEN 1 THEN monday
+        WHEN 2 THEN tuesday
+        WHEN 3 THEN wednesday
+        WHEN 4 THEN thursday
+        WHEN 5 THEN friday
+        WHEN 6 THEN saturday
+        ELSE sunday
+      END
+      FROM gtfs_calendar
+      WHERE service_id = service AND day BETWEEN start_date AND end_date
+    ),
+    false
+  )
+$$;
+
+-- Stop events next to the closest scheduled departure of their route at their stop within half
+-- an hour. Scheduled times are read as Zurich wall clock on the service day. Trips running past
+-- midnight belong to the previous service day, so both are searched. Delays are positive when the
+-- bus is late.
+CREATE VIEW stop_event_delays AS
+SELECT
+  e.trip_id,
+  e.seq,
+  e.stop_id,
+  e.arrival_time,
+  e.departure_time,
+  s.gtfs_trip_id,
+  s.scheduled_arrival,
+  s.scheduled_departure,
+  extract(epoch FROM e.arrival_time - s.scheduled_arrival)::int AS arrival_delay_s,
+  extract(epoch FROM e.departure_time - s.scheduled_departure)::int AS departure_delay_s
+FROM stop_events e
+JOIN trips t ON t.id = e.trip_id
+LEFT JOIN LATERAL (
+  SELECT
+    st.gtfs_trip_id,
+    (d.day + make_interval(secs => st.arrival_s)) AT TIME ZONE 'Europe/Zurich' AS scheduled_arrival,
+    sd.departure AS scheduled_departure
+  FROM gtfs_stops gs
+  JOIN gtfs_stop_times st ON st.gtfs_stop_id = gs.id
+  JOIN gtfs_trips gt ON gt.id = st.gtfs_trip_id
+  JOIN gtfs_routes gr ON gr.id = gt.gtfs_route_id
+  CROSS JOIN (VALUES
+    ((e.arrival_time AT TIME ZONE 'Europe/Zurich')::date - 1),
+    ((e.arrival_time AT TIME ZONE 'Europe/Zurich')::date)
+  ) AS d(day)
+  CROSS JOIN LATERAL (
+    SELECT (d.day + make_interval(secs => st.departure_s)) AT TIME ZONE 'Europe/Zurich' AS departure
+  ) sd
+  WHERE gs.stop_id = e.stop_id
+    AND gr.bus_route_id = t.route_id
+    AND sd.departure
+      BETWEEN e.arrival_time - INTERVAL '30 minutes' AND e.arrival_time + INTERVAL '30 minutes'
+    AND gtfs_service_runs(gt.service_id, d.day)
+  ORDER BY abs(extract(epoch FROM COALESCE(e.departure_time, e.arrival_time) - sd.departure))
+  LIMIT 1
+) s ON true;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS context_observations;
+DROP TABLE IF EXISTS context_sources;
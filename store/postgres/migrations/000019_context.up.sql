@@ -0,0 +1,20 @@
+-- External time series imported with the import-context command, e.g. weather, holidays or
+-- events. Every source keeps its own value columns.
+CREATE TABLE context_sources (
+  name TEXT PRIMARY KEY,
+  file TEXT NOT NULL, -- file the source was last imported from
+  value_columns TEXT[] NOT NULL,
+  observations INTEGER NOT NULL,
+  first_time TIMESTAMPTZ,
+  last_time TIMESTAMPTZ,
+  imported_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- One row per source and time. data maps each value column to a number, boolean or string, and
+-- leaves out empty cells. Rows hold until the next row of the same source.
+CREATE TABLE context_observations (
+  source TEXT NOT NULL REFERENCES context_sources(name) ON DELETE CASCADE,
+  time TIMESTAMPTZ NOT NULL,
+  data JSONB NOT NULL,
+  PRIMARY KEY (source, time)
+);
@@ -0,0 +1,44 @@
+DROP VIEW IF EXISTS stop_event_delays;
+
+-- Stop events next to the closest scheduled departure of their route at their stop within half
+-- an hour. Scheduled times are read as Zurich wall clock on the service day. Trips running past
+-- midnight belong to the previous service day, so both are searched. Delays are positive when the
+-- bus is late.
+CREATE VIEW stop_event_delays AS
+SELECT
+  e.trip_id,
+  e.seq,
+  e.stop_id,
+  e.arrival_time,
+  e.departure_time,
+  s.gtfs_trip_id,
+  s.scheduled_arrival,
+  s.scheduled_departure,
+  extract(epoch FROM e.arrival_time - s.scheduled_arrival)::int AS arrival_delay_s,
+  extract(epoch FROM e.departure_time - s.scheduled_departure)::int AS departure_delay_s
+FROM stop_events e
+JOIN trips t ON t.id = e.trip_id
+LEFT JOIN LATERAL (
+  SELECT
+    st.gtfs_trip_id,
+    (d.day + make_interval(secs => st.arrival_s)) AT TIME ZONE 'Europe/Zurich' AS scheduled_arrival,
+    sd.departure AS scheduled_departure
+  FROM gtfs_stops gs
+  JOIN gtfs_stop_times st ON st.gtfs_stop_id = gs.id
+  JOIN gtfs_trips gt ON gt.id = st.gtfs_trip_id
+  JOIN gtfs_routes gr ON gr.id = gt.gtfs_route_id
+  CROSS JOIN (VALUES
+    ((e.arrival_time AT TIME ZONE 'Europe/Zurich')::date - 1),
+    ((e.arrival_time AT TIME ZONE 'Europe/Zurich')::date)
+  ) AS d(day)
+  CROSS JOIN LATERAL (
+    SELECT (d.day + make_interval(secs => st.departure_s)) AT TIME ZONE 'Europe/Zurich' AS departure
+  ) sd
+  WHERE gs.stop_id = e.stop_id
+    AND gr.bus_route_id = t.route_id
+    AND sd.departure
+      BETWEEN e.arrival_time - INTERVAL '30 minutes' AND e.arrival_time + INTERVAL '30 minutes'
+    AND gtfs_service_runs(gt.service_id, d.day)
+  ORDER BY abs(extract(epoch FROM COALESCE(e.departure_time, e.arrival_time) - sd.departure))
+  LIMIT 1
+) s ON true;
@@ -0,0 +1,25 @@
+DROP VIEW IF EXISTS telemetry_with_route;
+
+ALTER TABLE telemetry
+  ADD COLUMN route_distance_m REAL,
+  ADD COLUMN route_offset_m REAL,
+  ADD COLUMN route_position_estimated BOOLEAN;
+
+UPDATE telemetry t
+SET
+  route_distance_m = p.route_distance_m,
+  route_offset_m = p.route_offset_m,
+  route_position_estimated = p.route_position_estimated
+FROM telemetry_route_positions p
+WHERE t.trip_id = p.trip_id
+  AND t.time = p.time;
+
+CREATE UNLOGGED TABLE route_position_staging (
+  trip_id INTEGER NOT NULL,
+  time TIMESTAMPTZ NOT NULL,
+  route_distance_m REAL,
+  route_offset_m REAL,
+  route_position_estimated BOOLEAN
+);
+
+DROP TABLE IF EXISTS telemetry_route_positions;
@@ -0,0 +1,66 @@
+-- Route positions move out of telemetry into a table of their own. Snapping a trip then replaces
+-- its rows here instead of updating every one of its telemetry rows, which rewrote the telemetry
+-- partitions and bloated them on every snap.
+CREATE TABLE telemetry_route_positions (
+  trip_id INTEGER NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+  time TIMESTAMPTZ NOT NULL,
+  route_distance_m REAL,
+  route_offset_m REAL,
+  route_position_estimated BOOLEAN,
+  PRIMARY KEY (trip_id, time)
+);
+
+INSERT INTO telemetry_route_positions (
+  trip_id,
+  time,
+  route_distance_m,
+  route_offset_m,
+  route_position_estimated
+)
+SELECT trip_id, time, route_distance_m, route_offset_m, route_position_estimated
+FROM telemetry
+WHERE route_position_estimated IS NOT NULL;
+
+DROP TABLE IF EXISTS route_position_staging;
+
+ALTER TABLE telemetry
+  DROP COLUMN IF EXISTS route_distance_m,
+  DROP COLUMN IF EXISTS route_offset_m,
+  DROP COLUMN IF EXISTS route_position_estimated;
+
+-- Telemetry with the route position of each sample, NULL where the trip was not snapped. The
+-- columns are listed so that the view does not pick up gnss_position from the PostGIS migrations.
+CREATE VIEW telemetry_with_route AS
+SELECT
+  t.id,
+  t.trip_id,
+  t.time,
+  t.electric_power_demand,
+  t.temperature_ambient,
+  t.traction_brake_pressure,
+  t.traction_traction_force,
+  t.gnss_altitude,
+  t.gnss_course,
+  t.gnss_latitude,
+  t.gnss_longitude,
+  t.itcs_bus_route_id,
+  t.itcs_number_of_passengers,
+  t.odometry_articulation_angle,
+  t.odometry_steering_angle,
+  t.odometry_vehicle_speed,
+  t.odometry_wheel_speed_fl,
+  t.odometry_wheel_speed_fr,
+  t.odometry_wheel_speed_ml,
+  t.odometry_wheel_speed_mr,
+  t.odometry_wheel_speed_rl,
+  t.odometry_wheel_speed_rr,
+  t.status_door_is_open,
+  t.status_grid_is_available,
+  t.status_halt_brake_is_active,
+  t.status_park_brake_is_active,
+  t.stop_id,
+  p.route_distance_m,
+  p.route_offset_m,
+  p.route_position_estimated
+FROM telemetry t
+LEFT JOIN telemetry_route_positions p ON p.trip_id = t.trip_id AND p.time = t.time;
@@ -0,0 +1,73 @@
+DROP VIEW IF EXISTS stop_event_delays;
+
+ALTER TABLE stop_events DROP COLUMN IF EXISTS run;
+
+-- Every ZTBus trip is matched to the GTFS trip of its route, running on its service day, that
+-- serves the most of its stop events with the least total deviation of their departures. Delays
+-- are then taken against that trip's stop times. Scheduled times are read as Zurich wall clock on
+-- the service day, and trips running past midnight belong to the previous service day. Delays are
+-- positive when the bus is late.
+CREATE VIEW stop_event_delays AS
+SELECT
+  e.trip_id,
+  e.seq,
+  e.stop_id,
+  e.arrival_time,
+  e.departure_time,
+  m.gtfs_trip_id,
+  s.scheduled_arrival,
+  s.scheduled_departure,
+  extract(epoch FROM e.arrival_time - s.scheduled_arrival)::int AS arrival_delay_s,
+  extract(epoch FROM e.departure_time - s.scheduled_departure)::int AS departure_delay_s
+FROM trips t
+JOIN stop_events e ON e.trip_id = t.id
+LEFT JOIN LATERAL (
+  SELECT c.gtfs_trip_id, c.day
+  FROM (
+    -- the closest departure of every stop event on each candidate trip, which may serve a stop
+    -- more than once
+    SELECT DISTINCT ON (ce.seq, st.gtfs_trip_id, d.day)
+      st.gtfs_trip_id,
+      d.day,
+      abs(extract(epoch FROM COALESCE(ce.departure_time, ce.arrival_time) - sd.departure))
+        AS deviation_s
+    FROM stop_events ce
+    JOIN gtfs_stops gs ON gs.stop_id = ce.stop_id
+    JOIN gtfs_stop_times st ON st.gtfs_stop_id = gs.id
+    JOIN gtfs_trips gt ON gt.id = st.gtfs_trip_id
+    JOIN gtfs_routes gr ON gr.id = gt.gtfs_route_id
+    CROSS JOIN (VALUES
+      ((t.start_time AT TIME ZONE 'Europe/Zurich')::date - 1),
+      ((t.start_time AT TIME ZONE 'Europe/Zurich')::date)
+    ) AS d(day)
+    CROSS JOIN LATERAL (
+      SELECT (d.day + make_interval(secs => st.departure_s)) AT TIME ZONE 'Europe/Zurich'
+        AS departure
+    ) sd
+    WHERE ce.trip_id = t.id
+      AND gr.bus_route_id = t.route_id
+      AND sd.departure
+        BETWEEN t.start_time - INTERVAL '1 hour' AND t.end_time + INTERVAL '1 hour'
+      AND gtfs_service_runs(gt.service_id, d.day)
+    ORDER BY ce.seq, st.gtfs_trip_id, d.day, deviation_s
+  ) c
+  GROUP BY c.gtfs_trip_id, c.day
+  ORDER BY count(*) DESC, sum(c.deviation_s), c.gtfs_trip_id
+  LIMIT 1
+) m ON true
+LEFT JOIN LATERAL (
+  SELECT
+    (m.day + make_interval(secs => st.arrival_s)) AT TIME ZONE 'Europe/Zurich'
+      AS scheduled_arrival,
+    sd.departure AS scheduled_departure
+  FROM gtfs_stop_times st
+  JOIN gtfs_stops gs ON gs.id = st.gtfs_stop_id
+  CROSS JOIN LATERAL (
+    SELECT (m.day + make_interval(secs => st.departure_s)) AT TIME ZONE 'Europe/Zurich'
+      AS departure
+  ) sd
+  WHERE st.gtfs_trip_id = m.gtfs_trip_id
+    AND gs.stop_id = e.stop_id
+  ORDER BY abs(extract(epoch FROM COALESCE(e.departure_time, e.arrival_time) - sd.departure))
+  LIMIT 1
+) s ON true;
@@ -0,0 +1,123 @@
+-- A ZTBus trip is a whole mission, usually several runs back and forth along the route, while a
+-- GTFS trip is a single run. Matching one GTFS trip to the whole mission measured every run but
+-- the best matching one against the wrong timetable, so stop events are numbered into runs and
+-- each run is matched on its own.
+DROP VIEW IF EXISTS stop_event_delays;
+
+ALTER TABLE stop_events ADD COLUMN run INTEGER NOT NULL DEFAULT 1;
+
+-- Number the stored events the way the loader does for new ones: a run ends when the bus comes
+-- back to a stop it already served on it, or after more than 15 minutes between leaving one stop
+-- and arriving at the next.
+WITH RECURSIVE walk AS (
+  SELECT
+    trip_id,
+    seq,
+    1 AS run,
+    ARRAY[stop_id] AS served,
+    COALESCE(departure_time, door_close_time) AS left_at
+  FROM stop_events
+  WHERE seq = 1
+  UNION ALL
+  SELECT
+    e.trip_id,
+    e.seq,
+    CASE WHEN b.new_run THEN w.run + 1 ELSE w.run END,
+    CASE WHEN b.new_run THEN ARRAY[e.stop_id] ELSE w.served || e.stop_id END,
+    COALESCE(e.departure_time, e.door_close_time)
+  FROM walk w
+  JOIN stop_events e ON e.trip_id = w.trip_id AND e.seq = w.seq + 1
+  CROSS JOIN LATERAL (
+    SELECT e.stop_id = ANY(w.served) OR e.arrival_time - w.left_at > INTERVAL '15 minutes'
+      AS new_run
+  ) b
+)
+UPDATE stop_events e
+SET run = w.run
+FROM walk w
+WHERE e.trip_id = w.trip_id
+  AND e.seq = w.seq
+  AND w.run > 1;
+
+ALTER TABLE stop_events ALTER COLUMN run DROP DEFAULT;
+
+-- Every run of a ZTBus trip is matched to the GTFS trip of its route, running on its service day,
+-- that serves the most of the run's stop events with the least total deviation of their
+-- departures. Delays are then taken against that trip's stop times. Scheduled times are read as
+-- Zurich wall clock on the service day, and trips running past midnight belong to the previous
+-- service day. Delays are positive when the bus is late.
+CREATE VIEW stop_event_delays AS
+SELECT
+  e.trip_id,
+  e.seq,
+  e.run,
+  e.stop_id,
+  e.arrival_time,
+  e.departure_time,
+  m.gtfs_trip_id,
+  s.scheduled_arrival,
+  s.scheduled_departure,
+  extract(epoch FROM e.arrival_time - s.scheduled_arrival)::int AS arrival_delay_s,
+  extract(epoch FROM e.departure_time - s.scheduled_departure)::int AS departure_delay_s
+FROM (
+  SELECT
+    trip_id,
+    run,
+    min(arrival_time) AS start_time,
+    max(COALESCE(departure_time, door_close_time)) AS end_time
+  FROM stop_events
+  GROUP BY trip_id, run
+) r
+JOIN trips t ON t.id = r.trip_id
+JOIN stop_events e ON e.trip_id = r.trip_id AND e.run = r.run
+LEFT JOIN LATERAL (
+  SELECT c.gtfs_trip_id, c.day
+  FROM (
+    -- the closest departure of every stop event of the run on each candidate trip, which may
+    -- serve a stop more than once
+    SELECT DISTINCT ON (ce.seq, st.gtfs_trip_id, d.day)
+      st.gtfs_trip_id,
+      d.day,
+      abs(extract(epoch FROM COALESCE(ce.departure_time, ce.arrival_time) - sd.departure))
+        AS deviation_s
+    FROM stop_events ce
+    JOIN gtfs_stops gs ON gs.stop_id = ce.stop_id
+    JOIN gtfs_stop_times st ON st.gtfs_stop_id = gs.id
+    JOIN gtfs_trips gt ON gt.id = st.gtfs_trip_id
+    JOIN gtfs_routes gr ON gr.id = gt.gtfs_route_id
+    CROSS JOIN (VALUES
+      ((r.start_time AT TIME ZONE 'Europe/Zurich')::date - 1),
+      ((r.start_time AT TIME ZONE 'Europe/Zurich')::date)
+    ) AS d(day)
+    CROSS JOIN LATERAL (
+      SELECT (d.day + make_interval(secs => st.departure_s)) AT TIME ZONE 'Europe/Zurich'
+        AS departure
+    ) sd
+    WHERE ce.trip_id = r.trip_id
+      AND ce.run = r.run
+      AND gr.bus_route_id = t.route_id
+      AND sd.departure
+        BETWEEN r.start_time - INTERVAL '1 hour' AND r.end_time + INTERVAL '1 hour'
+      AND gtfs_service_runs(gt.service_id, d.day)
+    ORDER BY ce.seq, st.gtfs_trip_id, d.day, deviation_s
+  ) c
+  GROUP BY c.gtfs_trip_id, c.day
+  ORDER BY count(*) DESC, sum(c.deviation_s), c.gtfs_trip_id
+  LIMIT 1
+) m ON true
+LEFT JOIN LATERAL (
+  SELECT
+    (m.day + make_interval(secs => st.arrival_s)) AT TIME ZONE 'Europe/Zurich'
+      AS scheduled_arrival,
+    sd.departure AS scheduled_departure
+  FROM gtfs_stop_times st
+  JOIN gtfs_stops gs ON gs.id = st.gtfs_stop_id
+  CROSS JOIN LATERAL (
+    SELECT (m.day + make_interval(secs => st.departure_s)) AT TIME ZONE 'Europe/Zurich'
+      AS departure
+  ) sd
+  WHERE st.gtfs_trip_id = m.gtfs_trip_id
+    AND gs.stop_id = e.stop_id
+  ORDER BY abs(extract(epoch FROM COALESCE(e.departure_time, e.arrival_time) - sd.departure))
+  LIMIT 1
+) s ON true;
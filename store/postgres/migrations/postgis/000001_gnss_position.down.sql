@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_telemetry_gnss_position;
+
+ALTER TABLE telemetry DROP COLUMN IF EXISTS gnss_position;
+
+-- the extension is left installed, other schemas may use it
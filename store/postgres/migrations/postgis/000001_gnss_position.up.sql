@@ -0,0 +1,20 @@
+CREATE EXTENSION IF NOT EXISTS postgis;
+
+-- GNSS positions as WGS 84 points with altitude. The raw columns are in radians, the point is in
+-- degrees. Being a stored generated column it is filled by every insert, including the staging
+-- upsert, and recomputed when a reload replaces a sample. Adding it rewrites the table.
+ALTER TABLE telemetry ADD COLUMN gnss_position geography(PointZ, 4326)
+GENERATED ALWAYS AS (
+  CASE
+    WHEN gnss_latitude IS NOT NULL AND gnss_longitude IS NOT NULL AND gnss_altitude IS NOT NULL
+    THEN ST_SetSRID(
+      ST_MakePoint(degrees(gnss_longitude), degrees(gnss_latitude), gnss_altitude),
+      4326
+    )::geography
+  END
+) STORED;
+
+-- Created on every partition, see --bulk-load for deferring it during large loads
+CREATE INDEX IF NOT EXISTS idx_telemetry_gnss_position ON telemetry USING GIST (gnss_position);
+
+ANALYZE telemetry;
@@ -0,0 +1,18 @@
+DROP INDEX IF EXISTS idx_telemetry_gnss_position;
+
+ALTER TABLE telemetry DROP COLUMN IF EXISTS gnss_position;
+
+ALTER TABLE telemetry ADD COLUMN gnss_position geography(PointZ, 4326)
+GENERATED ALWAYS AS (
+  CASE
+    WHEN gnss_latitude IS NOT NULL AND gnss_longitude IS NOT NULL AND gnss_altitude IS NOT NULL
+    THEN ST_SetSRID(
+      ST_MakePoint(degrees(gnss_longitude), degrees(gnss_latitude), gnss_altitude),
+      4326
+    )::geography
+  END
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_telemetry_gnss_position ON telemetry USING GIST (gnss_position);
+
+ANALYZE telemetry;
@@ -0,0 +1,18 @@
+-- gnss_position was NULL for every sample without an altitude. It is now a 2D point from latitude
+-- and longitude alone, the altitude stays in gnss_altitude. A generated column cannot change its
+-- expression, so it is dropped and added again, which rewrites the table.
+DROP INDEX IF EXISTS idx_telemetry_gnss_position;
+
+ALTER TABLE telemetry DROP COLUMN IF EXISTS gnss_position;
+
+ALTER TABLE telemetry ADD COLUMN gnss_position geography(Point, 4326)
+GENERATED ALWAYS AS (
+  CASE
+    WHEN gnss_latitude IS NOT NULL AND gnss_longitude IS NOT NULL
+    THEN ST_SetSRID(ST_MakePoint(degrees(gnss_longitude), degrees(gnss_latitude)), 4326)::geography
+  END
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_telemetry_gnss_position ON telemetry USING GIST (gnss_position);
+
+ANALYZE telemetry;
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PartitionIntervals are the partition intervals offered for telemetry, by the
+// date_trunc unit that aligns them
+var PartitionIntervals = map[string]string{
+	"day":   "1 day",
+	"week":  "1 week",
+	"month": "1 month",
+}
+
+// PartitionConfig is the pg_partman configuration of the telemetry table
+type PartitionConfig struct {
+	Interval           string // day, week or month
+	Premake            int
+	Retention          string // e.g. '2 years', empty keeps all partitions
+	RetentionKeepTable bool   // detach expired partitions rather than dropping them
+	SubPartitionTrips  int    // trip ids per sub-partition, 0 if not sub-partitioned
+}
+
+// TelemetryPartitionConfig reads the current configuration from pg_partman
+func TelemetryPartitionConfig(ctx context.Context, pool *pgxpool.Pool) (PartitionConfig, error) {
+	row, err := New(pool).GetTelemetryPartitionConfig(ctx)
+	if err != nil {
+		return PartitionConfig{}, fmt.Errorf("could not read partition config: %v", err)
+	}
+	c := PartitionConfig{
+		Interval:           row.PartitionInterval,
+		Premake:            int(row.Premake),
+		Retention:          row.Retention,
+		RetentionKeepTable: row.RetentionKeepTable,
+		SubPartitionTrips:  int(row.SubPartitionTrips),
+	}
+	if _, ok := PartitionIntervals[c.Interval]; !ok {
+		return PartitionConfig{}, fmt.Errorf("unsupported telemetry partition interval '%s'", c.Interval)
+	}
+	return c, nil
+}
+
+// tripTimeRange returns the earliest start and latest end of the trips
+func tripTimeRange(metadata []ztbus.Metadata) (time.Time, time.Time) {
+	start, end := metadata[0].StartTimeUnix, metadata[0].EndTimeUnix
+	for _, m := range metadata[1:] {
+		start = min(start, m.StartTimeUnix)
+		end = max(end, m.EndTimeUnix)
+	}
+	return time.Unix(int64(start), 0), time.Unix(int64(end), 0)
+}
+
+// PreparePartitions makes sure telemetry of the given trips lands in its own
+// partitions rather than the default one. Rows already stuck in the default
+// partition are moved first, as a partition cannot be created while the
+// default partition holds rows that belong to it.
+func PreparePartitions(ctx context.Context, pool *pgxpool.Pool, metadata []ztbus.Metadata) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+	q := New(pool)
+
+	if err := q.MoveTelemetryOutOfDefault(ctx); err != nil {
+		return fmt.Errorf("could not move telemetry out of the default partition: %v", err)
+	}
+
+	config, err := TelemetryPartitionConfig(ctx, pool)
+	if err != nil {
+		return err
+	}
+	start, end := tripTimeRange(metadata)
+	created, err := q.CreateTelemetryPartitions(ctx, CreateTelemetryPartitionsParams{
+		Unit:      config.Interval,
+		StartTime: pgtype.Timestamptz{Time: start, Valid: true},
+		EndTime:   pgtype.Timestamptz{Time: end, Valid: true},
+		Step:      PartitionIntervals[config.Interval],
+	})
+	if err != nil {
+		return fmt.Errorf("could not create telemetry partitions: %v", err)
+	}
+	slog.Info(
+		"prepared telemetry partitions",
+		"interval", config.Interval,
+		"from", start.UTC().Format(time.DateOnly),
+		"to", end.UTC().Format(time.DateOnly),
+		"created", created,
+	)
+	return nil
+}
+
+// FinishPartitions runs pg_partman maintenance and returns the resulting
+// partition layout. Row counts are planner estimates, refreshed by an ANALYZE.
+func FinishPartitions(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+) ([]ListTelemetryPartitionLayoutRow, error) {
+	q := New(pool)
+
+	// run_maintenance_proc commits as it goes, so it must not run in a transaction
+	if err := q.MakePartitions(ctx); err != nil {
+		return nil, fmt.Errorf("could not run partition maintenance: %v", err)
+	}
+	if err := q.AnalyzeTelemetry(ctx); err != nil {
+		return nil, fmt.Errorf("could not analyze telemetry: %v", err)
+	}
+
+	layout, err := q.ListTelemetryPartitionLayout(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list telemetry partitions: %v", err)
+	}
+	for _, p := range layout {
+		slog.Debug(
+			"telemetry partition",
+			"partition", p.Partition,
+			"bounds", p.Bounds,
+			"rows", p.EstimatedRows,
+		)
+	}
+	return layout, nil
+}
@@ -0,0 +1,62 @@
+-- Spatial queries on telemetry. They need the optional PostGIS migrations
+-- (--migrate --postgis). Coordinates are WGS 84 degrees, distances metres.
+
+-- name: ListTelemetryWithinRadius :many
+-- Samples within radius_m of a point, closest first within each trip.
+SELECT
+  trip_id,
+  time,
+  ST_Y(gnss_position::geometry)::float8 AS latitude,
+  ST_X(gnss_position::geometry)::float8 AS longitude,
+  gnss_altitude AS altitude,
+  ST_Distance(
+    gnss_position,
+    ST_SetSRID(ST_MakePoint(sqlc.arg('longitude')::float8, sqlc.arg('latitude')::float8), 4326)::geography
+  )::float8 AS distance_m
+FROM telemetry
+WHERE time >= sqlc.arg('start_time')
+  AND time < sqlc.arg('end_time')
+  AND ST_DWithin(
+    gnss_position,
+    ST_SetSRID(ST_MakePoint(sqlc.arg('longitude')::float8, sqlc.arg('latitude')::float8), 4326)::geography,
+    sqlc.arg('radius_m')::float8
+  )
+ORDER BY trip_id, distance_m;
+
+-- name: ListTelemetryInPolygon :many
+-- Samples inside a polygon given as WKT, e.g. 'POLYGON((8.53 47.37, ...))'.
+SELECT
+  trip_id,
+  time,
+  ST_Y(gnss_position::geometry)::float8 AS latitude,
+  ST_X(gnss_position::geometry)::float8 AS longitude,
+  gnss_altitude AS altitude
+FROM telemetry
+WHERE time >= sqlc.arg('start_time')
+  AND time < sqlc.arg('end_time')
+  AND ST_Covers(ST_GeogFromText(sqlc.arg('polygon')::text), gnss_position)
+ORDER BY trip_id, time;
+
+-- name: GetTripTrajectory :one
+-- The trip's GNSS track as a GeoJSON LineString. Samples without a fix are skipped.
+SELECT
+  trip_id,
+  count(*)::int AS points,
+  ST_AsGeoJSON(ST_MakeLine(gnss_position::geometry ORDER BY time))::text AS line_string
+FROM telemetry
+WHERE trip_id = sqlc.arg('trip_id')
+  AND gnss_position IS NOT NULL
+GROUP BY trip_id;
+
+-- name: ListTrajectoriesInRange :many
+-- GeoJSON LineStrings of every trip with GNSS samples in a time range.
+SELECT
+  trip_id,
+  count(*)::int AS points,
+  ST_AsGeoJSON(ST_MakeLine(gnss_position::geometry ORDER BY time))::text AS line_string
+FROM telemetry
+WHERE time >= sqlc.arg('start_time')
+  AND time < sqlc.arg('end_time')
+  AND gnss_position IS NOT NULL
+GROUP BY trip_id
+ORDER BY trip_id;
@@ -0,0 +1,1443 @@
+-- name: CreateBus :one
+INSERT INTO buses (bus_number)
+VALUES (sqlc.arg('bus_number'))
+ON CONFLICT (bus_number) DO UPDATE SET bus_number = EXCLUDED.bus_number
+RETURNING id;
+
+-- name: CreateRoute :one
+INSERT INTO bus_routes (route_code)
+VALUES (sqlc.arg('route_code'))
+ON CONFLICT (route_code) DO UPDATE SET route_code = EXCLUDED.route_code
+RETURNING id;
+
+-- name: CreateTrip :one
+-- Reloading a trip updates its metadata and keeps its id.
+INSERT INTO trips (
+  name,
+  bus_id,
+  route_id,
+  start_time,
+  end_time,
+  driven_distance_km,
+  energy_consumption_kWh,
+  itcs_passengers_mean,
+  itcs_passengers_min,
+  itcs_passengers_max,
+  grid_available_mean,
+  amb_temperature_mean,
+  amb_temperature_min,
+  amb_temperature_max
+)
+VALUES (
+  sqlc.arg('name'),
+  sqlc.arg('bus_id'),
+  sqlc.arg('route_id'),
+  sqlc.arg('start_time'),
+  sqlc.arg('end_time'),
+  sqlc.arg('driven_distance_km'),
+  sqlc.arg('energy_consumption_kWh'),
+  sqlc.arg('itcs_passengers_mean'),
+  sqlc.arg('itcs_passengers_min'),
+  sqlc.arg('itcs_passengers_max'),
+  sqlc.arg('grid_available_mean'),
+  sqlc.arg('temperature_mean'),
+  sqlc.arg('temperature_min'),
+  sqlc.arg('temperature_max')
+)
+ON CONFLICT (name) DO UPDATE SET
+  bus_id = EXCLUDED.bus_id,
+  route_id = EXCLUDED.route_id,
+  start_time = EXCLUDED.start_time,
+  end_time = EXCLUDED.end_time,
+  driven_distance_km = EXCLUDED.driven_distance_km,
+  energy_consumption_kWh = EXCLUDED.energy_consumption_kWh,
+  itcs_passengers_mean = EXCLUDED.itcs_passengers_mean,
+  itcs_passengers_min = EXCLUDED.itcs_passengers_min,
+  itcs_passengers_max = EXCLUDED.itcs_passengers_max,
+  grid_available_mean = EXCLUDED.grid_available_mean,
+  amb_temperature_mean = EXCLUDED.amb_temperature_mean,
+  amb_temperature_min = EXCLUDED.amb_temperature_min,
+  amb_temperature_max = EXCLUDED.amb_temperature_max
+RETURNING id;
+
+-- name: GetTripByName :one
+SELECT * FROM trips
+WHERE name = sqlc.arg('name');
+
+-- name: GetTripsByBus :many
+SELECT * FROM trips
+WHERE bus_id = sqlc.arg('bus_id')
+ORDER BY start_time;
+
+-- name: GetTripsByRoute :many
+SELECT * FROM trips
+WHERE route_id = sqlc.arg('route_id')
+ORDER BY start_time;
+
+-- name: GetTripsByTimeRange :many
+SELECT * FROM trips
+WHERE start_time >= sqlc.arg('start_time_from')
+  AND end_time <= sqlc.arg('end_time_to')
+ORDER BY start_time;
+
+-- name: UpdateTrip :exec
+UPDATE trips
+SET
+  bus_id = sqlc.arg('bus_id'),
+  route_id = sqlc.arg('route_id'),
+  start_time = sqlc.arg('start_time'),
+  end_time = sqlc.arg('end_time'),
+  driven_distance_km = sqlc.arg('driven_distance_km'),
+  energy_consumption_kWh = sqlc.arg('energy_consumption_kWh'),
+  itcs_passengers_mean = sqlc.arg('itcs_passengers_mean'),
+  itcs_passengers_min = sqlc.arg('itcs_passengers_min'),
+  itcs_passengers_max = sqlc.arg('itcs_passengers_max'),
+  grid_available_mean = sqlc.arg('grid_available_mean'),
+  amb_temperature_mean = sqlc.arg('temperature_mean'),
+  amb_temperature_min = sqlc.arg('temperature_min'),
+  amb_temperature_max = sqlc.arg('temperature_max')
+WHERE name = sqlc.arg('name');
+
+-- name: DeleteTripByName :exec
+DELETE FROM trips
+WHERE name = sqlc.arg('name');
+
+-- name: ListAllTrips :many
+SELECT * FROM trips
+ORDER BY start_time;
+
+-- name: ListBuses :many
+SELECT * FROM buses
+ORDER BY bus_number;
+
+-- name: ListRoutes :many
+SELECT * FROM bus_routes
+ORDER BY route_code;
+
+-- name: GetBusRouteId :one
+SELECT id FROM bus_routes WHERE route_code = sqlc.arg('bus_route');
+
+-- name: GetBusRouteIdFromTripId :one
+SELECT route_id FROM trips WHERE id = sqlc.arg('trip_id');
+
+-- name: InsertTelemetry :copyfrom
+INSERT INTO telemetry (
+  trip_id,
+  time,
+  electric_power_demand,
+  gnss_altitude,
+  gnss_course,
+  gnss_latitude,
+  gnss_longitude,
+  itcs_bus_route_id,
+  itcs_number_of_passengers,
+  odometry_articulation_angle,
+  odometry_steering_angle,
+  odometry_vehicle_speed,
+  odometry_wheel_speed_fl,
+  odometry_wheel_speed_fr,
+  odometry_wheel_speed_ml,
+  odometry_wheel_speed_mr,
+  odometry_wheel_speed_rl,
+  odometry_wheel_speed_rr,
+  status_door_is_open,
+  status_grid_is_available,
+  status_halt_brake_is_active,
+  status_park_brake_is_active,
+  stop_id,
+  temperature_ambient,
+  traction_brake_pressure,
+  traction_traction_force
+)
+VALUES (
+  sqlc.arg('trip_id'),
+  sqlc.arg('time'),
+  sqlc.arg('electric_power_demand'),
+  sqlc.arg('gnss_altitude'),
+  sqlc.arg('gnss_course'),
+  sqlc.arg('gnss_latitude'),
+  sqlc.arg('gnss_longitude'),
+  sqlc.arg('bus_route_id'),
+  sqlc.arg('itcs_number_of_passengers'),
+  sqlc.arg('odometry_articulation_angle'),
+  sqlc.arg('odometry_steering_angle'),
+  sqlc.arg('odometry_vehicle_speed'),
+  sqlc.arg('odometry_wheel_speed_fl'),
+  sqlc.arg('odometry_wheel_speed_fr'),
+  sqlc.arg('odometry_wheel_speed_ml'),
+  sqlc.arg('odometry_wheel_speed_mr'),
+  sqlc.arg('odometry_wheel_speed_rl'),
+  sqlc.arg('odometry_wheel_speed_rr'),
+  sqlc.arg('status_door_is_open'),
+  sqlc.arg('status_grid_is_available'),
+  sqlc.arg('status_halt_brake_is_active'),
+  sqlc.arg('status_park_brake_is_active'),
+  sqlc.arg('stop_id'),
+  sqlc.arg('temperature_ambient'),
+  sqlc.arg('traction_brake_pressure'),
+  sqlc.arg('traction_traction_force')
+);
+
+-- name: StageTelemetry :copyfrom
+INSERT INTO telemetry_staging (
+  trip_id,
+  time,
+  electric_power_demand,
+  gnss_altitude,
+  gnss_course,
+  gnss_latitude,
+  gnss_longitude,
+  itcs_bus_route_id,
+  itcs_number_of_passengers,
+  odometry_articulation_angle,
+  odometry_steering_angle,
+  odometry_vehicle_speed,
+  odometry_wheel_speed_fl,
+  odometry_wheel_speed_fr,
+  odometry_wheel_speed_ml,
+  odometry_wheel_speed_mr,
+  odometry_wheel_speed_rl,
+  odometry_wheel_speed_rr,
+  status_door_is_open,
+  status_grid_is_available,
+  status_halt_brake_is_active,
+  status_park_brake_is_active,
+  stop_id,
+  temperature_ambient,
+  traction_brake_pressure,
+  traction_traction_force
+)
+VALUES (
+  sqlc.arg('trip_id'),
+  sqlc.arg('time'),
+  sqlc.arg('electric_power_demand'),
+  sqlc.arg('gnss_altitude'),
+  sqlc.arg('gnss_course'),
+  sqlc.arg('gnss_latitude'),
+  sqlc.arg('gnss_longitude'),
+  sqlc.arg('bus_route_id'),
+  sqlc.arg('itcs_number_of_passengers'),
+  sqlc.arg('odometry_articulation_angle'),
+  sqlc.arg('odometry_steering_angle'),
+  sqlc.arg('odometry_vehicle_speed'),
+  sqlc.arg('odometry_wheel_speed_fl'),
+  sqlc.arg('odometry_wheel_speed_fr'),
+  sqlc.arg('odometry_wheel_speed_ml'),
+  sqlc.arg('odometry_wheel_speed_mr'),
+  sqlc.arg('odometry_wheel_speed_rl'),
+  sqlc.arg('odometry_wheel_speed_rr'),
+  sqlc.arg('status_door_is_open'),
+  sqlc.arg('status_grid_is_available'),
+  sqlc.arg('status_halt_brake_is_active'),
+  sqlc.arg('status_park_brake_is_active'),
+  sqlc.arg('stop_id'),
+  sqlc.arg('temperature_ambient'),
+  sqlc.arg('traction_brake_pressure'),
+  sqlc.arg('traction_traction_force')
+);
+
+-- name: UpsertStagedTelemetry :execrows
+-- Moves this transaction's staged rows into telemetry. Within a batch the first
+-- sample of a second wins, a reload replaces the stored sample and clears its
+-- route position until the trip is snapped again.
+WITH staged AS (
+  DELETE FROM telemetry_staging
+  RETURNING *
+), cleared AS (
+  DELETE FROM telemetry_route_positions p
+  USING staged s
+  WHERE p.trip_id = s.trip_id
+    AND p.time = s.time
+)
+INSERT INTO telemetry (
+  trip_id,
+  time,
+  electric_power_demand,
+  gnss_altitude,
+  gnss_course,
+  gnss_latitude,
+  gnss_longitude,
+  itcs_bus_route_id,
+  itcs_number_of_passengers,
+  odometry_articulation_angle,
+  odometry_steering_angle,
+  odometry_vehicle_speed,
+  odometry_wheel_speed_fl,
+  odometry_wheel_speed_fr,
+  odometry_wheel_speed_ml,
+  odometry_wheel_speed_mr,
+  odometry_wheel_speed_rl,
+  odometry_wheel_speed_rr,
+  status_door_is_open,
+  status_grid_is_available,
+  status_halt_brake_is_active,
+  status_park_brake_is_active,
+  stop_id,
+  temperature_ambient,
+  traction_brake_pressure,
+  traction_traction_force
+)
+SELECT DISTINCT ON (trip_id, time)
+  trip_id,
+  time,
+  electric_power_demand,
+  gnss_altitude,
+  gnss_course,
+  gnss_latitude,
+  gnss_longitude,
+  itcs_bus_route_id,
+  itcs_number_of_passengers,
+  odometry_articulation_angle,
+  odometry_steering_angle,
+  odometry_vehicle_speed,
+  odometry_wheel_speed_fl,
+  odometry_wheel_speed_fr,
+  odometry_wheel_speed_ml,
+  odometry_wheel_speed_mr,
+  odometry_wheel_speed_rl,
+  odometry_wheel_speed_rr,
+  status_door_is_open,
+  status_grid_is_available,
+  status_halt_brake_is_active,
+  status_park_brake_is_active,
+  stop_id,
+  temperature_ambient,
+  traction_brake_pressure,
+  traction_traction_force
+FROM staged
+ORDER BY trip_id, time, seq
+ON CONFLICT (trip_id, time) DO UPDATE SET
+  electric_power_demand = EXCLUDED.electric_power_demand,
+  gnss_altitude = EXCLUDED.gnss_altitude,
+  gnss_course = EXCLUDED.gnss_course,
+  gnss_latitude = EXCLUDED.gnss_latitude,
+  gnss_longitude = EXCLUDED.gnss_longitude,
+  itcs_bus_route_id = EXCLUDED.itcs_bus_route_id,
+  itcs_number_of_passengers = EXCLUDED.itcs_number_of_passengers,
+  odometry_articulation_angle = EXCLUDED.odometry_articulation_angle,
+  odometry_steering_angle = EXCLUDED.odometry_steering_angle,
+  odometry_vehicle_speed = EXCLUDED.odometry_vehicle_speed,
+  odometry_wheel_speed_fl = EXCLUDED.odometry_wheel_speed_fl,
+  odometry_wheel_speed_fr = EXCLUDED.odometry_wheel_speed_fr,
+  odometry_wheel_speed_ml = EXCLUDED.odometry_wheel_speed_ml,
+  odometry_wheel_speed_mr = EXCLUDED.odometry_wheel_speed_mr,
+  odometry_wheel_speed_rl = EXCLUDED.odometry_wheel_speed_rl,
+  odometry_wheel_speed_rr = EXCLUDED.odometry_wheel_speed_rr,
+  status_door_is_open = EXCLUDED.status_door_is_open,
+  status_grid_is_available = EXCLUDED.status_grid_is_available,
+  status_halt_brake_is_active = EXCLUDED.status_halt_brake_is_active,
+  status_park_brake_is_active = EXCLUDED.status_park_brake_is_active,
+  stop_id = EXCLUDED.stop_id,
+  temperature_ambient = EXCLUDED.temperature_ambient,
+  traction_brake_pressure = EXCLUDED.traction_brake_pressure,
+  traction_traction_force = EXCLUDED.traction_traction_force;
+
+-- name: GetTelemetryByTrip :many
+SELECT * FROM telemetry_with_route
+WHERE trip_id = sqlc.arg('trip_id')
+ORDER BY time;
+
+-- name: ListTelemetryInRange :many
+SELECT * FROM telemetry_with_route
+WHERE trip_id = sqlc.arg('trip_id')
+  AND time >= sqlc.arg('start_time')
+  AND time <= sqlc.arg('end_time')
+ORDER BY time;
+
+-- name: ListTelemetryPage :many
+-- One page of the telemetry in a time range, in (trip_id, time) order after a keyset cursor. Pass
+-- 0 and -infinity for the first page. Bus and route are optional filters.
+SELECT * FROM telemetry_with_route
+WHERE time >= sqlc.arg('start_time')
+  AND time < sqlc.arg('end_time')
+  AND (trip_id, time) > (sqlc.arg('after_trip_id')::int, sqlc.arg('after_time')::timestamptz)
+  AND (
+    sqlc.narg('bus_id')::int IS NULL
+    OR trip_id IN (SELECT id FROM trips WHERE bus_id = sqlc.narg('bus_id'))
+  )
+  AND (
+    sqlc.narg('route_id')::int IS NULL
+    OR trip_id IN (SELECT id FROM trips WHERE route_id = sqlc.narg('route_id'))
+  )
+ORDER BY trip_id, time
+LIMIT sqlc.arg('page_size');
+
+-- name: DeleteTelemetryByTrip :exec
+DELETE FROM telemetry
+WHERE trip_id = sqlc.arg('trip_id');
+
+-- name: MakePartitions :exec
+CALL public.run_maintenance_proc();
+
+-- name: MoveTelemetryOutOfDefault :exec
+-- Moves rows from the default partition into their own partitions, creating
+-- them as needed. Commits internally, so it cannot run inside a transaction.
+CALL public.partition_data_proc('public.telemetry', p_wait => 0, p_quiet => true);
+
+-- name: CreateTelemetryPartitions :one
+-- Creates every partition between start_time and end_time. unit aligns the
+-- first partition (day, week or month) and step is the partition interval.
+-- Existing partitions are skipped.
+SELECT public.create_partition_time(
+  'public.telemetry',
+  ARRAY(
+    SELECT generate_series(
+      date_trunc(sqlc.arg('unit')::text, sqlc.arg('start_time')::timestamptz),
+      sqlc.arg('end_time')::timestamptz,
+      sqlc.arg('step')::text::interval
+    )
+  )
+) AS created;
+
+-- name: AnalyzeTelemetry :exec
+ANALYZE telemetry;
+
+
+-- name: CreateSampleSet :one
+INSERT INTO sample_sets (name, fraction, seed, stratified, filters)
+VALUES (
+  sqlc.arg('name'),
+  sqlc.arg('fraction'),
+  sqlc.arg('seed'),
+  sqlc.arg('stratified'),
+  sqlc.arg('filters')
+)
+ON CONFLICT (name) DO UPDATE
+SET
+  fraction = EXCLUDED.fraction,
+  seed = EXCLUDED.seed,
+  stratified = EXCLUDED.stratified,
+  filters = EXCLUDED.filters,
+  created_at = now()
+RETURNING id;
+
+-- name: ClearSampleSetTrips :exec
+DELETE FROM sample_set_trips
+WHERE sample_set_id = sqlc.arg('sample_set_id');
+
+-- name: AddTripToSampleSet :exec
+INSERT INTO sample_set_trips (sample_set_id, trip_id)
+VALUES (sqlc.arg('sample_set_id'), sqlc.arg('trip_id'))
+ON CONFLICT DO NOTHING;
+
+-- name: ListSampleSets :many
+SELECT * FROM sample_sets
+ORDER BY name;
+
+-- name: GetTripsBySampleSet :many
+SELECT trips.* FROM trips
+JOIN sample_set_trips ON sample_set_trips.trip_id = trips.id
+JOIN sample_sets ON sample_sets.id = sample_set_trips.sample_set_id
+WHERE sample_sets.name = sqlc.arg('sample_set_name')
+ORDER BY trips.start_time;
+
+-- name: InsertResampledTelemetry :copyfrom
+INSERT INTO telemetry_resampled (
+  trip_id,
+  time,
+  resolution_s,
+  sample_count,
+  itcs_bus_route_id,
+  electric_power_demand_mean,
+  electric_power_demand_min,
+  electric_power_demand_max,
+  temperature_ambient_mean,
+  temperature_ambient_min,
+  temperature_ambient_max,
+  traction_brake_pressure_mean,
+  traction_brake_pressure_min,
+  traction_brake_pressure_max,
+  traction_traction_force_mean,
+  traction_traction_force_min,
+  traction_traction_force_max,
+  gnss_altitude_mean,
+  gnss_altitude_min,
+  gnss_altitude_max,
+  gnss_latitude_mean,
+  gnss_latitude_min,
+  gnss_latitude_max,
+  gnss_longitude_mean,
+  gnss_longitude_min,
+  gnss_longitude_max,
+  itcs_number_of_passengers_mean,
+  itcs_number_of_passengers_min,
+  itcs_number_of_passengers_max,
+  odometry_articulation_angle_mean,
+  odometry_articulation_angle_min,
+  odometry_articulation_angle_max,
+  odometry_steering_angle_mean,
+  odometry_steering_angle_min,
+  odometry_steering_angle_max,
+  odometry_vehicle_speed_mean,
+  odometry_vehicle_speed_min,
+  odometry_vehicle_speed_max,
+  odometry_wheel_speed_fl_mean,
+  odometry_wheel_speed_fl_min,
+  odometry_wheel_speed_fl_max,
+  odometry_wheel_speed_fr_mean,
+  odometry_wheel_speed_fr_min,
+  odometry_wheel_speed_fr_max,
+  odometry_wheel_speed_ml_mean,
+  odometry_wheel_speed_ml_min,
+  odometry_wheel_speed_ml_max,
+  odometry_wheel_speed_mr_mean,
+  odometry_wheel_speed_mr_min,
+  odometry_wheel_speed_mr_max,
+  odometry_wheel_speed_rl_mean,
+  odometry_wheel_speed_rl_min,
+  odometry_wheel_speed_rl_max,
+  odometry_wheel_speed_rr_mean,
+  odometry_wheel_speed_rr_min,
+  odometry_wheel_speed_rr_max,
+  gnss_course,
+  status_door_is_open_frac,
+  status_grid_is_available_frac,
+  status_halt_brake_is_active_frac,
+  status_park_brake_is_active_frac,
+  itcs_stop_name
+)
+VALUES (
+  sqlc.arg('trip_id'),
+  sqlc.arg('time'),
+  sqlc.arg('resolution_s'),
+  sqlc.arg('sample_count'),
+  sqlc.arg('itcs_bus_route_id'),
+  sqlc.arg('electric_power_demand_mean'),
+  sqlc.arg('electric_power_demand_min'),
+  sqlc.arg('electric_power_demand_max'),
+  sqlc.arg('temperature_ambient_mean'),
+  sqlc.arg('temperature_ambient_min'),
+  sqlc.arg('temperature_ambient_max'),
+  sqlc.arg('traction_brake_pressure_mean'),
+  sqlc.arg('traction_brake_pressure_min'),
+  sqlc.arg('traction_brake_pressure_max'),
+  sqlc.arg('traction_traction_force_mean'),
+  sqlc.arg('traction_traction_force_min'),
+  sqlc.arg('traction_traction_force_max'),
+  sqlc.arg('gnss_altitude_mean'),
+  sqlc.arg('gnss_altitude_min'),
+  sqlc.arg('gnss_altitude_max'),
+  sqlc.arg('gnss_latitude_mean'),
+  sqlc.arg('gnss_latitude_min'),
+  sqlc.arg('gnss_latitude_max'),
+  sqlc.arg('gnss_longitude_mean'),
+  sqlc.arg('gnss_longitude_min'),
+  sqlc.arg('gnss_longitude_max'),
+  sqlc.arg('itcs_number_of_passengers_mean'),
+  sqlc.arg('itcs_number_of_passengers_min'),
+  sqlc.arg('itcs_number_of_passengers_max'),
+  sqlc.arg('odometry_articulation_angle_mean'),
+  sqlc.arg('odometry_articulation_angle_min'),
+  sqlc.arg('odometry_articulation_angle_max'),
+  sqlc.arg('odometry_steering_angle_mean'),
+  sqlc.arg('odometry_steering_angle_min'),
+  sqlc.arg('odometry_steering_angle_max'),
+  sqlc.arg('odometry_vehicle_speed_mean'),
+  sqlc.arg('odometry_vehicle_speed_min'),
+  sqlc.arg('odometry_vehicle_speed_max'),
+  sqlc.arg('odometry_wheel_speed_fl_mean'),
+  sqlc.arg('odometry_wheel_speed_fl_min'),
+  sqlc.arg('odometry_wheel_speed_fl_max'),
+  sqlc.arg('odometry_wheel_speed_fr_mean'),
+  sqlc.arg('odometry_wheel_speed_fr_min'),
+  sqlc.arg('odometry_wheel_speed_fr_max'),
+  sqlc.arg('odometry_wheel_speed_ml_mean'),
+  sqlc.arg('odometry_wheel_speed_ml_min'),
+  sqlc.arg('odometry_wheel_speed_ml_max'),
+  sqlc.arg('odometry_wheel_speed_mr_mean'),
+  sqlc.arg('odometry_wheel_speed_mr_min'),
+  sqlc.arg('odometry_wheel_speed_mr_max'),
+  sqlc.arg('odometry_wheel_speed_rl_mean'),
+  sqlc.arg('odometry_wheel_speed_rl_min'),
+  sqlc.arg('odometry_wheel_speed_rl_max'),
+  sqlc.arg('odometry_wheel_speed_rr_mean'),
+  sqlc.arg('odometry_wheel_speed_rr_min'),
+  sqlc.arg('odometry_wheel_speed_rr_max'),
+  sqlc.arg('gnss_course'),
+  sqlc.arg('status_door_is_open_frac'),
+  sqlc.arg('status_grid_is_available_frac'),
+  sqlc.arg('status_halt_brake_is_active_frac'),
+  sqlc.arg('status_park_brake_is_active_frac'),
+  sqlc.arg('itcs_stop_name')
+);
+
+-- name: GetResampledTelemetryByTrip :many
+SELECT * FROM telemetry_resampled
+WHERE trip_id = sqlc.arg('trip_id')
+  AND resolution_s = sqlc.arg('resolution_s')
+ORDER BY time;
+
+-- name: DeleteResampledTelemetryByTrip :exec
+DELETE FROM telemetry_resampled
+WHERE trip_id = sqlc.arg('trip_id')
+  AND resolution_s = sqlc.arg('resolution_s');
+
+-- name: DeleteTelemetryMinuteRollups :exec
+DELETE FROM telemetry_minute_rollups
+WHERE trip_id = sqlc.arg('trip_id');
+
+-- name: RefreshTelemetryMinuteRollups :exec
+-- Each sample is integrated over the time until the next one. Gaps longer
+-- than a minute are capped so that missing data does not inflate the totals.
+INSERT INTO telemetry_minute_rollups (
+  trip_id,
+  minute,
+  sample_count,
+  electric_power_demand_mean,
+  electric_power_demand_min,
+  electric_power_demand_max,
+  energy_kwh,
+  odometry_vehicle_speed_mean,
+  odometry_vehicle_speed_max,
+  distance_km,
+  itcs_number_of_passengers_mean,
+  temperature_ambient_mean,
+  door_open_s,
+  halt_brake_active_s
+)
+SELECT
+  s.trip_id,
+  date_trunc('minute', s.time),
+  count(*),
+  avg(s.electric_power_demand),
+  min(s.electric_power_demand),
+  max(s.electric_power_demand),
+  sum(s.electric_power_demand * s.dt) / 3600000.0,
+  avg(s.odometry_vehicle_speed),
+  max(s.odometry_vehicle_speed),
+  sum(s.odometry_vehicle_speed * s.dt) / 1000.0,
+  avg(s.itcs_number_of_passengers),
+  avg(s.temperature_ambient),
+  coalesce(sum(s.dt) FILTER (WHERE s.status_door_is_open), 0),
+  coalesce(sum(s.dt) FILTER (WHERE s.status_halt_brake_is_active), 0)
+FROM (
+  SELECT
+    t.*,
+    LEAST(coalesce(EXTRACT(EPOCH FROM LEAD(t.time) OVER (ORDER BY t.time) - t.time), 0), 60) AS dt
+  FROM telemetry t
+  WHERE t.trip_id = sqlc.arg('trip_id')
+) s
+GROUP BY s.trip_id, date_trunc('minute', s.time);
+
+-- name: RefreshTripRollup :exec
+-- Uses the same integration as RefreshTelemetryMinuteRollups.
+INSERT INTO trip_rollups (
+  trip_id,
+  sample_count,
+  first_time,
+  last_time,
+  energy_kwh,
+  distance_km,
+  door_open_s,
+  halt_brake_active_s,
+  park_brake_active_s
+)
+SELECT
+  trips.id,
+  count(s.time),
+  min(s.time),
+  max(s.time),
+  sum(s.electric_power_demand * s.dt) / 3600000.0,
+  sum(s.odometry_vehicle_speed * s.dt) / 1000.0,
+  coalesce(sum(s.dt) FILTER (WHERE s.status_door_is_open), 0),
+  coalesce(sum(s.dt) FILTER (WHERE s.status_halt_brake_is_active), 0),
+  coalesce(sum(s.dt) FILTER (WHERE s.status_park_brake_is_active), 0)
+FROM trips
+LEFT JOIN (
+  SELECT
+    t.*,
+    LEAST(coalesce(EXTRACT(EPOCH FROM LEAD(t.time) OVER (ORDER BY t.time) - t.time), 0), 60) AS dt
+  FROM telemetry t
+  WHERE t.trip_id = sqlc.arg('trip_id')
+) s ON s.trip_id = trips.id
+WHERE trips.id = sqlc.arg('trip_id')
+GROUP BY trips.id
+ON CONFLICT (trip_id) DO UPDATE
+SET
+  sample_count = EXCLUDED.sample_count,
+  first_time = EXCLUDED.first_time,
+  last_time = EXCLUDED.last_time,
+  energy_kwh = EXCLUDED.energy_kwh,
+  distance_km = EXCLUDED.distance_km,
+  door_open_s = EXCLUDED.door_open_s,
+  halt_brake_active_s = EXCLUDED.halt_brake_active_s,
+  park_brake_active_s = EXCLUDED.park_brake_active_s,
+  refreshed_at = now();
+
+-- name: ListTripsWithoutRollup :many
+SELECT trips.id FROM trips
+LEFT JOIN trip_rollups ON trip_rollups.trip_id = trips.id
+WHERE trip_rollups.trip_id IS NULL
+ORDER BY trips.id;
+
+-- name: GetTripRollup :one
+SELECT * FROM trip_rollups
+WHERE trip_id = sqlc.arg('trip_id');
+
+-- name: ListTripRollups :many
+SELECT * FROM trip_rollups
+ORDER BY trip_id;
+
+-- name: GetTelemetryMinuteRollupsByTrip :many
+SELECT * FROM telemetry_minute_rollups
+WHERE trip_id = sqlc.arg('trip_id')
+ORDER BY minute;
+
+-- name: ListTelemetryMinuteRollupsInRange :many
+SELECT * FROM telemetry_minute_rollups
+WHERE minute >= sqlc.arg('start_time')
+  AND minute <= sqlc.arg('end_time')
+ORDER BY trip_id, minute;
+
+-- name: UpsertTripQuality :exec
+INSERT INTO trip_quality (
+  trip_id,
+  tolerance,
+  metadata_consistent,
+  mismatches,
+  driven_distance_m_meta,
+  driven_distance_m_calc,
+  energy_consumption_j_meta,
+  energy_consumption_j_calc,
+  passengers_mean_meta,
+  passengers_mean_calc,
+  passengers_min_meta,
+  passengers_min_calc,
+  passengers_max_meta,
+  passengers_max_calc,
+  temperature_mean_meta,
+  temperature_mean_calc,
+  temperature_min_meta,
+  temperature_min_calc,
+  temperature_max_meta,
+  temperature_max_calc
+)
+VALUES (
+  sqlc.arg('trip_id'),
+  sqlc.arg('tolerance'),
+  sqlc.arg('metadata_consistent'),
+  sqlc.arg('mismatches'),
+  sqlc.arg('driven_distance_m_meta'),
+  sqlc.arg('driven_distance_m_calc'),
+  sqlc.arg('energy_consumption_j_meta'),
+  sqlc.arg('energy_consumption_j_calc'),
+  sqlc.arg('passengers_mean_meta'),
+  sqlc.arg('passengers_mean_calc'),
+  sqlc.arg('passengers_min_meta'),
+  sqlc.arg('passengers_min_calc'),
+  sqlc.arg('passengers_max_meta'),
+  sqlc.arg('passengers_max_calc'),
+  sqlc.arg('temperature_mean_meta'),
+  sqlc.arg('temperature_mean_calc'),
+  sqlc.arg('temperature_min_meta'),
+  sqlc.arg('temperature_min_calc'),
+  sqlc.arg('temperature_max_meta'),
+  sqlc.arg('temperature_max_calc')
+)
+ON CONFLICT (trip_id) DO UPDATE
+SET
+  checked_at = now(),
+  tolerance = EXCLUDED.tolerance,
+  metadata_consistent = EXCLUDED.metadata_consistent,
+  mismatches = EXCLUDED.mismatches,
+  driven_distance_m_meta = EXCLUDED.driven_distance_m_meta,
+  driven_distance_m_calc = EXCLUDED.driven_distance_m_calc,
+  energy_consumption_j_meta = EXCLUDED.energy_consumption_j_meta,
+  energy_consumption_j_calc = EXCLUDED.energy_consumption_j_calc,
+  passengers_mean_meta = EXCLUDED.passengers_mean_meta,
+  passengers_mean_calc = EXCLUDED.passengers_mean_calc,
+  passengers_min_meta = EXCLUDED.passengers_min_meta,
+  passengers_min_calc = EXCLUDED.passengers_min_calc,
+  passengers_max_meta = EXCLUDED.passengers_max_meta,
+  passengers_max_calc = EXCLUDED.passengers_max_calc,
+  temperature_mean_meta = EXCLUDED.temperature_mean_meta,
+  temperature_mean_calc = EXCLUDED.temperature_mean_calc,
+  temperature_min_meta = EXCLUDED.temperature_min_meta,
+  temperature_min_calc = EXCLUDED.temperature_min_calc,
+  temperature_max_meta = EXCLUDED.temperature_max_meta,
+  temperature_max_calc = EXCLUDED.temperature_max_calc;
+
+-- name: GetTripQuality :one
+SELECT * FROM trip_quality
+WHERE trip_id = sqlc.arg('trip_id');
+
+-- name: ListInconsistentTrips :many
+SELECT trips.name, trip_quality.* FROM trip_quality
+JOIN trips ON trips.id = trip_quality.trip_id
+WHERE NOT trip_quality.metadata_consistent
+ORDER BY trips.start_time;
+
+-- name: UpsertTripTimelineQuality :exec
+INSERT INTO trip_quality (
+  trip_id,
+  timeline_checked_at,
+  duplicate_timestamps,
+  backward_jumps,
+  gap_count,
+  missing_seconds,
+  timeline_sorted,
+  timeline_deduplicated
+)
+VALUES (
+  sqlc.arg('trip_id'),
+  now(),
+  sqlc.arg('duplicate_timestamps'),
+  sqlc.arg('backward_jumps'),
+  sqlc.arg('gap_count'),
+  sqlc.arg('missing_seconds'),
+  sqlc.arg('timeline_sorted'),
+  sqlc.arg('timeline_deduplicated')
+)
+ON CONFLICT (trip_id) DO UPDATE
+SET
+  timeline_checked_at = now(),
+  duplicate_timestamps = EXCLUDED.duplicate_timestamps,
+  backward_jumps = EXCLUDED.backward_jumps,
+  gap_count = EXCLUDED.gap_count,
+  missing_seconds = EXCLUDED.missing_seconds,
+  timeline_sorted = EXCLUDED.timeline_sorted,
+  timeline_deduplicated = EXCLUDED.timeline_deduplicated;
+
+-- name: DeleteTelemetryGapsByTrip :exec
+DELETE FROM telemetry_gaps
+WHERE trip_id = sqlc.arg('trip_id');
+
+-- name: InsertTelemetryGaps :copyfrom
+INSERT INTO telemetry_gaps (
+  trip_id,
+  gap_start,
+  gap_end,
+  duration_s
+)
+VALUES (
+  sqlc.arg('trip_id'),
+  sqlc.arg('gap_start'),
+  sqlc.arg('gap_end'),
+  sqlc.arg('duration_s')
+);
+
+-- name: GetTelemetryGapsByTrip :many
+SELECT * FROM telemetry_gaps
+WHERE trip_id = sqlc.arg('trip_id')
+ORDER BY gap_start;
+
+-- name: ListTelemetryGapsInRange :many
+SELECT * FROM telemetry_gaps
+WHERE gap_end >= sqlc.arg('start_time')
+  AND gap_start <= sqlc.arg('end_time')
+ORDER BY trip_id, gap_start;
+
+-- name: DeletePlausibilityViolationsByTrip :exec
+DELETE FROM plausibility_violations
+WHERE trip_id = sqlc.arg('trip_id');
+
+-- name: InsertPlausibilityViolations :copyfrom
+INSERT INTO plausibility_violations (
+  trip_id,
+  field,
+  kind,
+  violations,
+  first_time,
+  last_time,
+  nulled
+)
+VALUES (
+  sqlc.arg('trip_id'),
+  sqlc.arg('field'),
+  sqlc.arg('kind'),
+  sqlc.arg('violations'),
+  sqlc.arg('first_time'),
+  sqlc.arg('last_time'),
+  sqlc.arg('nulled')
+);
+
+-- name: GetPlausibilityViolationsByTrip :many
+SELECT * FROM plausibility_violations
+WHERE trip_id = sqlc.arg('trip_id')
+ORDER BY field, kind;
+
+-- name: SummarisePlausibilityViolations :many
+SELECT
+  field,
+  kind,
+  count(DISTINCT trip_id)::int AS trips,
+  sum(violations)::bigint AS violations
+FROM plausibility_violations
+GROUP BY field, kind
+ORDER BY field, kind;
+
+-- name: ListTripsByServiceDay :many
+SELECT * FROM trips_local
+WHERE service_day = sqlc.arg('service_day')
+ORDER BY start_local;
+
+-- name: GetTelemetryLocalByTrip :many
+SELECT * FROM telemetry_local
+WHERE trip_id = sqlc.arg('trip_id')
+ORDER BY time;
+
+-- name: ListPublicHolidays :many
+SELECT * FROM public_holidays
+ORDER BY day;
+
+-- name: ListTelemetryPartitions :many
+SELECT c.relname::text AS partition
+FROM pg_inherits i
+JOIN pg_class c ON c.oid = i.inhrelid
+WHERE i.inhparent = 'public.telemetry'::regclass
+ORDER BY c.relname;
+
+-- name: ListTelemetryPartitionLayout :many
+SELECT
+  c.relname::text AS partition,
+  pg_get_expr(c.relpartbound, c.oid)::text AS bounds,
+  greatest(c.reltuples, 0)::bigint AS estimated_rows
+FROM pg_inherits i
+JOIN pg_class c ON c.oid = i.inhrelid
+WHERE i.inhparent = 'public.telemetry'::regclass
+ORDER BY c.relname;
+
+-- name: ListTelemetryLeafPartitions :many
+-- The partitions that hold rows, below any trip sub-partitioning.
+SELECT c.relname::text AS partition
+FROM pg_partition_tree('public.telemetry') t
+JOIN pg_class c ON c.oid = t.relid
+WHERE t.isleaf
+ORDER BY c.relname;
+
+-- name: ListTelemetrySubParents :many
+-- The time partitions that pg_partman sub-partitions by trip. The default
+-- partition is never a sub-parent.
+SELECT c.parent_table
+FROM public.part_config c
+JOIN pg_inherits i ON i.inhrelid = to_regclass(c.parent_table)
+WHERE i.inhparent = 'public.telemetry'::regclass
+  AND EXISTS (
+    SELECT 1 FROM public.part_config_sub s WHERE s.sub_parent = 'public.telemetry'
+  )
+ORDER BY c.parent_table;
+
+-- name: GetTelemetryPartitionConfig :one
+-- The pg_partman configuration of telemetry. The offered intervals are named by
+-- the date_trunc unit that aligns them.
+SELECT
+  (CASE c.partition_interval::interval
+    WHEN INTERVAL '1 day' THEN 'day'
+    WHEN INTERVAL '1 week' THEN 'week'
+    WHEN INTERVAL '1 month' THEN 'month'
+    ELSE c.partition_interval
+  END)::text AS partition_interval,
+  c.premake,
+  coalesce(c.retention, '')::text AS retention,
+  c.retention_keep_table,
+  coalesce((
+    SELECT s.sub_partition_interval::int
+    FROM public.part_config_sub s
+    WHERE s.sub_parent = 'public.telemetry'
+  ), 0)::int AS sub_partition_trips
+FROM public.part_config c
+WHERE c.parent_table = 'public.telemetry';
+
+-- name: UpdateTelemetryRetention :exec
+-- Stores the premake and retention settings of telemetry. An empty retention
+-- keeps all partitions.
+UPDATE public.part_config
+SET
+  premake = sqlc.arg('premake'),
+  retention = nullif(sqlc.arg('retention')::text, ''),
+  retention_keep_table = sqlc.arg('retention_keep_table')
+WHERE parent_table = 'public.telemetry';
+
+-- name: UpsertStops :many
+-- Adds unknown stop names and returns the ids of all given names.
+WITH inserted AS (
+  INSERT INTO stops (name)
+  SELECT unnest(sqlc.arg('names')::text[])
+  ON CONFLICT (name) DO NOTHING
+  RETURNING id, name
+)
+SELECT id, name FROM inserted
+UNION ALL
+SELECT id, name FROM stops
+WHERE name = ANY(sqlc.arg('names')::text[]);
+
+-- name: ListStops :many
+SELECT * FROM stops
+ORDER BY name;
+
+-- name: DeleteTripStops :exec
+DELETE FROM trip_stops WHERE trip_id = sqlc.arg('trip_id');
+
+-- name: RefreshTripStops :exec
+-- Visits of the trip's stops and the mean GNSS fix while the doors were open there.
+-- A visit is a run of consecutive samples reporting the same stop.
+INSERT INTO trip_stops (trip_id, stop_id, visits, door_open_fixes, latitude, longitude, altitude)
+SELECT
+  trip_id,
+  stop_id,
+  count(*) FILTER (WHERE arrival),
+  count(*) FILTER (WHERE door_fix),
+  avg(gnss_latitude) FILTER (WHERE door_fix),
+  avg(gnss_longitude) FILTER (WHERE door_fix),
+  avg(gnss_altitude) FILTER (WHERE door_fix)
+FROM (
+  SELECT
+    trip_id,
+    stop_id,
+    gnss_latitude,
+    gnss_longitude,
+    gnss_altitude,
+    stop_id IS DISTINCT FROM lag(stop_id) OVER (ORDER BY time) AS arrival,
+    coalesce(status_door_is_open, false)
+      AND gnss_latitude IS NOT NULL
+      AND gnss_longitude IS NOT NULL AS door_fix
+  FROM telemetry
+  WHERE trip_id = sqlc.arg('trip_id')
+    AND stop_id IS NOT NULL
+) s
+GROUP BY trip_id, stop_id;
+
+-- name: RefreshTripStopsResampled :exec
+-- RefreshTripStops for trips loaded without raw telemetry, from their windows at resolution_s.
+-- A visit is a run of consecutive windows reporting the same stop. Door open fixes are the
+-- seconds the doors were open in windows with a fix, which also weigh the window positions.
+INSERT INTO trip_stops (trip_id, stop_id, visits, door_open_fixes, latitude, longitude, altitude)
+SELECT
+  trip_id,
+  stop_id,
+  count(*) FILTER (WHERE arrival),
+  round(coalesce(sum(door_s) FILTER (WHERE fix), 0))::int,
+  sum(gnss_latitude * door_s) FILTER (WHERE fix) / nullif(sum(door_s) FILTER (WHERE fix), 0),
+  sum(gnss_longitude * door_s) FILTER (WHERE fix) / nullif(sum(door_s) FILTER (WHERE fix), 0),
+  sum(gnss_altitude * door_s) FILTER (WHERE fix)
+    / nullif(sum(door_s) FILTER (WHERE fix AND gnss_altitude IS NOT NULL), 0)
+FROM (
+  SELECT
+    r.trip_id,
+    st.id AS stop_id,
+    r.gnss_latitude_mean AS gnss_latitude,
+    r.gnss_longitude_mean AS gnss_longitude,
+    r.gnss_altitude_mean AS gnss_altitude,
+    coalesce(r.status_door_is_open_frac, 0) * r.sample_count AS door_s,
+    st.id IS DISTINCT FROM lag(st.id) OVER (ORDER BY r.time) AS arrival,
+    r.gnss_latitude_mean IS NOT NULL AND r.gnss_longitude_mean IS NOT NULL AS fix
+  FROM telemetry_resampled r
+  JOIN stops st ON st.name = r.itcs_stop_name
+  WHERE r.trip_id = sqlc.arg('trip_id')
+    AND r.resolution_s = sqlc.arg('resolution_s')
+) s
+GROUP BY trip_id, stop_id;
+
+-- name: RefreshStopStats :exec
+-- Aggregates trip_stops into stops. The centroid weighs each trip by its door open fixes.
+UPDATE stops s
+SET
+  latitude = a.latitude,
+  longitude = a.longitude,
+  altitude = a.altitude,
+  door_open_fixes = a.door_open_fixes,
+  visits = a.visits,
+  trips = a.trips
+FROM (
+  SELECT
+    st.id,
+    sum(ts.latitude * ts.door_open_fixes) / nullif(sum(ts.door_open_fixes), 0) AS latitude,
+    sum(ts.longitude * ts.door_open_fixes) / nullif(sum(ts.door_open_fixes), 0) AS longitude,
+    sum(ts.altitude * ts.door_open_fixes)
+      / nullif(sum(ts.door_open_fixes) FILTER (WHERE ts.altitude IS NOT NULL), 0) AS altitude,
+    coalesce(sum(ts.door_open_fixes), 0) AS door_open_fixes,
+    coalesce(sum(ts.visits), 0) AS visits,
+    count(ts.trip_id) AS trips
+  FROM stops st
+  LEFT JOIN trip_stops ts ON ts.stop_id = st.id
+  GROUP BY st.id
+) a
+WHERE a.id = s.id;
+
+-- name: DeleteStopEventsByTrip :exec
+DELETE FROM stop_events
+WHERE trip_id = sqlc.arg('trip_id');
+
+-- name: InsertStopEvents :copyfrom
+INSERT INTO stop_events (
+  trip_id,
+  seq,
+  run,
+  stop_id,
+  arrival_time,
+  door_open_time,
+  door_close_time,
+  departure_time,
+  dwell_s,
+  door_open_s,
+  passengers_before,
+  passengers_after,
+  passenger_change
+)
+VALUES (
+  sqlc.arg('trip_id'),
+  sqlc.arg('seq'),
+  sqlc.arg('run'),
+  sqlc.arg('stop_id'),
+  sqlc.arg('arrival_time'),
+  sqlc.arg('door_open_time'),
+  sqlc.arg('door_close_time'),
+  sqlc.arg('departure_time'),
+  sqlc.arg('dwell_s'),
+  sqlc.arg('door_open_s'),
+  sqlc.arg('passengers_before'),
+  sqlc.arg('passengers_after'),
+  sqlc.arg('passenger_change')
+);
+
+-- name: ListStopEventsByTrip :many
+SELECT * FROM stop_events
+WHERE trip_id = sqlc.arg('trip_id')
+ORDER BY seq;
+
+-- name: ListStopEventsByRoute :many
+SELECT stop_events.* FROM stop_events
+JOIN trips ON trips.id = stop_events.trip_id
+WHERE trips.route_id = sqlc.arg('route_id')
+  AND stop_events.arrival_time >= sqlc.arg('start_time')
+  AND stop_events.arrival_time < sqlc.arg('end_time')
+ORDER BY stop_events.arrival_time;
+
+-- name: ListStopEventsByStop :many
+SELECT * FROM stop_events
+WHERE stop_id = sqlc.arg('stop_id')
+  AND arrival_time >= sqlc.arg('start_time')
+  AND arrival_time < sqlc.arg('end_time')
+ORDER BY arrival_time;
+
+-- name: DeleteTripSegmentsByTrip :exec
+DELETE FROM trip_segments
+WHERE trip_id = sqlc.arg('trip_id');
+
+-- name: InsertTripSegments :copyfrom
+INSERT INTO trip_segments (
+  trip_id,
+  seq,
+  phase,
+  start_time,
+  end_time,
+  duration_s,
+  energy_kwh,
+  distance_km
+)
+VALUES (
+  sqlc.arg('trip_id'),
+  sqlc.arg('seq'),
+  sqlc.arg('phase'),
+  sqlc.arg('start_time'),
+  sqlc.arg('end_time'),
+  sqlc.arg('duration_s'),
+  sqlc.arg('energy_kwh'),
+  sqlc.arg('distance_km')
+);
+
+-- name: ListTripSegmentsByTrip :many
+SELECT * FROM trip_segments
+WHERE trip_id = sqlc.arg('trip_id')
+ORDER BY seq;
+
+-- name: ListTripSegmentsByPhase :many
+SELECT * FROM trip_segments
+WHERE phase = sqlc.arg('phase')
+  AND start_time >= sqlc.arg('start_time')
+  AND start_time < sqlc.arg('end_time')
+ORDER BY start_time;
+
+-- name: SummariseTripSegments :many
+-- Time, energy and distance per phase over the segments starting in a time range.
+SELECT
+  phase,
+  count(*)::int AS segments,
+  count(DISTINCT trip_id)::int AS trips,
+  sum(duration_s)::bigint AS duration_s,
+  sum(energy_kwh)::float8 AS energy_kwh,
+  sum(distance_km)::float8 AS distance_km
+FROM trip_segments
+WHERE start_time >= sqlc.arg('start_time')
+  AND start_time < sqlc.arg('end_time')
+GROUP BY phase
+ORDER BY phase;
+
+-- name: UpsertTripEnergy :exec
+INSERT INTO trip_energy (
+  trip_id,
+  energy_kwh,
+  consumed_kwh,
+  regenerated_kwh,
+  traction_kwh,
+  auxiliary_kwh,
+  distance_km,
+  kwh_per_km
+)
+VALUES (
+  sqlc.arg('trip_id'),
+  sqlc.arg('energy_kwh'),
+  sqlc.arg('consumed_kwh'),
+  sqlc.arg('regenerated_kwh'),
+  sqlc.arg('traction_kwh'),
+  sqlc.arg('auxiliary_kwh'),
+  sqlc.arg('distance_km'),
+  sqlc.arg('kwh_per_km')
+)
+ON CONFLICT (trip_id) DO UPDATE
+SET
+  energy_kwh = EXCLUDED.energy_kwh,
+  consumed_kwh = EXCLUDED.consumed_kwh,
+  regenerated_kwh = EXCLUDED.regenerated_kwh,
+  traction_kwh = EXCLUDED.traction_kwh,
+  auxiliary_kwh = EXCLUDED.auxiliary_kwh,
+  distance_km = EXCLUDED.distance_km,
+  kwh_per_km = EXCLUDED.kwh_per_km,
+  computed_at = now();
+
+-- name: GetTripEnergy :one
+-- The trip's energy features next to the metadata energy, which trips stores in J.
+SELECT
+  trips.name,
+  (trips.energy_consumption_kwh / 3600000.0)::float8 AS metadata_energy_kwh,
+  trip_energy.*
+FROM trip_energy
+JOIN trips ON trips.id = trip_energy.trip_id
+WHERE trip_energy.trip_id = sqlc.arg('trip_id');
+
+-- name: ListTripEnergy :many
+-- Energy features of the trips starting in a time range, see GetTripEnergy.
+SELECT
+  trips.name,
+  (trips.energy_consumption_kwh / 3600000.0)::float8 AS metadata_energy_kwh,
+  trip_energy.*
+FROM trip_energy
+JOIN trips ON trips.id = trip_energy.trip_id
+WHERE trips.start_time >= sqlc.arg('start_time')
+  AND trips.start_time < sqlc.arg('end_time')
+ORDER BY trips.start_time;
+
+-- name: GetTripTrack :many
+-- The samples of a trip needed for map matching.
+SELECT time, gnss_latitude, gnss_longitude, gnss_course, odometry_vehicle_speed
+FROM telemetry
+WHERE trip_id = sqlc.arg('trip_id')
+ORDER BY time;
+
+-- name: UpsertRouteShape :exec
+INSERT INTO route_shapes (route_id, latitudes, longitudes, distances_m, length_m, trips)
+VALUES (
+  sqlc.arg('route_id'),
+  sqlc.arg('latitudes'),
+  sqlc.arg('longitudes'),
+  sqlc.arg('distances_m'),
+  sqlc.arg('length_m'),
+  sqlc.arg('trips')
+)
+ON CONFLICT (route_id) DO UPDATE
+SET
+  latitudes = EXCLUDED.latitudes,
+  longitudes = EXCLUDED.longitudes,
+  distances_m = EXCLUDED.distances_m,
+  length_m = EXCLUDED.length_m,
+  trips = EXCLUDED.trips,
+  built_at = now();
+
+-- name: GetRouteShape :one
+SELECT * FROM route_shapes
+WHERE route_id = sqlc.arg('route_id');
+
+-- name: DeleteRoutePositionsByTrip :exec
+DELETE FROM telemetry_route_positions
+WHERE trip_id = sqlc.arg('trip_id');
+
+-- name: InsertRoutePositions :copyfrom
+INSERT INTO telemetry_route_positions (
+  trip_id,
+  time,
+  route_distance_m,
+  route_offset_m,
+  route_position_estimated
+)
+VALUES (
+  sqlc.arg('trip_id'),
+  sqlc.arg('time'),
+  sqlc.arg('route_distance_m'),
+  sqlc.arg('route_offset_m'),
+  sqlc.arg('route_position_estimated')
+);
+
+-- name: GetRoutePositionsByTrip :many
+SELECT time, route_distance_m, route_offset_m, route_position_estimated
+FROM telemetry_route_positions
+WHERE trip_id = sqlc.arg('trip_id')
+ORDER BY time;
+
+-- name: ClearGtfs :exec
+TRUNCATE gtfs_stop_times, gtfs_trips, gtfs_calendar_dates, gtfs_calendar, gtfs_stops, gtfs_routes;
+
+-- name: InsertGtfsRoutes :copyfrom
+INSERT INTO gtfs_routes (id, agency_id, short_name, long_name, route_type, bus_route_id)
+VALUES (
+  sqlc.arg('id'),
+  sqlc.arg('agency_id'),
+  sqlc.arg('short_name'),
+  sqlc.arg('long_name'),
+  sqlc.arg('route_type'),
+  sqlc.arg('bus_route_id')
+);
+
+-- name: InsertGtfsStops :copyfrom
+INSERT INTO gtfs_stops (id, name, latitude, longitude, location_type, parent_station, stop_id)
+VALUES (
+  sqlc.arg('id'),
+  sqlc.arg('name'),
+  sqlc.arg('latitude'),
+  sqlc.arg('longitude'),
+  sqlc.arg('location_type'),
+  sqlc.arg('parent_station'),
+  sqlc.arg('stop_id')
+);
+
+-- name: InsertGtfsTrips :copyfrom
+INSERT INTO gtfs_trips (id, gtfs_route_id, service_id, headsign, direction_id)
+VALUES (
+  sqlc.arg('id'),
+  sqlc.arg('gtfs_route_id'),
+  sqlc.arg('service_id'),
+  sqlc.arg('headsign'),
+  sqlc.arg('direction_id')
+);
+
+-- name: InsertGtfsStopTimes :copyfrom
+INSERT INTO gtfs_stop_times (gtfs_trip_id, stop_sequence, gtfs_stop_id, arrival_s, departure_s)
+VALUES (
+  sqlc.arg('gtfs_trip_id'),
+  sqlc.arg('stop_sequence'),
+  sqlc.arg('gtfs_stop_id'),
+  sqlc.arg('arrival_s'),
+  sqlc.arg('departure_s')
+);
+
+-- name: InsertGtfsCalendar :copyfrom
+INSERT INTO gtfs_calendar (
+  service_id,
+  monday,
+  tuesday,
+  wednesday,
+  thursday,
+  friday,
+  saturday,
+  sunday,
+  start_date,
+  end_date
+) VALUES (
+  sqlc.arg('service_id'),
+  sqlc.arg('monday'),
+  sqlc.arg('tuesday'),
+  sqlc.arg('wednesday'),
+  sqlc.arg('thursday'),
+  sqlc.arg('friday'),
+  sqlc.arg('saturday'),
+  sqlc.arg('sunday'),
+  sqlc.arg('start_date'),
+  sqlc.arg('end_date')
+);
+
+-- name: InsertGtfsCalendarDates :copyfrom
+INSERT INTO gtfs_calendar_dates (service_id, date, exception_type)
+VALUES (sqlc.arg('service_id'), sqlc.arg('date'), sqlc.arg('exception_type'));
+
+-- name: ListStopEventDelaysByTrip :many
+SELECT * FROM stop_event_delays
+WHERE trip_id = sqlc.arg('trip_id')
+ORDER BY seq;
+
+-- name: SummariseStopDelays :many
+-- Delay per stop of a route over the stop events arriving in a time range. Stops without any
+-- scheduled departure matched are left out.
+SELECT
+  d.stop_id,
+  stops.name,
+  count(*)::int AS events,
+  count(d.departure_delay_s)::int AS matched,
+  avg(d.arrival_delay_s)::float8 AS mean_arrival_delay_s,
+  avg(d.departure_delay_s)::float8 AS mean_departure_delay_s,
+  percentile_cont(0.5) WITHIN GROUP (ORDER BY d.departure_delay_s)::float8
+    AS median_departure_delay_s,
+  percentile_cont(0.9) WITHIN GROUP (ORDER BY d.departure_delay_s)::float8
+    AS p90_departure_delay_s
+FROM stop_event_delays d
+JOIN trips ON trips.id = d.trip_id
+JOIN stops ON stops.id = d.stop_id
+WHERE trips.route_id = sqlc.arg('route_id')
+  AND d.arrival_time >= sqlc.arg('start_time')
+  AND d.arrival_time < sqlc.arg('end_time')
+GROUP BY d.stop_id, stops.name
+HAVING count(d.departure_delay_s) > 0
+ORDER BY stops.name;
+
+-- name: UpsertContextSource :exec
+INSERT INTO context_sources (name, file, value_columns, observations, first_time, last_time)
+VALUES (
+  sqlc.arg('name'),
+  sqlc.arg('file'),
+  sqlc.arg('value_columns'),
+  sqlc.arg('observations'),
+  sqlc.arg('first_time'),
+  sqlc.arg('last_time')
+)
+ON CONFLICT (name) DO UPDATE SET
+  file = EXCLUDED.file,
+  value_columns = EXCLUDED.value_columns,
+  observations = EXCLUDED.observations,
+  first_time = EXCLUDED.first_time,
+  last_time = EXCLUDED.last_time,
+  imported_at = now();
+
+-- name: ListContextSources :many
+SELECT * FROM context_sources
+ORDER BY name;
+
+-- name: DeleteContextObservations :exec
+DELETE FROM context_observations
+WHERE source = sqlc.arg('source');
+
+-- name: InsertContextObservations :copyfrom
+INSERT INTO context_observations (source, time, data)
+VALUES (sqlc.arg('source'), sqlc.arg('time'), sqlc.arg('data'));
+
+-- name: GetTripTelemetryContext :many
+-- The latest observation of a source at or before every sample of a trip, and no older than
+-- max_age. Samples without such an observation get NULL.
+SELECT t.time, c.time AS context_time, c.data
+FROM telemetry t
+LEFT JOIN LATERAL (
+  SELECT o.time, o.data
+  FROM context_observations o
+  WHERE o.source = sqlc.arg('source')
+    AND o.time <= t.time
+    AND o.time > t.time - sqlc.arg('max_age')::interval
+  ORDER BY o.time DESC
+  LIMIT 1
+) c ON true
+WHERE t.trip_id = sqlc.arg('trip_id')
+ORDER BY t.time;
+
+-- name: ListTripContext :many
+-- The latest observation of a source at or before the start of every trip starting in a time
+-- range, and no older than max_age. Trips without such an observation get NULL.
+SELECT trips.id, trips.name, trips.start_time, c.time AS context_time, c.data
+FROM trips
+LEFT JOIN LATERAL (
+  SELECT o.time, o.data
+  FROM context_observations o
+  WHERE o.source = sqlc.arg('source')
+    AND o.time <= trips.start_time
+    AND o.time > trips.start_time - sqlc.arg('max_age')::interval
+  ORDER BY o.time DESC
+  LIMIT 1
+) c ON true
+WHERE trips.start_time >= sqlc.arg('start_time')
+  AND trips.start_time < sqlc.arg('end_time')
+ORDER BY trips.start_time;
@@ -0,0 +1,283 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// dependentView is a view on telemetry that has to be recreated with the table
+type dependentView struct {
+	Name         string
+	Materialized bool
+	Definition   string
+}
+
+// dependentIndex is a secondary telemetry index recreated with the table
+type dependentIndex struct {
+	Name       string
+	Definition string
+}
+
+// RepartitionTelemetry rebuilds the telemetry table with a new partitioning
+// scheme. A partitioned table cannot change its partitions in place, so the
+// old table is renamed, a new one is created under pg_partman and the rows are
+// copied across in a single transaction.
+func RepartitionTelemetry(ctx context.Context, pool *pgxpool.Pool, c PartitionConfig) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	exec := func(sql string, args ...any) error {
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return fmt.Errorf("%v, while running: %s", err, sql)
+		}
+		return nil
+	}
+
+	// views bind to the table itself, keep their definitions before renaming it
+	rows, err := tx.Query(ctx, `
+SELECT DISTINCT v.relname::text, v.relkind = 'm', pg_get_viewdef(v.oid)
+FROM pg_depend d
+JOIN pg_rewrite r ON r.oid = d.objid
+JOIN pg_class v ON v.oid = r.ev_class
+WHERE d.refobjid = 'public.telemetry'::regclass
+  AND v.oid <> 'public.telemetry'::regclass`)
+	if err != nil {
+		return fmt.Errorf("could not list dependent views: %v", err)
+	}
+	views, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (dependentView, error) {
+		var v dependentView
+		err := row.Scan(&v.Name, &v.Materialized, &v.Definition)
+		return v, err
+	})
+	if err != nil {
+		return fmt.Errorf("could not list dependent views: %v", err)
+	}
+	for _, v := range views {
+		kind := "VIEW"
+		if v.Materialized {
+			kind = "MATERIALIZED VIEW"
+		}
+		err := exec(fmt.Sprintf("DROP %s %s", kind, pgx.Identifier{v.Name}.Sanitize()))
+		if err != nil {
+			return err
+		}
+	}
+
+	// secondary indexes beyond idx_telemetry_time (e.g. the PostGIS one) are
+	// recreated on the new table from their definitions
+	rows, err = tx.Query(ctx, `
+SELECT c.relname::text, replace(pg_get_indexdef(i.indexrelid), ' ON ONLY ', ' ON ')
+FROM pg_index i
+JOIN pg_class c ON c.oid = i.indexrelid
+WHERE i.indrelid = 'public.telemetry'::regclass
+  AND c.relname <> 'idx_telemetry_time'
+  AND NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conindid = i.indexrelid)`)
+	if err != nil {
+		return fmt.Errorf("could not list telemetry indexes: %v", err)
+	}
+	indexes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (dependentIndex, error) {
+		var idx dependentIndex
+		err := row.Scan(&idx.Name, &idx.Definition)
+		return idx, err
+	})
+	if err != nil {
+		return fmt.Errorf("could not list telemetry indexes: %v", err)
+	}
+	for _, idx := range indexes {
+		if err := exec("DROP INDEX " + pgx.Identifier{idx.Name}.Sanitize()); err != nil {
+			return err
+		}
+	}
+
+	// generated columns are computed again on insert and cannot be copied
+	var columns string
+	err = tx.QueryRow(ctx, `
+SELECT string_agg(quote_ident(attname), ', ' ORDER BY attnum)
+FROM pg_attribute
+WHERE attrelid = 'public.telemetry'::regclass
+  AND attnum > 0
+  AND NOT attisdropped
+  AND attgenerated = ''`).Scan(&columns)
+	if err != nil {
+		return fmt.Errorf("could not list telemetry columns: %v", err)
+	}
+
+	var start, end pgtype.Timestamptz
+	err = tx.QueryRow(ctx, "SELECT min(time), max(time) FROM telemetry").Scan(&start, &end)
+	if err != nil {
+		return fmt.Errorf("could not read telemetry time range: %v", err)
+	}
+
+	// detach the old table and all its partitions from pg_partman
+	steps := []string{
+		`DELETE FROM public.part_config_sub
+WHERE sub_parent = 'public.telemetry' OR sub_parent LIKE 'public.telemetry\_p%'`,
+		`DELETE FROM public.part_config
+WHERE parent_table = 'public.telemetry' OR parent_table LIKE 'public.telemetry\_p%'`,
+		`ALTER TABLE telemetry RENAME TO telemetry_legacy`,
+		`ALTER TABLE telemetry_legacy RENAME CONSTRAINT telemetry_pkey TO telemetry_legacy_pkey`,
+		`ALTER TABLE telemetry_legacy
+  RENAME CONSTRAINT telemetry_trip_id_time_key TO telemetry_legacy_trip_id_time_key`,
+		`ALTER INDEX IF EXISTS idx_telemetry_time RENAME TO idx_telemetry_legacy_time`,
+		`DO $$
+DECLARE
+  r record;
+BEGIN
+  FOR r IN
+    SELECT relname FROM pg_class
+    WHERE (relname = 'template_public_telemetry' OR relname LIKE 'template\_public\_telemetry\_p%')
+      AND relkind IN ('r', 'p')
+  LOOP
+    EXECUTE format('DROP TABLE %I', r.relname);
+  END LOOP;
+  FOR r IN
+    WITH RECURSIVE children AS (
+      SELECT inhrelid FROM pg_inherits WHERE inhparent = 'telemetry_legacy'::regclass
+      UNION ALL
+      SELECT i.inhrelid FROM pg_inherits i JOIN children c ON i.inhparent = c.inhrelid
+    )
+    SELECT relname FROM pg_class WHERE oid IN (SELECT inhrelid FROM children)
+  LOOP
+    EXECUTE format('ALTER TABLE %I RENAME TO %I', r.relname, 'legacy_' || r.relname);
+  END LOOP;
+END $$`,
+		`CREATE TABLE telemetry (
+  LIKE telemetry_legacy INCLUDING DEFAULTS INCLUDING GENERATED INCLUDING STORAGE
+)
+PARTITION BY RANGE (time)`,
+		`ALTER TABLE telemetry
+  ADD FOREIGN KEY (trip_id) REFERENCES trips(id) ON DELETE CASCADE,
+  ADD FOREIGN KEY (itcs_bus_route_id) REFERENCES bus_routes(id) ON DELETE CASCADE,
+  ADD FOREIGN KEY (stop_id) REFERENCES stops(id),
+  ADD CONSTRAINT telemetry_trip_id_time_key UNIQUE (trip_id, time)`,
+		`CREATE INDEX idx_telemetry_time ON telemetry(time)`,
+	}
+	// unique constraints must include every partition column
+	if c.SubPartitionTrips > 0 {
+		steps = append(steps, `ALTER TABLE telemetry ADD PRIMARY KEY (id, time, trip_id)`)
+	} else {
+		steps = append(steps, `ALTER TABLE telemetry ADD PRIMARY KEY (id, time)`)
+	}
+	for _, step := range steps {
+		if err := exec(step); err != nil {
+			return err
+		}
+	}
+
+	err = exec(`SELECT public.create_parent(
+    p_parent_table => 'public.telemetry',
+    p_control => 'time',
+    p_type => 'range',
+    p_interval => $1,
+    p_premake => $2
+)`, PartitionIntervals[c.Interval], c.Premake)
+	if err != nil {
+		return err
+	}
+	if c.SubPartitionTrips > 0 {
+		err = exec(`SELECT public.create_sub_parent(
+    p_top_parent => 'public.telemetry',
+    p_control => 'trip_id',
+    p_interval => $1,
+    p_type => 'range',
+    p_declarative_check => 'yes'
+)`, fmt.Sprint(c.SubPartitionTrips))
+		if err != nil {
+			return err
+		}
+	}
+
+	if start.Valid {
+		_, err = New(tx).CreateTelemetryPartitions(ctx, CreateTelemetryPartitionsParams{
+			Unit:      c.Interval,
+			StartTime: start,
+			EndTime:   end,
+			Step:      PartitionIntervals[c.Interval],
+		})
+		if err != nil {
+			return fmt.Errorf("could not create telemetry partitions: %v", err)
+		}
+	}
+
+	tag, err := tx.Exec(ctx, fmt.Sprintf(
+		"INSERT INTO telemetry (%s) SELECT %s FROM telemetry_legacy",
+		columns,
+		columns,
+	))
+	if err != nil {
+		return fmt.Errorf("could not copy telemetry: %v", err)
+	}
+	slog.Info("copied telemetry into the new partitions", "rows", tag.RowsAffected())
+
+	steps = []string{
+		// the id sequence outlives the old table
+		`ALTER SEQUENCE telemetry_id_seq OWNED BY telemetry.id`,
+		`DROP TABLE telemetry_legacy`,
+	}
+	for _, idx := range indexes {
+		steps = append(steps, idx.Definition)
+	}
+	for _, v := range views {
+		kind := "VIEW"
+		if v.Materialized {
+			kind = "MATERIALIZED VIEW"
+		}
+		steps = append(steps, fmt.Sprintf(
+			"CREATE %s %s AS %s",
+			kind,
+			pgx.Identifier{v.Name}.Sanitize(),
+			v.Definition,
+		))
+	}
+	for _, step := range steps {
+		if err := exec(step); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit transaction: %v", err)
+	}
+
+	// rows beyond the premade trip id ranges sit in each sub-partition's
+	// default, partition_data_proc commits as it goes so it runs after the copy
+	if c.SubPartitionTrips > 0 {
+		parents, err := New(pool).ListTelemetrySubParents(ctx)
+		if err != nil {
+			return fmt.Errorf("could not list telemetry sub-partitions: %v", err)
+		}
+		for _, parent := range parents {
+			_, err := pool.Exec(
+				ctx,
+				"CALL public.partition_data_proc($1, p_wait => 0, p_quiet => true)",
+				parent,
+			)
+			if err != nil {
+				return fmt.Errorf("could not partition %s by trip: %v", parent, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyRetention stores the premake and retention settings, which pg_partman
+// applies during maintenance without rebuilding the table
+func ApplyRetention(ctx context.Context, pool *pgxpool.Pool, c PartitionConfig) error {
+	err := New(pool).UpdateTelemetryRetention(ctx, UpdateTelemetryRetentionParams{
+		Premake:            int32(c.Premake),
+		Retention:          c.Retention,
+		RetentionKeepTable: c.RetentionKeepTable,
+	})
+	if err != nil {
+		return fmt.Errorf("could not update partition config: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// refreshTripRollups rebuilds the per-minute and per-trip rollups of a trip
+func refreshTripRollups(ctx context.Context, pool *pgxpool.Pool, tripID int32) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := New(tx)
+
+	if err := qtx.DeleteTelemetryMinuteRollups(ctx, tripID); err != nil {
+		return fmt.Errorf("could not clear minute rollups: %v", err)
+	}
+	if err := qtx.RefreshTelemetryMinuteRollups(ctx, tripID); err != nil {
+		return fmt.Errorf("could not refresh minute rollups: %v", err)
+	}
+	if err := qtx.RefreshTripRollup(ctx, tripID); err != nil {
+		return fmt.Errorf("could not refresh trip rollup: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit transaction: %v", err)
+	}
+	return nil
+}
+
+// RefreshRollups incrementally maintains the rollup tables. The trips loaded in
+// this run are always refreshed, together with any trip in the database that
+// does not have a rollup yet (e.g. loaded before rollups were enabled).
+func RefreshRollups(ctx context.Context, pool *pgxpool.Pool, loaded []int32) (int, error) {
+	missing, err := New(pool).ListTripsWithoutRollup(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("could not list trips without rollups: %v", err)
+	}
+
+	tripIDs := slices.Concat(loaded, missing)
+	slices.Sort(tripIDs)
+	tripIDs = slices.Compact(tripIDs)
+
+	slog.Info("refreshing rollups", "trips", len(tripIDs), "missing", len(missing))
+	for _, tripID := range tripIDs {
+		if err := refreshTripRollups(ctx, pool, tripID); err != nil {
+			return 0, fmt.Errorf("trip %d: %w", tripID, err)
+		}
+	}
+	return len(tripIDs), nil
+}
@@ -0,0 +1,21 @@
+-- The pg_partman configuration tables read by query.sql. pg_partman creates
+-- them with its extension, this file only tells sqlc their column types and is
+-- never applied to a database.
+CREATE TABLE public.part_config (
+  parent_table text NOT NULL PRIMARY KEY,
+  control text NOT NULL,
+  partition_interval text NOT NULL,
+  partition_type text NOT NULL,
+  premake int NOT NULL DEFAULT 4,
+  retention text,
+  retention_keep_table boolean NOT NULL DEFAULT true,
+  infinite_time_partitions boolean NOT NULL DEFAULT false
+);
+
+CREATE TABLE public.part_config_sub (
+  sub_parent text NOT NULL PRIMARY KEY,
+  sub_control text NOT NULL,
+  sub_partition_interval text NOT NULL,
+  sub_partition_type text NOT NULL,
+  sub_premake int NOT NULL DEFAULT 4
+);
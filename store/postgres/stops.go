@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// StopIDs maps ITCS stop names to their row in the stops table
+type StopIDs map[string]int32
+
+// ID returns the stop id of a sample's stop name, if it has one
+func (s StopIDs) ID(name *string) pgtype.Int4 {
+	if name == nil {
+		return pgtype.Int4{}
+	}
+	id, ok := s[*name]
+	return pgtype.Int4{Int32: id, Valid: ok}
+}
+
+// ResolveStops adds the trip's stop names to the stops table and returns their ids
+func ResolveStops(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	records []ztbus.TripTelemetry,
+) (StopIDs, error) {
+	var names []string
+	for _, r := range records {
+		if r.ItcsStopName != nil {
+			names = append(names, *r.ItcsStopName)
+		}
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	ids := make(StopIDs, len(names))
+	if len(names) == 0 {
+		return ids, nil
+	}
+	rows, err := New(pool).UpsertStops(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("could not upsert stops: %v", err)
+	}
+	for _, row := range rows {
+		ids[row.Name] = row.ID
+	}
+	return ids, nil
+}
+
+// refreshStopVisits rebuilds the stop visits of a trip, from its raw telemetry
+// or, if resolutionS is set, from its resampled windows
+func refreshStopVisits(ctx context.Context, pool *pgxpool.Pool, tripID, resolutionS int32) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := New(tx)
+
+	if err := qtx.DeleteTripStops(ctx, tripID); err != nil {
+		return fmt.Errorf("could not clear trip stops: %v", err)
+	}
+	if resolutionS > 0 {
+		err = qtx.RefreshTripStopsResampled(ctx, RefreshTripStopsResampledParams{
+			TripID:      tripID,
+			ResolutionS: resolutionS,
+		})
+	} else {
+		err = qtx.RefreshTripStops(ctx, tripID)
+	}
+	if err != nil {
+		return fmt.Errorf("could not refresh trip stops: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit transaction: %v", err)
+	}
+	return nil
+}
+
+// RefreshStops updates the stop visits of the loaded trips and then the
+// positions and visit counts of all stops. Trips loaded without raw telemetry
+// pass the resolution of their telemetry_resampled windows, others 0.
+func RefreshStops(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	loaded []int32,
+	resolutionS int32,
+) (int, error) {
+	for _, tripID := range loaded {
+		if err := refreshStopVisits(ctx, pool, tripID, resolutionS); err != nil {
+			return 0, fmt.Errorf("trip %d: %w", tripID, err)
+		}
+	}
+
+	q := New(pool)
+	if err := q.RefreshStopStats(ctx); err != nil {
+		return 0, fmt.Errorf("could not refresh stop statistics: %v", err)
+	}
+	stops, err := q.ListStops(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("could not list stops: %v", err)
+	}
+	slog.Info("refreshed stops", "trips", len(loaded), "stops", len(stops))
+	return len(stops), nil
+}
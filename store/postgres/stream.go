@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"iter"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Streaming variants of the telemetry queries. The generated functions collect
+// every row into a slice, these hand rows to the caller as they are read from
+// the connection. Stopping the iteration early closes the rows.
+
+// streamRows runs a query returning whole rows of T in column order
+func streamRows[T any](ctx context.Context, db DBTX, sql string, args ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		rows, err := db.Query(ctx, sql, args...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+		for rows.Next() {
+			i, err := pgx.RowToStructByPos[T](rows)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(i, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// StreamTelemetryByTrip is GetTelemetryByTrip without collecting the rows
+func (q *Queries) StreamTelemetryByTrip(ctx context.Context, tripID int32) iter.Seq2[TelemetryWithRoute, error] {
+	return streamRows[TelemetryWithRoute](ctx, q.db, getTelemetryByTrip, tripID)
+}
+
+// StreamTelemetryInRange is ListTelemetryInRange without collecting the rows
+func (q *Queries) StreamTelemetryInRange(
+	ctx context.Context,
+	arg ListTelemetryInRangeParams,
+) iter.Seq2[TelemetryWithRoute, error] {
+	return streamRows[TelemetryWithRoute](ctx, q.db, listTelemetryInRange, arg.TripID, arg.StartTime, arg.EndTime)
+}
+
+// StreamTelemetryPages walks ListTelemetryPage from the cursor in arg to the
+// end of the range, holding one page in memory at a time. Unlike a single
+// long query it does not keep a connection busy between pages.
+func (q *Queries) StreamTelemetryPages(
+	ctx context.Context,
+	arg ListTelemetryPageParams,
+) iter.Seq2[TelemetryWithRoute, error] {
+	return func(yield func(TelemetryWithRoute, error) bool) {
+		for {
+			page, err := q.ListTelemetryPage(ctx, arg)
+			if err != nil {
+				yield(TelemetryWithRoute{}, err)
+				return
+			}
+			for _, t := range page {
+				if !yield(t, nil) {
+					return
+				}
+			}
+			if len(page) < int(arg.PageSize) {
+				return
+			}
+			last := page[len(page)-1]
+			arg.AfterTripID, arg.AfterTime = last.TripID, last.Time
+		}
+	}
+}
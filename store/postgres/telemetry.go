@@ -0,0 +1,325 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Predixus/orca-ztbus-prep/ztbus"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// batch processing config
+const (
+	BatchSize   = 40000 // number of telemetry records per batch
+	WorkerCount = 10    // number of worker goroutines
+	BufferSize  = 50    // channel buffer size
+)
+
+// batch job structure
+type TelemetryBatch struct {
+	TripID       int32
+	Records      []ztbus.TripTelemetry
+	StopIDs      StopIDs
+	BatchID      int
+	TotalBatches int
+}
+
+// helpers for nullable telemetry columns. Required values are NaN when
+// unavailable in the source or nulled as implausible.
+func requiredFloat4(v float64) pgtype.Float4 {
+	return pgtype.Float4{Float32: float32(v), Valid: !math.IsNaN(v)}
+}
+
+func optionalFloat4(v *float64) pgtype.Float4 {
+	if v == nil {
+		return pgtype.Float4{}
+	}
+	return pgtype.Float4{Float32: float32(*v), Valid: true}
+}
+
+func optionalFloat8(v *float64) pgtype.Float8 {
+	if v == nil {
+		return pgtype.Float8{}
+	}
+	return pgtype.Float8{Float64: *v, Valid: true}
+}
+
+func optionalInt4(v *int) pgtype.Int4 {
+	if v == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: int32(*v), Valid: true}
+}
+
+func telemetryWorker(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	jobs <-chan TelemetryBatch,
+	results chan<- error,
+	wg *sync.WaitGroup,
+) {
+	defer wg.Done()
+
+	for batch := range jobs {
+		err := func() error {
+			conn, err := pool.Acquire(ctx)
+			if err != nil {
+				return fmt.Errorf("could not acquire connection: %v", err)
+			}
+			defer conn.Release()
+
+			tx, err := conn.Begin(ctx)
+			if err != nil {
+				return fmt.Errorf("could not start transaction: %v", err)
+			}
+			defer tx.Rollback(ctx)
+			qtx := New(tx).WithTx(tx)
+
+			routeID, err := qtx.GetBusRouteIdFromTripId(ctx, batch.TripID)
+			if err != nil {
+				slog.Error("could not get bus route id", "error", err)
+				return err
+			}
+			telemetryParams := make([]StageTelemetryParams, len(batch.Records))
+
+			for ii, telemRow := range batch.Records {
+				telemetryParams[ii] = StageTelemetryParams{
+					TripID: batch.TripID,
+					Time: pgtype.Timestamptz{
+						Time:  time.Unix(int64(telemRow.TimeUnix), 0),
+						Valid: true,
+					},
+					ElectricPowerDemand:       requiredFloat4(telemRow.ElectricPowerDemand),
+					GnssAltitude:              optionalFloat8(telemRow.GnssAltitude),
+					GnssCourse:                optionalFloat4(telemRow.GnssCourse),
+					GnssLatitude:              optionalFloat8(telemRow.GnssLatitude),
+					GnssLongitude:             optionalFloat8(telemRow.GnssLongitude),
+					ItcsNumberOfPassengers:    optionalInt4(telemRow.ItcsNumberOfPassengers),
+					OdometryArticulationAngle: requiredFloat4(telemRow.OdometryArticulationAngle),
+					OdometrySteeringAngle:     requiredFloat4(telemRow.OdometrySteeringAngle),
+					OdometryVehicleSpeed:      requiredFloat4(telemRow.OdometryVehicleSpeed),
+					OdometryWheelSpeedFl:      requiredFloat4(telemRow.OdometryWheelSpeedFl),
+					OdometryWheelSpeedFr:      requiredFloat4(telemRow.OdometryWheelSpeedFr),
+					OdometryWheelSpeedMl:      requiredFloat4(telemRow.OdometryWheelSpeedMl),
+					OdometryWheelSpeedMr:      requiredFloat4(telemRow.OdometryWheelSpeedMr),
+					OdometryWheelSpeedRl:      requiredFloat4(telemRow.OdometryWheelSpeedRl),
+					OdometryWheelSpeedRr:      requiredFloat4(telemRow.OdometryWheelSpeedRr),
+					StatusDoorIsOpen: pgtype.Bool{
+						Bool:  telemRow.StatusDoorIsOpen,
+						Valid: true,
+					},
+					StatusGridIsAvailable: pgtype.Bool{
+						Bool:  telemRow.StatusGridIsAvailable,
+						Valid: true,
+					},
+					StatusHaltBrakeIsActive: pgtype.Bool{
+						Bool:  telemRow.StatusHaltBrakeIsActive,
+						Valid: true,
+					},
+					StatusParkBrakeIsActive: pgtype.Bool{
+						Bool:  telemRow.StatusParkBrakeIsActive,
+						Valid: true,
+					},
+					StopID:                batch.StopIDs.ID(telemRow.ItcsStopName),
+					TemperatureAmbient:    requiredFloat4(telemRow.TemperatureAmbient),
+					TractionBrakePressure: requiredFloat4(telemRow.TractionBrakePressure),
+					TractionTractionForce: requiredFloat4(telemRow.TractionTractionForce),
+					BusRouteID:            routeID,
+				}
+			}
+
+			// copy into staging and upsert from there, so that retried batches
+			// and reloaded trips replace samples rather than duplicating them
+			if _, err := qtx.StageTelemetry(ctx, telemetryParams); err != nil {
+				return fmt.Errorf("error during COPY FROM: %v", err)
+			}
+			count, err := qtx.UpsertStagedTelemetry(ctx)
+			if err != nil {
+				return fmt.Errorf("could not upsert staged telemetry: %v", err)
+			}
+
+			if err := tx.Commit(ctx); err != nil {
+				return fmt.Errorf("could not commit transaction: %v", err)
+			}
+
+			slog.Debug("written results", "count", count)
+
+			return nil
+		}()
+
+		if err != nil {
+			results <- fmt.Errorf("batch %d/%d failed: %v", batch.BatchID, batch.TotalBatches, err)
+		} else {
+			results <- nil
+			slog.Debug(
+				"Completed telemetry batch",
+				"batch", fmt.Sprintf("%d/%d", batch.BatchID, batch.TotalBatches),
+				"records", len(batch.Records),
+			)
+		}
+	}
+}
+
+// helper function to split telemetry data into batches
+func createTelemetryBatches(
+	tripID int32,
+	stopIDs StopIDs,
+	telemetryData []ztbus.TripTelemetry,
+) []TelemetryBatch {
+	var batches []TelemetryBatch
+	totalBatches := (len(telemetryData) + BatchSize - 1) / BatchSize // ceiling division
+
+	for i := 0; i < len(telemetryData); i += BatchSize {
+		end := i + BatchSize
+		end = min(end, len(telemetryData))
+
+		batch := TelemetryBatch{
+			TripID:       tripID,
+			Records:      telemetryData[i:end],
+			StopIDs:      stopIDs,
+			BatchID:      (i / BatchSize) + 1,
+			TotalBatches: totalBatches,
+		}
+		batches = append(batches, batch)
+	}
+
+	return batches
+}
+
+// WriteTelemetry writes a trip's raw telemetry using a pool of COPY workers
+func WriteTelemetry(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	tripID int32,
+	name string,
+	stopIDs StopIDs,
+	records []ztbus.TripTelemetry,
+) error {
+	// Create batches for parallel processing
+	batches := createTelemetryBatches(tripID, stopIDs, records)
+	slog.Debug(
+		"Processing telemetry data",
+		"trip",
+		name,
+		"total_records",
+		len(records),
+		"batches",
+		len(batches),
+	)
+
+	// Set up worker pool for this trip's telemetry
+	jobs := make(chan TelemetryBatch, BufferSize)
+	results := make(chan error, len(batches))
+	var wg sync.WaitGroup
+
+	// start workers
+	for range WorkerCount {
+		wg.Add(1)
+		go telemetryWorker(ctx, pool, jobs, results, &wg)
+	}
+
+	// send batches to workers
+	go func() {
+		defer close(jobs)
+		for _, batch := range batches {
+			jobs <- batch
+		}
+	}()
+
+	// wait for all workers to finish
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// collect results and check for errors
+	var processingErrors []error
+	for err := range results {
+		if err != nil {
+			processingErrors = append(processingErrors, err)
+		}
+	}
+
+	if len(processingErrors) > 0 {
+		return fmt.Errorf(
+			"errors processing telemetry for trip %s: %v",
+			name,
+			processingErrors[0],
+		)
+	}
+
+	return nil
+}
+
+// helpers to read loaded telemetry back into the ingest representation
+func float4Value(v pgtype.Float4) float64 {
+	if !v.Valid {
+		return math.NaN()
+	}
+	return float64(v.Float32)
+}
+
+func float4Ptr(v pgtype.Float4) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	f := float64(v.Float32)
+	return &f
+}
+
+func float8Ptr(v pgtype.Float8) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	f := v.Float64
+	return &f
+}
+
+func int4Ptr(v pgtype.Int4) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int32)
+	return &i
+}
+
+// TelemetryToTrip converts stored telemetry rows back into TripTelemetry
+func TelemetryToTrip(rows []TelemetryWithRoute, stopNames map[int32]string) []ztbus.TripTelemetry {
+	records := make([]ztbus.TripTelemetry, len(rows))
+	for i, r := range rows {
+		records[i] = ztbus.TripTelemetry{
+			TimeUnix:                  int(r.Time.Time.Unix()),
+			ElectricPowerDemand:       float4Value(r.ElectricPowerDemand),
+			GnssAltitude:              float8Ptr(r.GnssAltitude),
+			GnssCourse:                float4Ptr(r.GnssCourse),
+			GnssLatitude:              float8Ptr(r.GnssLatitude),
+			GnssLongitude:             float8Ptr(r.GnssLongitude),
+			ItcsNumberOfPassengers:    int4Ptr(r.ItcsNumberOfPassengers),
+			OdometryArticulationAngle: float4Value(r.OdometryArticulationAngle),
+			OdometrySteeringAngle:     float4Value(r.OdometrySteeringAngle),
+			OdometryVehicleSpeed:      float4Value(r.OdometryVehicleSpeed),
+			OdometryWheelSpeedFl:      float4Value(r.OdometryWheelSpeedFl),
+			OdometryWheelSpeedFr:      float4Value(r.OdometryWheelSpeedFr),
+			OdometryWheelSpeedMl:      float4Value(r.OdometryWheelSpeedMl),
+			OdometryWheelSpeedMr:      float4Value(r.OdometryWheelSpeedMr),
+			OdometryWheelSpeedRl:      float4Value(r.OdometryWheelSpeedRl),
+			OdometryWheelSpeedRr:      float4Value(r.OdometryWheelSpeedRr),
+			StatusDoorIsOpen:          r.StatusDoorIsOpen.Bool,
+			StatusGridIsAvailable:     r.StatusGridIsAvailable.Bool,
+			StatusHaltBrakeIsActive:   r.StatusHaltBrakeIsActive.Bool,
+			StatusParkBrakeIsActive:   r.StatusParkBrakeIsActive.Bool,
+			TemperatureAmbient:        float4Value(r.TemperatureAmbient),
+			TractionBrakePressure:     float4Value(r.TractionBrakePressure),
+			TractionTractionForce:     float4Value(r.TractionTractionForce),
+		}
+		if name, ok := stopNames[r.StopID.Int32]; ok && r.StopID.Valid {
+			records[i].ItcsStopName = &name
+		}
+	}
+	return records
+}
@@ -1,8 +1,11 @@
-package main
+// Package ztbus reads the ZTBus dataset: metaData.csv, with one row per
+// trip, and the per second telemetry CSV of each trip.
+package ztbus
 
 import (
 	"encoding/csv"
 	"io"
+	"math"
 	"os"
 	"strconv"
 )
@@ -52,24 +55,34 @@ type TripTelemetry struct {
 	TractionTractionForce     float64
 }
 
+// ZTBus marks unavailable samples as NaN, these are returned as nil
 func parseOptionalFloat(s string) (*float64, error) {
 	if s == "" {
 		return nil, nil
 	}
 	f, err := strconv.ParseFloat(s, 64)
-	return &f, err
+	if err != nil {
+		return nil, err
+	}
+	if math.IsNaN(f) {
+		return nil, nil
+	}
+	return &f, nil
 }
 
+// counts such as itcs_numberOfPassengers are stored as floats in the csv files
 func parseOptionalInt(s string) (*int, error) {
-	if s == "" {
-		return nil, nil
+	f, err := parseOptionalFloat(s)
+	if f == nil || err != nil {
+		return nil, err
 	}
-	i, err := strconv.Atoi(s)
-	return &i, err
+	i := int(math.Round(*f))
+	return &i, nil
 }
 
+// ZTBus marks unavailable stop names as "-", these are returned as nil
 func parseOptionalString(s string) (*string, error) {
-	if s == "" {
+	if s == "" || s == "-" {
 		return nil, nil
 	}
 	return &s, nil
@@ -114,7 +127,15 @@ func ParseMetadataCSV(path string) ([]Metadata, error) {
 		}
 
 		parseI := func(s string) int {
-			i, _ := strconv.Atoi(s)
+			i, err := strconv.Atoi(s)
+			if err != nil {
+				// e.g. energyConsumption is a float in joules
+				f := parseF(s)
+				if math.IsNaN(f) {
+					return 0
+				}
+				return int(math.Round(f))
+			}
 			return i
 		}
 